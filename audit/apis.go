@@ -0,0 +1,137 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/graph"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// APISummary is an API Gateway API with its default endpoint, stages, the custom domain names mapped to it, the
+// certificates of those domains and the Route53 names leading to it
+type APISummary struct {
+	Account      string   `json:"account"`
+	Region       string   `json:"region"`
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Protocol     string   `json:"protocol"`
+	EndpointType string   `json:"endpoint_type,omitempty"`
+	Endpoint     string   `json:"endpoint,omitempty"`
+	Stages       []string `json:"stages"`
+	Domains      []string `json:"domains"`
+	Certificates []string `json:"certificates"`
+	DNSNames     []string `json:"dns_names"`
+}
+
+// SummarizeAPIs lists the REST, HTTP and WebSocket APIs of an inventory dumped with apigateway, linked to the custom
+// domain names, ACM certificates and Route53 records of the inventory
+func SummarizeAPIs(inv collector.AWSInventory) []APISummary {
+	summaries := []APISummary{}
+	for region, gateway := range inv.APIGateway {
+		if gateway == nil {
+			continue
+		}
+		for _, api := range gateway.RestAPIs {
+			s := APISummary{Account: inv.Account, Region: region, ID: aws.StringValue(api.Id), Name: aws.StringValue(api.Name),
+				Protocol: "REST", Stages: []string{}}
+			if api.EndpointConfiguration != nil && len(api.EndpointConfiguration.Types) > 0 {
+				s.EndpointType = aws.StringValue(api.EndpointConfiguration.Types[0])
+			}
+			if !aws.BoolValue(api.DisableExecuteApiEndpoint) {
+				s.Endpoint = "https://" + s.ID + ".execute-api." + region + "." + dnsSuffix(region)
+			}
+			for _, stage := range api.Stages {
+				s.Stages = append(s.Stages, aws.StringValue(stage.StageName))
+			}
+			summaries = append(summaries, s)
+		}
+	}
+	for region, gateway := range inv.APIGatewayV2 {
+		if gateway == nil {
+			continue
+		}
+		for _, api := range gateway.APIs {
+			s := APISummary{Account: inv.Account, Region: region, ID: aws.StringValue(api.ApiId), Name: aws.StringValue(api.Name),
+				Protocol: aws.StringValue(api.ProtocolType), EndpointType: "REGIONAL", Stages: []string{}}
+			if !aws.BoolValue(api.DisableExecuteApiEndpoint) {
+				s.Endpoint = aws.StringValue(api.ApiEndpoint)
+			}
+			for _, stage := range api.Stages {
+				s.Stages = append(s.Stages, aws.StringValue(stage.StageName))
+			}
+			summaries = append(summaries, s)
+		}
+	}
+
+	// Certificates are mapped to the graph nodes of the domain names using them
+	certificates := make(map[string][]string)
+	for arn, users := range certificateUsers(inv) {
+		for node := range users {
+			certificates[node] = append(certificates[node], arn)
+		}
+	}
+	g := graph.Build(inv)
+	for i := range summaries {
+		s := &summaries[i]
+		names := make(map[string]bool)
+		certs := make(map[string]bool)
+		s.Domains = []string{}
+		for _, n := range g.Reachable("api:"+s.Region+"/"+s.ID, true).Nodes {
+			switch n.Type {
+			case graph.TypeAPIDomain:
+				s.Domains = append(s.Domains, n.Label)
+				for _, arn := range certificates[n.ID] {
+					certs[arn] = true
+				}
+			case graph.TypeDNSRecord:
+				names[n.Label] = true
+			}
+		}
+		s.Certificates = []string{}
+		for arn := range certs {
+			s.Certificates = append(s.Certificates, arn)
+		}
+		s.DNSNames = []string{}
+		for name := range names {
+			s.DNSNames = append(s.DNSNames, name)
+		}
+		sort.Strings(s.Domains)
+		sort.Strings(s.Certificates)
+		sort.Strings(s.DNSNames)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if !strings.EqualFold(a.Name, b.Name) {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		return a.ID < b.ID
+	})
+	return summaries
+}
+
+// dnsSuffix returns the DNS suffix of the partition of a region, such as amazonaws.com.cn for the China regions
+func dnsSuffix(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.DNSSuffix()
+	}
+	return endpoints.AwsPartition().DNSSuffix()
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testAPIInventory = `{
+	"account": "123456789012",
+	"apigateway": {"us-east-1": {
+		"RestAPIs": [
+			{"Id": "rest1", "Name": "orders", "EndpointConfiguration": {"Types": ["REGIONAL"]}, "Stages": [{"StageName": "prod"}, {"StageName": "dev"}]},
+			{"Id": "rest2", "Name": "internal", "EndpointConfiguration": {"Types": ["PRIVATE"]}, "DisableExecuteApiEndpoint": true}
+		],
+		"DomainNames": [
+			{"DomainName": "api.example.com", "RegionalDomainName": "d-abc.execute-api.us-east-1.amazonaws.com",
+				"RegionalCertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/api",
+				"BasePathMappings": [{"BasePath": "orders", "RestApiId": "rest1", "Stage": "prod"}]}
+		]
+	}, "cn-north-1": {
+		"RestAPIs": [
+			{"Id": "rest3", "Name": "china", "EndpointConfiguration": {"Types": ["REGIONAL"]}}
+		]
+	}},
+	"apigatewayv2": {"eu-west-1": {
+		"APIs": [
+			{"ApiId": "ws1", "Name": "chat", "ProtocolType": "WEBSOCKET", "ApiEndpoint": "wss://ws1.execute-api.eu-west-1.amazonaws.com"}
+		]
+	}},
+	"hostedzones": [{"Id": "/hostedzone/Z1", "Name": "example.com.", "Records": [
+		{"Name": "api.example.com.", "Type": "A", "AliasTarget": {"DNSName": "d-abc.execute-api.us-east-1.amazonaws.com."}}
+	]}]
+}`
+
+func TestSummarizeAPIs(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testAPIInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	apis := SummarizeAPIs(inv)
+
+	expected := []APISummary{
+		{"123456789012", "cn-north-1", "rest3", "china", "REST", "REGIONAL", "https://rest3.execute-api.cn-north-1.amazonaws.com.cn",
+			[]string{}, []string{}, []string{}, []string{}},
+		{"123456789012", "eu-west-1", "ws1", "chat", "WEBSOCKET", "REGIONAL", "wss://ws1.execute-api.eu-west-1.amazonaws.com",
+			[]string{}, []string{}, []string{}, []string{}},
+		{"123456789012", "us-east-1", "rest2", "internal", "REST", "PRIVATE", "",
+			[]string{}, []string{}, []string{}, []string{}},
+		{"123456789012", "us-east-1", "rest1", "orders", "REST", "REGIONAL", "https://rest1.execute-api.us-east-1.amazonaws.com",
+			[]string{"prod", "dev"}, []string{"api.example.com"}, []string{"arn:aws:acm:us-east-1:123456789012:certificate/api"}, []string{"api.example.com."}},
+	}
+	if !reflect.DeepEqual(apis, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, apis)
+	}
+}
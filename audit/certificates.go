@@ -0,0 +1,212 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/graph"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// ExpiringCertificate is an ACM certificate expiring soon, along with the load balancers, CloudFront distributions and
+// API Gateway custom domain names using it and the Route53 names leading to them
+type ExpiringCertificate struct {
+	Account            string    `json:"account"`
+	Region             string    `json:"region"`
+	ARN                string    `json:"arn"`
+	Domain             string    `json:"domain"`
+	NotAfter           time.Time `json:"not_after"`
+	DaysLeft           int       `json:"days_left"`
+	Status             string    `json:"status"`
+	RenewalEligibility string    `json:"renewal_eligibility"`
+	LoadBalancers      []string  `json:"load_balancers"`
+	Distributions      []string  `json:"distributions"`
+	APIDomains         []string  `json:"api_domains"`
+	DNSNames           []string  `json:"dns_names"`
+}
+
+// certificateUser returns the graph node of a load balancer, CloudFront distribution or API Gateway domain name ARN
+func certificateUser(arn string) (string, bool) {
+	// arn:partition:service:region:account:resource
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 {
+		return "", false
+	}
+	switch parts[2] {
+	case "elasticloadbalancing":
+		resource := strings.Split(parts[5], "/")
+		// loadbalancer/name for classic, loadbalancer/app|net/name/id otherwise
+		if len(resource) == 2 && resource[0] == "loadbalancer" {
+			return "elb:" + parts[3] + "/" + resource[1], true
+		}
+		if len(resource) == 4 && resource[0] == "loadbalancer" {
+			return "elbv2:" + parts[3] + "/" + resource[2], true
+		}
+	case "cloudfront":
+		if strings.HasPrefix(parts[5], "distribution/") {
+			return "cloudfront:" + strings.TrimPrefix(parts[5], "distribution/"), true
+		}
+	case "apigateway":
+		// arn:aws:apigateway:region::/domainnames/name
+		if strings.HasPrefix(parts[5], "/domainnames/") {
+			return "apidomain:" + parts[3] + "/" + strings.TrimPrefix(parts[5], "/domainnames/"), true
+		}
+	}
+	return "", false
+}
+
+// certificateUsers maps certificate ARNs to the graph nodes of the load balancers, distributions and API domain names
+// using them, from the ACM in-use-by ARNs and from the listeners, viewer certificates and domain names of the inventory
+func certificateUsers(inv collector.AWSInventory) map[string]map[string]bool {
+	users := make(map[string]map[string]bool)
+	add := func(certificate, node string) {
+		if certificate == "" {
+			return
+		}
+		if users[certificate] == nil {
+			users[certificate] = make(map[string]bool)
+		}
+		users[certificate][node] = true
+	}
+	for _, certificates := range inv.ACM {
+		for _, c := range certificates {
+			for _, arn := range c.InUseBy {
+				if node, ok := certificateUser(aws.StringValue(arn)); ok {
+					add(aws.StringValue(c.CertificateArn), node)
+				}
+			}
+		}
+	}
+	if inv.LoadBalancers != nil {
+		for region, lbs := range inv.LoadBalancers.Classic {
+			for _, lb := range lbs {
+				for _, l := range lb.ListenerDescriptions {
+					if l.Listener != nil {
+						add(aws.StringValue(l.Listener.SSLCertificateId), "elb:"+region+"/"+aws.StringValue(lb.LoadBalancerName))
+					}
+				}
+			}
+		}
+		for region, lbs := range inv.LoadBalancers.ApplicationNetwork {
+			for _, lb := range lbs {
+				for _, l := range lb.Listeners {
+					for _, c := range l.Certificates {
+						add(aws.StringValue(c.CertificateArn), "elbv2:"+region+"/"+aws.StringValue(lb.LoadBalancerName))
+					}
+				}
+			}
+		}
+	}
+	for _, d := range inv.CloudFront {
+		if d.ViewerCertificate != nil {
+			add(aws.StringValue(d.ViewerCertificate.ACMCertificateArn), "cloudfront:"+aws.StringValue(d.Id))
+		}
+	}
+	for region, gateway := range inv.APIGateway {
+		if gateway == nil {
+			continue
+		}
+		for _, d := range gateway.DomainNames {
+			if d.DomainName == nil {
+				continue
+			}
+			node := "apidomain:" + region + "/" + aws.StringValue(d.DomainName.DomainName)
+			add(aws.StringValue(d.CertificateArn), node)
+			add(aws.StringValue(d.RegionalCertificateArn), node)
+		}
+	}
+	for region, gateway := range inv.APIGatewayV2 {
+		if gateway == nil {
+			continue
+		}
+		for _, d := range gateway.DomainNames {
+			if d.DomainName == nil {
+				continue
+			}
+			for _, c := range d.DomainNameConfigurations {
+				add(aws.StringValue(c.CertificateArn), "apidomain:"+region+"/"+aws.StringValue(d.DomainName.DomainName))
+			}
+		}
+	}
+	return users
+}
+
+// FindExpiringCertificates reports the ACM certificates of an inventory expiring within the given duration, or already
+// expired, mapped to the load balancers, distributions and API domain names using them and to the Route53 names
+// pointing at those
+func FindExpiringCertificates(inv collector.AWSInventory, within time.Duration, now time.Time) []ExpiringCertificate {
+	expiring := []ExpiringCertificate{}
+	users := certificateUsers(inv)
+	g := graph.Build(inv)
+
+	for region, certificates := range inv.ACM {
+		for _, c := range certificates {
+			if c.NotAfter == nil || c.NotAfter.After(now.Add(within)) {
+				continue
+			}
+			e := ExpiringCertificate{
+				Account:            inv.Account,
+				Region:             region,
+				ARN:                aws.StringValue(c.CertificateArn),
+				Domain:             aws.StringValue(c.DomainName),
+				NotAfter:           *c.NotAfter,
+				DaysLeft:           int(c.NotAfter.Sub(now).Hours() / 24),
+				Status:             aws.StringValue(c.Status),
+				RenewalEligibility: aws.StringValue(c.RenewalEligibility),
+				LoadBalancers:      []string{},
+				Distributions:      []string{},
+				APIDomains:         []string{},
+				DNSNames:           []string{},
+			}
+			names := make(map[string]bool)
+			for node := range users[e.ARN] {
+				parts := strings.SplitN(node, ":", 2)
+				switch parts[0] {
+				case "cloudfront":
+					e.Distributions = append(e.Distributions, parts[1])
+				case "apidomain":
+					e.APIDomains = append(e.APIDomains, parts[1])
+				default:
+					e.LoadBalancers = append(e.LoadBalancers, parts[1])
+				}
+				if _, ok := g.Nodes[node]; !ok {
+					continue
+				}
+				for _, n := range g.Reachable(node, true).Nodes {
+					if n.Type == graph.TypeDNSRecord {
+						names[n.Label] = true
+					}
+				}
+			}
+			for name := range names {
+				e.DNSNames = append(e.DNSNames, name)
+			}
+			sort.Strings(e.LoadBalancers)
+			sort.Strings(e.Distributions)
+			sort.Strings(e.APIDomains)
+			sort.Strings(e.DNSNames)
+			expiring = append(expiring, e)
+		}
+	}
+	sort.Slice(expiring, func(i, j int) bool {
+		if !expiring[i].NotAfter.Equal(expiring[j].NotAfter) {
+			return expiring[i].NotAfter.Before(expiring[j].NotAfter)
+		}
+		return expiring[i].ARN < expiring[j].ARN
+	})
+	return expiring
+}
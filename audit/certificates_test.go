@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testCertificateInventory = `{
+	"account": "123456789012",
+	"loadbalancer": [
+		{"us-east-1": [{"LoadBalancerName": "legacy", "DNSName": "legacy-1.us-east-1.elb.amazonaws.com",
+			"ListenerDescriptions": [{"Listener": {"Protocol": "HTTPS", "SSLCertificateId": "arn:aws:acm:us-east-1:123456789012:certificate/soon"}}]}]},
+		{"us-east-1": [{"LoadBalancerName": "web", "DNSName": "web-1.us-east-1.elb.amazonaws.com"}]}
+	],
+	"cloudfront": [
+		{"Id": "E1", "DomainName": "d1.cloudfront.net", "ViewerCertificate": {"ACMCertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/expired"}}
+	],
+	"apigateway": {"us-east-1": {"DomainNames": [
+		{"DomainName": "api.example.com", "RegionalDomainName": "d-abc.execute-api.us-east-1.amazonaws.com",
+			"RegionalCertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/api"}
+	]}},
+	"acm": {"us-east-1": [
+		{"CertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/soon", "DomainName": "www.example.com", "NotAfter": "2020-06-10T00:00:00Z",
+			"Status": "ISSUED", "RenewalEligibility": "INELIGIBLE",
+			"InUseBy": ["arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/0123456789abcdef"]},
+		{"CertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/expired", "DomainName": "cdn.example.com", "NotAfter": "2020-05-01T00:00:00Z",
+			"Status": "EXPIRED", "InUseBy": ["arn:aws:cloudfront::123456789012:distribution/E1"]},
+		{"CertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/api", "DomainName": "api.example.com", "NotAfter": "2020-06-20T00:00:00Z",
+			"Status": "ISSUED", "InUseBy": ["arn:aws:apigateway:us-east-1::/domainnames/api.example.com"]},
+		{"CertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/later", "DomainName": "api.example.com", "NotAfter": "2021-01-01T00:00:00Z"},
+		{"CertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/pending", "DomainName": "new.example.com", "Status": "PENDING_VALIDATION"}
+	]},
+	"hostedzones": [{"Id": "/hostedzone/Z1", "Name": "example.com.", "Records": [
+		{"Name": "www.example.com.", "Type": "A", "AliasTarget": {"DNSName": "dualstack.web-1.us-east-1.elb.amazonaws.com."}},
+		{"Name": "old.example.com.", "Type": "CNAME", "ResourceRecords": [{"Value": "legacy-1.us-east-1.elb.amazonaws.com"}]},
+		{"Name": "cdn.example.com.", "Type": "A", "AliasTarget": {"DNSName": "d1.cloudfront.net."}},
+		{"Name": "api.example.com.", "Type": "A", "AliasTarget": {"DNSName": "d-abc.execute-api.us-east-1.amazonaws.com."}}
+	]}]
+}`
+
+func TestFindExpiringCertificates(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testCertificateInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	expiring := FindExpiringCertificates(inv, 30*24*time.Hour, now)
+
+	if len(expiring) != 3 {
+		t.Fatalf("Expected 3 expiring certificates, got %+v", expiring)
+	}
+	expired := expiring[0]
+	if expired.Domain != "cdn.example.com" || expired.DaysLeft != -31 ||
+		!reflect.DeepEqual(expired.Distributions, []string{"E1"}) || !reflect.DeepEqual(expired.DNSNames, []string{"cdn.example.com."}) {
+		t.Errorf("Unexpected expired certificate %+v", expired)
+	}
+	soon := expiring[1]
+	if soon.Domain != "www.example.com" || soon.DaysLeft != 9 ||
+		!reflect.DeepEqual(soon.LoadBalancers, []string{"us-east-1/legacy", "us-east-1/web"}) ||
+		!reflect.DeepEqual(soon.DNSNames, []string{"old.example.com.", "www.example.com."}) {
+		t.Errorf("Unexpected expiring certificate %+v", soon)
+	}
+	api := expiring[2]
+	if !reflect.DeepEqual(api.APIDomains, []string{"us-east-1/api.example.com"}) || !reflect.DeepEqual(api.DNSNames, []string{"api.example.com."}) {
+		t.Errorf("Unexpected API certificate %+v", api)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// kubernetesClusterTag prefixes the tag Kubernetes puts on the nodes it owns or shares
+const kubernetesClusterTag = "kubernetes.io/cluster/"
+
+// ClusterNode is an EC2 instance acting as an EKS or ECS cluster node
+type ClusterNode struct {
+	InstanceID string `json:"instance_id"`
+	Account    string `json:"account"`
+	Region     string `json:"region"`
+	Service    string `json:"service"`
+	Cluster    string `json:"cluster"`
+	NodeGroup  string `json:"node_group,omitempty"`
+}
+
+// ClusterSummary is an EKS or ECS cluster with its number of EC2 nodes.
+// Version and Unsupported are only set for EKS clusters
+type ClusterSummary struct {
+	Service     string `json:"service"`
+	Account     string `json:"account"`
+	Region      string `json:"region"`
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Unsupported bool   `json:"unsupported"`
+	Nodes       int    `json:"nodes"`
+}
+
+// ClusterReport lists the clusters of an inventory and the EC2 instances acting as their nodes
+type ClusterReport struct {
+	Clusters []ClusterSummary `json:"clusters"`
+	Nodes    []ClusterNode    `json:"nodes"`
+}
+
+// FindClusterNodes maps the EC2 instances of an inventory to the EKS clusters named by their tags
+// and to the ECS clusters registering them as container instances. EKS versions are checked against
+// minEKSVersion rather than the support status recorded at dump time
+func FindClusterNodes(inv collector.AWSInventory, minEKSVersion string) ClusterReport {
+	report := ClusterReport{Clusters: []ClusterSummary{}, Nodes: []ClusterNode{}}
+	nodes := make(map[string]int)
+
+	for region, instances := range inv.EC2 {
+		for _, i := range instances {
+			var cluster, nodeGroup string
+			for _, t := range i.Tags {
+				key := aws.StringValue(t.Key)
+				switch {
+				case key == "eks:cluster-name":
+					cluster = aws.StringValue(t.Value)
+				case key == "eks:nodegroup-name":
+					nodeGroup = aws.StringValue(t.Value)
+				case strings.HasPrefix(key, kubernetesClusterTag) && cluster == "":
+					cluster = strings.TrimPrefix(key, kubernetesClusterTag)
+				}
+			}
+			if cluster == "" {
+				continue
+			}
+			report.Nodes = append(report.Nodes, ClusterNode{aws.StringValue(i.InstanceId), inv.Account, region, "eks", cluster, nodeGroup})
+			nodes["eks/"+region+"/"+cluster]++
+		}
+	}
+	for region, clusters := range inv.ECS {
+		for _, c := range clusters {
+			name := aws.StringValue(c.ClusterName)
+			for _, ci := range c.ContainerInstances {
+				if aws.StringValue(ci.Ec2InstanceId) == "" {
+					continue
+				}
+				report.Nodes = append(report.Nodes, ClusterNode{aws.StringValue(ci.Ec2InstanceId), inv.Account, region, "ecs", name, ""})
+				nodes["ecs/"+region+"/"+name]++
+			}
+		}
+	}
+
+	for region, clusters := range inv.EKS {
+		for _, c := range clusters {
+			name := aws.StringValue(c.Name)
+			report.Clusters = append(report.Clusters, ClusterSummary{"eks", inv.Account, region, name,
+				aws.StringValue(c.Version), awslib.UnsupportedEKSVersion(aws.StringValue(c.Version), minEKSVersion), nodes["eks/"+region+"/"+name]})
+		}
+	}
+	for region, clusters := range inv.ECS {
+		for _, c := range clusters {
+			name := aws.StringValue(c.ClusterName)
+			report.Clusters = append(report.Clusters, ClusterSummary{"ecs", inv.Account, region, name, "", false, nodes["ecs/"+region+"/"+name]})
+		}
+	}
+
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		a, b := report.Clusters[i], report.Clusters[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		a, b := report.Nodes[i], report.Nodes[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.InstanceID < b.InstanceID
+	})
+	return report
+}
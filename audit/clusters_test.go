@@ -0,0 +1,84 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testClusterInventory = `{
+	"account": "123456789012",
+	"ec2": {"us-east-1": [
+		{"InstanceId": "i-managed", "Tags": [{"Key": "eks:cluster-name", "Value": "prod"}, {"Key": "eks:nodegroup-name", "Value": "workers"}]},
+		{"InstanceId": "i-self", "Tags": [{"Key": "kubernetes.io/cluster/prod", "Value": "owned"}]},
+		{"InstanceId": "i-ecs"},
+		{"InstanceId": "i-plain", "Tags": [{"Key": "Name", "Value": "bastion"}]}
+	]},
+	"eks": {"us-east-1": [
+		{"Name": "prod", "Version": "1.27", "UnsupportedVersion": true},
+		{"Name": "fargate", "Version": "1.35", "UnsupportedVersion": false}
+	]},
+	"ecs": {"us-east-1": [
+		{"ClusterName": "jobs", "ContainerInstances": [{"Ec2InstanceId": "i-ecs"}]}
+	]}
+}`
+
+func TestFindClusterNodes(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testClusterInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	report := FindClusterNodes(inv, awslib.MinSupportedEKSVersion)
+
+	expectedNodes := []ClusterNode{
+		{"i-ecs", "123456789012", "us-east-1", "ecs", "jobs", ""},
+		{"i-managed", "123456789012", "us-east-1", "eks", "prod", "workers"},
+		{"i-self", "123456789012", "us-east-1", "eks", "prod", ""},
+	}
+	if len(report.Nodes) != len(expectedNodes) {
+		t.Fatalf("Expected %d nodes, got %+v", len(expectedNodes), report.Nodes)
+	}
+	for i, n := range expectedNodes {
+		if report.Nodes[i] != n {
+			t.Errorf("Expected node %+v, got %+v", n, report.Nodes[i])
+		}
+	}
+
+	expectedClusters := []struct {
+		service, name string
+		unsupported   bool
+		nodes         int
+	}{
+		{"ecs", "jobs", false, 1},
+		{"eks", "fargate", false, 0},
+		{"eks", "prod", true, 2},
+	}
+	if len(report.Clusters) != len(expectedClusters) {
+		t.Fatalf("Expected %d clusters, got %+v", len(expectedClusters), report.Clusters)
+	}
+	for i, c := range expectedClusters {
+		got := report.Clusters[i]
+		if got.Service != c.service || got.Name != c.name || got.Unsupported != c.unsupported || got.Nodes != c.nodes {
+			t.Errorf("Expected %+v, got %+v", c, got)
+		}
+	}
+
+	// Versions are checked against the minimum given to the report
+	if fargate := FindClusterNodes(inv, "1.36").Clusters[1]; fargate.Name != "fargate" || !fargate.Unsupported {
+		t.Errorf("Expected fargate to be unsupported below 1.36, got %+v", fargate)
+	}
+}
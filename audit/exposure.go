@@ -0,0 +1,287 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// OpenPermission is an inbound security group rule open to the whole internet.
+// A protocol of -1 means all protocols and ports
+type OpenPermission struct {
+	GroupID  string `json:"group_id"`
+	Protocol string `json:"protocol"`
+	FromPort int64  `json:"from_port"`
+	ToPort   int64  `json:"to_port"`
+	Source   string `json:"source"`
+}
+
+// Exposure is a resource whose security groups accept traffic from 0.0.0.0/0 or ::/0, or an internet-facing
+// network load balancer without security groups.
+// Public tells whether the resource has a public IP, is publicly accessible or internet-facing
+type Exposure struct {
+	Service     string           `json:"service"`
+	Account     string           `json:"account"`
+	Region      string           `json:"region"`
+	ID          string           `json:"id"`
+	Public      bool             `json:"public"`
+	Address     string           `json:"address,omitempty"`
+	Permissions []OpenPermission `json:"permissions"`
+}
+
+// UnusedSecurityGroup is a security group attached to no network interface and referenced by no other group
+type UnusedSecurityGroup struct {
+	Account string `json:"account"`
+	Region  string `json:"region"`
+	GroupID string `json:"group_id"`
+	Name    string `json:"name"`
+	VpcID   string `json:"vpc_id,omitempty"`
+}
+
+// SharedSnapshot is a manual RDS snapshot restorable by anyone or by other accounts
+type SharedSnapshot struct {
+	Account    string   `json:"account"`
+	Region     string   `json:"region"`
+	ID         string   `json:"id"`
+	Cluster    bool     `json:"cluster"`
+	Public     bool     `json:"public"`
+	SharedWith []string `json:"shared_with,omitempty"`
+}
+
+// ExposureReport lists internet exposed resources, unused security groups and shared RDS snapshots
+type ExposureReport struct {
+	Exposed              []Exposure            `json:"exposed"`
+	UnusedSecurityGroups []UnusedSecurityGroup `json:"unused_security_groups"`
+	SharedSnapshots      []SharedSnapshot      `json:"shared_snapshots"`
+}
+
+// openPermissions returns the inbound rules of a security group open to the internet
+func openPermissions(sg *awslib.SecurityGroup) []OpenPermission {
+	var open []OpenPermission
+	for _, p := range sg.IpPermissions {
+		var sources []string
+		for _, r := range p.IpRanges {
+			if aws.StringValue(r.CidrIp) == "0.0.0.0/0" {
+				sources = append(sources, "0.0.0.0/0")
+			}
+		}
+		for _, r := range p.Ipv6Ranges {
+			if aws.StringValue(r.CidrIpv6) == "::/0" {
+				sources = append(sources, "::/0")
+			}
+		}
+		for _, source := range sources {
+			perm := OpenPermission{
+				GroupID:  aws.StringValue(sg.GroupId),
+				Protocol: aws.StringValue(p.IpProtocol),
+				FromPort: aws.Int64Value(p.FromPort),
+				ToPort:   aws.Int64Value(p.ToPort),
+				Source:   source,
+			}
+			if perm.Protocol == "-1" {
+				perm.FromPort, perm.ToPort = 0, 65535
+			}
+			open = append(open, perm)
+		}
+	}
+	return open
+}
+
+// exposureFinder joins resources to the security groups of their region
+type exposureFinder struct {
+	inv        collector.AWSInventory
+	groups     map[string]map[string]*awslib.SecurityGroup
+	referenced map[string]map[string]bool
+	report     ExposureReport
+}
+
+// check records a resource as exposed when one of its security groups is open to the internet
+func (f *exposureFinder) check(service, region, id string, public bool, address string, groupIDs []string) {
+	if f.referenced[region] == nil {
+		f.referenced[region] = make(map[string]bool)
+	}
+	e := Exposure{Service: service, Account: f.inv.Account, Region: region, ID: id, Public: public, Address: address}
+	for _, groupID := range groupIDs {
+		f.referenced[region][groupID] = true
+		if sg, ok := f.groups[region][groupID]; ok {
+			e.Permissions = append(e.Permissions, openPermissions(sg)...)
+		}
+	}
+	if len(e.Permissions) > 0 {
+		f.report.Exposed = append(f.report.Exposed, e)
+	}
+}
+
+// checkNetworkLoadBalancer records an internet-facing network load balancer without security groups as exposed,
+// every listener being reachable from anywhere. All ports are reported when no listener was collected
+func (f *exposureFinder) checkNetworkLoadBalancer(region string, lb *awslib.ApplicationNetworkLoadBalancer) {
+	if aws.StringValue(lb.Scheme) != elbv2.LoadBalancerSchemeEnumInternetFacing {
+		return
+	}
+	e := Exposure{Service: "loadbalancer", Account: f.inv.Account, Region: region, ID: aws.StringValue(lb.LoadBalancerName),
+		Public: true, Address: aws.StringValue(lb.DNSName)}
+	for _, l := range lb.Listeners {
+		port := aws.Int64Value(l.Port)
+		e.Permissions = append(e.Permissions, OpenPermission{Protocol: strings.ToLower(aws.StringValue(l.Protocol)),
+			FromPort: port, ToPort: port, Source: "0.0.0.0/0"})
+	}
+	if len(e.Permissions) == 0 {
+		e.Permissions = []OpenPermission{{Protocol: "-1", FromPort: 0, ToPort: 65535, Source: "0.0.0.0/0"}}
+	}
+	f.report.Exposed = append(f.report.Exposed, e)
+}
+
+// FindExposure joins the security groups of an inventory to its EC2 instances, RDS instances, load balancers
+// and Lambda functions, reporting resources reachable from the internet and security groups attached to
+// no network interface nor referenced by another group.
+// Manual RDS snapshots shared publicly or with other accounts are reported as well
+func FindExposure(inv collector.AWSInventory) ExposureReport {
+	f := exposureFinder{
+		inv:        inv,
+		groups:     make(map[string]map[string]*awslib.SecurityGroup),
+		referenced: make(map[string]map[string]bool),
+		report:     ExposureReport{Exposed: []Exposure{}, UnusedSecurityGroups: []UnusedSecurityGroup{}, SharedSnapshots: []SharedSnapshot{}},
+	}
+	for region, groups := range inv.SecurityGroups {
+		f.groups[region] = make(map[string]*awslib.SecurityGroup)
+		f.referenced[region] = make(map[string]bool)
+		for _, sg := range groups {
+			f.groups[region][aws.StringValue(sg.GroupId)] = sg
+			// Groups allowing traffic from or to other groups count as referencing them
+			for _, perms := range [][]*ec2.IpPermission{sg.IpPermissions, sg.IpPermissionsEgress} {
+				for _, p := range perms {
+					for _, pair := range p.UserIdGroupPairs {
+						f.referenced[region][aws.StringValue(pair.GroupId)] = true
+					}
+				}
+			}
+		}
+	}
+
+	for region, instances := range inv.EC2 {
+		for _, i := range instances {
+			var ids []string
+			for _, sg := range i.SecurityGroups {
+				ids = append(ids, aws.StringValue(sg.GroupId))
+			}
+			address := aws.StringValue(i.PublicIpAddress)
+			f.check("ec2", region, aws.StringValue(i.InstanceId), address != "", address, ids)
+		}
+	}
+	for region, dbs := range inv.RDS {
+		for _, db := range dbs {
+			var ids []string
+			for _, sg := range db.VpcSecurityGroups {
+				ids = append(ids, aws.StringValue(sg.VpcSecurityGroupId))
+			}
+			var address string
+			if db.Endpoint != nil {
+				address = aws.StringValue(db.Endpoint.Address)
+			}
+			f.check("rds", region, aws.StringValue(db.DBInstanceIdentifier), aws.BoolValue(db.PubliclyAccessible), address, ids)
+		}
+	}
+	if inv.LoadBalancers != nil {
+		for region, lbs := range inv.LoadBalancers.Classic {
+			for _, lb := range lbs {
+				f.check("loadbalancer", region, aws.StringValue(lb.LoadBalancerName), aws.StringValue(lb.Scheme) == "internet-facing",
+					aws.StringValue(lb.DNSName), aws.StringValueSlice(lb.SecurityGroups))
+			}
+		}
+		for region, lbs := range inv.LoadBalancers.ApplicationNetwork {
+			for _, lb := range lbs {
+				if aws.StringValue(lb.Type) == elbv2.LoadBalancerTypeEnumNetwork && len(lb.SecurityGroups) == 0 {
+					f.checkNetworkLoadBalancer(region, lb)
+					continue
+				}
+				f.check("loadbalancer", region, aws.StringValue(lb.LoadBalancerName), aws.StringValue(lb.Scheme) == "internet-facing",
+					aws.StringValue(lb.DNSName), aws.StringValueSlice(lb.SecurityGroups))
+			}
+		}
+	}
+	for region, functions := range inv.Lambda {
+		for _, fn := range functions {
+			if fn.VpcConfig != nil {
+				f.check("lambda", region, aws.StringValue(fn.FunctionName), false, "", aws.StringValueSlice(fn.VpcConfig.SecurityGroupIds))
+			}
+		}
+	}
+
+	for region, groups := range inv.SecurityGroups {
+		for _, sg := range groups {
+			// Default groups cannot be deleted. Inventories dumped without network interfaces fall back to
+			// the references of the collected resources
+			if aws.StringValue(sg.GroupName) == "default" || len(sg.NetworkInterfaces) > 0 || f.referenced[region][aws.StringValue(sg.GroupId)] {
+				continue
+			}
+			f.report.UnusedSecurityGroups = append(f.report.UnusedSecurityGroups, UnusedSecurityGroup{
+				Account: inv.Account,
+				Region:  region,
+				GroupID: aws.StringValue(sg.GroupId),
+				Name:    aws.StringValue(sg.GroupName),
+				VpcID:   aws.StringValue(sg.VpcId),
+			})
+		}
+	}
+
+	for region, topology := range inv.RDSTopology {
+		if topology == nil {
+			continue
+		}
+		for _, s := range topology.Snapshots {
+			if s.Public || len(s.SharedWith) > 0 {
+				f.report.SharedSnapshots = append(f.report.SharedSnapshots, SharedSnapshot{inv.Account, region,
+					aws.StringValue(s.DBSnapshotIdentifier), false, s.Public, s.SharedWith})
+			}
+		}
+		for _, s := range topology.ClusterSnapshots {
+			if s.Public || len(s.SharedWith) > 0 {
+				f.report.SharedSnapshots = append(f.report.SharedSnapshots, SharedSnapshot{inv.Account, region,
+					aws.StringValue(s.DBClusterSnapshotIdentifier), true, s.Public, s.SharedWith})
+			}
+		}
+	}
+
+	sort.Slice(f.report.Exposed, func(i, j int) bool {
+		a, b := f.report.Exposed[i], f.report.Exposed[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.ID < b.ID
+	})
+	sort.Slice(f.report.UnusedSecurityGroups, func(i, j int) bool {
+		a, b := f.report.UnusedSecurityGroups[i], f.report.UnusedSecurityGroups[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.GroupID < b.GroupID
+	})
+	sort.Slice(f.report.SharedSnapshots, func(i, j int) bool {
+		a, b := f.report.SharedSnapshots[i], f.report.SharedSnapshots[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ID < b.ID
+	})
+	return f.report
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testExposureInventory = `{
+	"account": "123456789012",
+	"ec2": {"us-east-1": [
+		{"InstanceId": "i-public", "PublicIpAddress": "203.0.113.10", "SecurityGroups": [{"GroupId": "sg-web"}]},
+		{"InstanceId": "i-private", "SecurityGroups": [{"GroupId": "sg-all"}]},
+		{"InstanceId": "i-internal", "SecurityGroups": [{"GroupId": "sg-internal"}]}
+	]},
+	"rds": {"us-east-1": [
+		{"DBInstanceIdentifier": "db", "PubliclyAccessible": true, "VpcSecurityGroups": [{"VpcSecurityGroupId": "sg-web"}]}
+	]},
+	"loadbalancer": [{}, {"us-east-1": [
+		{"LoadBalancerName": "nlb", "Type": "network", "Scheme": "internet-facing", "DNSName": "nlb.example.com",
+			"Listeners": [{"Port": 443, "Protocol": "TCP"}]},
+		{"LoadBalancerName": "nlb-internal", "Type": "network", "Scheme": "internal"}
+	]}],
+	"rdstopology": {"us-east-1": {
+		"Snapshots": [
+			{"DBSnapshotIdentifier": "private"},
+			{"DBSnapshotIdentifier": "public", "Public": true}
+		],
+		"ClusterSnapshots": [
+			{"DBClusterSnapshotIdentifier": "shared", "Public": false, "SharedWith": ["210987654321"]}
+		]
+	}},
+	"securitygroup": {"us-east-1": [
+		{"GroupId": "sg-web", "GroupName": "web", "IpPermissions": [
+			{"IpProtocol": "tcp", "FromPort": 443, "ToPort": 443, "IpRanges": [{"CidrIp": "0.0.0.0/0"}], "Ipv6Ranges": [{"CidrIpv6": "::/0"}]},
+			{"IpProtocol": "tcp", "FromPort": 22, "ToPort": 22, "IpRanges": [{"CidrIp": "10.0.0.0/8"}]}
+		]},
+		{"GroupId": "sg-all", "GroupName": "all", "IpPermissions": [
+			{"IpProtocol": "-1", "IpRanges": [{"CidrIp": "0.0.0.0/0"}]}
+		]},
+		{"GroupId": "sg-internal", "GroupName": "internal", "IpPermissions": [
+			{"IpProtocol": "tcp", "FromPort": 5432, "ToPort": 5432, "UserIdGroupPairs": [{"GroupId": "sg-referenced"}]}
+		]},
+		{"GroupId": "sg-referenced", "GroupName": "referenced"},
+		{"GroupId": "sg-outbound", "GroupName": "outbound", "IpPermissionsEgress": [
+			{"IpProtocol": "tcp", "FromPort": 443, "ToPort": 443, "UserIdGroupPairs": [{"GroupId": "sg-egress"}]}
+		], "NetworkInterfaces": ["eni-2"]},
+		{"GroupId": "sg-egress", "GroupName": "egress"},
+		{"GroupId": "sg-eni", "GroupName": "eni", "NetworkInterfaces": ["eni-1"]},
+		{"GroupId": "sg-unused", "GroupName": "unused", "VpcId": "vpc-1"},
+		{"GroupId": "sg-default", "GroupName": "default"}
+	]}
+}`
+
+func TestFindExposure(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testExposureInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	report := FindExposure(inv)
+
+	if len(report.Exposed) != 4 {
+		t.Fatalf("Expected 4 exposed resources, got %+v", report.Exposed)
+	}
+	expected := []struct {
+		service, id string
+		public      bool
+		permissions int
+	}{
+		{"ec2", "i-private", false, 1},
+		{"ec2", "i-public", true, 2},
+		{"loadbalancer", "nlb", true, 1},
+		{"rds", "db", true, 2},
+	}
+	for i, e := range expected {
+		got := report.Exposed[i]
+		if got.Service != e.service || got.ID != e.id || got.Public != e.public || len(got.Permissions) != e.permissions {
+			t.Errorf("Expected %s %s public=%t with %d open rules, got %+v", e.service, e.id, e.public, e.permissions, got)
+		}
+	}
+	all := report.Exposed[0].Permissions[0]
+	if all.FromPort != 0 || all.ToPort != 65535 || all.Source != "0.0.0.0/0" {
+		t.Errorf("Expected all ports open to 0.0.0.0/0, got %+v", all)
+	}
+
+	if nlb := report.Exposed[2].Permissions[0]; nlb.Protocol != "tcp" || nlb.FromPort != 443 || nlb.ToPort != 443 {
+		t.Errorf("Expected the NLB listener to be open, got %+v", nlb)
+	}
+
+	if len(report.UnusedSecurityGroups) != 1 || report.UnusedSecurityGroups[0].GroupID != "sg-unused" {
+		t.Errorf("Expected only sg-unused to be unused, got %+v", report.UnusedSecurityGroups)
+	}
+
+	if len(report.SharedSnapshots) != 2 {
+		t.Fatalf("Expected 2 shared snapshots, got %+v", report.SharedSnapshots)
+	}
+	if s := report.SharedSnapshots[0]; s.ID != "public" || !s.Public || s.Cluster {
+		t.Errorf("Expected the public DB snapshot, got %+v", s)
+	}
+	if s := report.SharedSnapshots[1]; s.ID != "shared" || s.Public || !s.Cluster || len(s.SharedWith) != 1 {
+		t.Errorf("Expected the cluster snapshot shared with another account, got %+v", s)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// rootAccount is the credential report name of the account root user
+const rootAccount = "<root_account>"
+
+// StaleAccessKey is an active access key not rotated or not used within the allowed age
+type StaleAccessKey struct {
+	Account  string     `json:"account"`
+	User     string     `json:"user"`
+	Key      int        `json:"key"`
+	AgeDays  int        `json:"age_days"`
+	LastUsed *time.Time `json:"last_used,omitempty"`
+	Reason   string     `json:"reason"`
+}
+
+// UnusedRole is a role not assumed within the allowed age
+type UnusedRole struct {
+	Account  string     `json:"account"`
+	Role     string     `json:"role"`
+	Created  *time.Time `json:"created,omitempty"`
+	LastUsed *time.Time `json:"last_used,omitempty"`
+}
+
+// UserWithoutMFA is a user able to sign in to the console, or the root user, without MFA
+type UserWithoutMFA struct {
+	Account string `json:"account"`
+	User    string `json:"user"`
+}
+
+// IAMReport lists the stale access keys, unused roles and users without MFA of an inventory
+type IAMReport struct {
+	StaleAccessKeys []StaleAccessKey `json:"stale_access_keys"`
+	UnusedRoles     []UnusedRole     `json:"unused_roles"`
+	UsersWithoutMFA []UserWithoutMFA `json:"users_without_mfa"`
+}
+
+func daysSince(t time.Time, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// FindStaleCredentials reports the active access keys not rotated or not used within maxAge, the roles not used
+// within maxAge and the users without MFA of an inventory dumped with iam. Service-linked roles are ignored
+func FindStaleCredentials(inv collector.AWSInventory, maxAge time.Duration, now time.Time) IAMReport {
+	report := IAMReport{StaleAccessKeys: []StaleAccessKey{}, UnusedRoles: []UnusedRole{}, UsersWithoutMFA: []UserWithoutMFA{}}
+	if inv.IAM == nil {
+		return report
+	}
+	limit := now.Add(-maxAge)
+
+	for _, c := range inv.IAM.Credentials {
+		if !c.MFAActive && (c.PasswordEnabled || c.User == rootAccount) {
+			report.UsersWithoutMFA = append(report.UsersWithoutMFA, UserWithoutMFA{inv.Account, c.User})
+		}
+		for _, k := range c.AccessKeys {
+			if !k.Active || k.LastRotated == nil {
+				continue
+			}
+			var reasons []string
+			if k.LastRotated.Before(limit) {
+				reasons = append(reasons, fmt.Sprintf("not rotated for %d days", daysSince(*k.LastRotated, now)))
+				if k.LastUsed == nil {
+					reasons = append(reasons, "never used")
+				}
+			}
+			if k.LastUsed != nil && k.LastUsed.Before(limit) {
+				reasons = append(reasons, fmt.Sprintf("not used for %d days", daysSince(*k.LastUsed, now)))
+			}
+			if len(reasons) > 0 {
+				report.StaleAccessKeys = append(report.StaleAccessKeys, StaleAccessKey{inv.Account, c.User, k.Number,
+					daysSince(*k.LastRotated, now), k.LastUsed, strings.Join(reasons, ", ")})
+			}
+		}
+	}
+
+	for _, r := range inv.IAM.Roles {
+		if strings.HasPrefix(aws.StringValue(r.Path), "/aws-service-role/") {
+			continue
+		}
+		// Recently created roles may not have been needed yet
+		if r.CreateDate != nil && r.CreateDate.After(limit) {
+			continue
+		}
+		var lastUsed *time.Time
+		if r.RoleLastUsed != nil {
+			lastUsed = r.RoleLastUsed.LastUsedDate
+		}
+		if lastUsed == nil || lastUsed.Before(limit) {
+			report.UnusedRoles = append(report.UnusedRoles, UnusedRole{inv.Account, aws.StringValue(r.RoleName), r.CreateDate, lastUsed})
+		}
+	}
+
+	sort.Slice(report.StaleAccessKeys, func(i, j int) bool {
+		a, b := report.StaleAccessKeys[i], report.StaleAccessKeys[j]
+		if a.User != b.User {
+			return a.User < b.User
+		}
+		return a.Key < b.Key
+	})
+	sort.Slice(report.UnusedRoles, func(i, j int) bool {
+		return report.UnusedRoles[i].Role < report.UnusedRoles[j].Role
+	})
+	sort.Slice(report.UsersWithoutMFA, func(i, j int) bool {
+		return report.UsersWithoutMFA[i].User < report.UsersWithoutMFA[j].User
+	})
+	return report
+}
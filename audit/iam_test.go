@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testIAMInventory = `{
+	"account": "123456789012",
+	"iam": {
+		"Roles": [
+			{"RoleName": "new", "CreateDate": "2020-05-20T00:00:00Z"},
+			{"RoleName": "active", "CreateDate": "2019-01-01T00:00:00Z", "RoleLastUsed": {"LastUsedDate": "2020-05-30T00:00:00Z"}},
+			{"RoleName": "forgotten", "CreateDate": "2019-01-01T00:00:00Z", "RoleLastUsed": {"LastUsedDate": "2019-06-01T00:00:00Z"}},
+			{"RoleName": "never", "CreateDate": "2019-01-01T00:00:00Z"},
+			{"RoleName": "AWSServiceRoleForSupport", "Path": "/aws-service-role/support.amazonaws.com/", "CreateDate": "2019-01-01T00:00:00Z"}
+		],
+		"Credentials": [
+			{"User": "<root_account>", "MFAActive": false},
+			{"User": "alice", "PasswordEnabled": true, "MFAActive": true, "AccessKeys": [
+				{"Number": 1, "Active": true, "LastRotated": "2020-04-01T00:00:00Z", "LastUsed": "2020-05-31T00:00:00Z"}
+			]},
+			{"User": "bob", "PasswordEnabled": true, "MFAActive": false, "AccessKeys": [
+				{"Number": 1, "Active": true, "LastRotated": "2019-01-01T00:00:00Z", "LastUsed": "2020-05-31T00:00:00Z"},
+				{"Number": 2, "Active": true, "LastRotated": "2019-01-01T00:00:00Z"}
+			]},
+			{"User": "ci", "PasswordEnabled": false, "MFAActive": false, "AccessKeys": [
+				{"Number": 1, "Active": true, "LastRotated": "2020-04-01T00:00:00Z", "LastUsed": "2020-01-01T00:00:00Z"},
+				{"Number": 2, "Active": false, "LastRotated": "2018-01-01T00:00:00Z"}
+			]}
+		]
+	}
+}`
+
+func TestFindStaleCredentials(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testIAMInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	report := FindStaleCredentials(inv, 90*24*time.Hour, now)
+
+	expectedKeys := []struct {
+		user   string
+		key    int
+		reason string
+	}{
+		{"bob", 1, "not rotated for 517 days"},
+		{"bob", 2, "not rotated for 517 days, never used"},
+		{"ci", 1, "not used for 152 days"},
+	}
+	if len(report.StaleAccessKeys) != len(expectedKeys) {
+		t.Fatalf("Expected %d stale keys, got %+v", len(expectedKeys), report.StaleAccessKeys)
+	}
+	for i, e := range expectedKeys {
+		got := report.StaleAccessKeys[i]
+		if got.User != e.user || got.Key != e.key || got.Reason != e.reason {
+			t.Errorf("Expected %+v, got %+v", e, got)
+		}
+	}
+
+	if len(report.UnusedRoles) != 2 || report.UnusedRoles[0].Role != "forgotten" || report.UnusedRoles[1].Role != "never" {
+		t.Errorf("Expected forgotten and never to be unused, got %+v", report.UnusedRoles)
+	}
+	if len(report.UsersWithoutMFA) != 2 || report.UsersWithoutMFA[0].User != "<root_account>" || report.UsersWithoutMFA[1].User != "bob" {
+		t.Errorf("Expected the root account and bob without MFA, got %+v", report.UsersWithoutMFA)
+	}
+
+	if r := FindStaleCredentials(collector.AWSInventory{}, time.Hour, now); len(r.StaleAccessKeys)+len(r.UnusedRoles)+len(r.UsersWithoutMFA) != 0 {
+		t.Errorf("Expected an empty report without IAM inventory, got %+v", r)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"sort"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Unmanaged is the stack reported for resources owned by no CloudFormation stack
+const Unmanaged = collector.Unmanaged
+
+// OwnedResource is a collected resource along with the CloudFormation stack owning it, or Unmanaged.
+// DriftStatus is the drift status of the resource within its stack
+type OwnedResource struct {
+	Service     string `json:"service"`
+	Account     string `json:"account"`
+	Region      string `json:"region"`
+	ID          string `json:"id"`
+	Stack       string `json:"stack"`
+	StackStatus string `json:"stack_status,omitempty"`
+	DriftStatus string `json:"drift_status,omitempty"`
+}
+
+// CoverageSummary is the share of the resources of a service and account managed by CloudFormation
+type CoverageSummary struct {
+	Account    string  `json:"account"`
+	Service    string  `json:"service"`
+	Total      int     `json:"total"`
+	Managed    int     `json:"managed"`
+	Percentage float64 `json:"percentage"`
+}
+
+// OwnershipReport lists the owning stack of every resource of an inventory and the resulting coverage per service
+type OwnershipReport struct {
+	Resources []OwnedResource   `json:"resources"`
+	Coverage  []CoverageSummary `json:"coverage"`
+}
+
+// FindStackOwnership reports the CloudFormation stack owning every resource of an inventory dumped with cloudformation,
+// matching stack resources by their physical ID. Instances of an Auto Scaling group belong to the stack of the group
+func FindStackOwnership(inv collector.AWSInventory) OwnershipReport {
+	report := OwnershipReport{Resources: []OwnedResource{}, Coverage: []CoverageSummary{}}
+	o := collector.NewStackOwnership(inv)
+	for _, owned := range collector.OwnableResources(inv) {
+		r := OwnedResource{Service: owned.Service, Account: inv.Account, Region: owned.Region, ID: owned.ID, Stack: Unmanaged}
+		if sr, ok := o.Find(owned.Service, owned.Region, append([]string{owned.ID}, owned.PhysicalIDs...)); ok {
+			r.Stack = aws.StringValue(sr.Stack.StackName)
+			r.StackStatus = aws.StringValue(sr.Stack.StackStatus)
+			if sr.Resource.DriftInformation != nil {
+				r.DriftStatus = aws.StringValue(sr.Resource.DriftInformation.StackResourceDriftStatus)
+			}
+		}
+		report.Resources = append(report.Resources, r)
+	}
+
+	sort.Slice(report.Resources, func(i, j int) bool {
+		a, b := report.Resources[i], report.Resources[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ID < b.ID
+	})
+
+	coverage := make(map[string]*CoverageSummary)
+	for _, r := range report.Resources {
+		c, ok := coverage[r.Service]
+		if !ok {
+			c = &CoverageSummary{Account: inv.Account, Service: r.Service}
+			coverage[r.Service] = c
+		}
+		c.Total++
+		if r.Stack != Unmanaged {
+			c.Managed++
+		}
+	}
+	for _, c := range coverage {
+		c.Percentage = 100 * float64(c.Managed) / float64(c.Total)
+		report.Coverage = append(report.Coverage, *c)
+	}
+	sort.Slice(report.Coverage, func(i, j int) bool {
+		return report.Coverage[i].Service < report.Coverage[j].Service
+	})
+	return report
+}
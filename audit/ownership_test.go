@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testOwnershipInventory = `{
+	"account": "123456789012",
+	"cloudformation": {"us-east-1": [
+		{"StackName": "web", "StackStatus": "UPDATE_COMPLETE", "Resources": [
+			{"LogicalResourceId": "Group", "ResourceType": "AWS::AutoScaling::AutoScalingGroup", "PhysicalResourceId": "web-asg", "ResourceStatus": "CREATE_COMPLETE"},
+			{"LogicalResourceId": "Balancer", "ResourceType": "AWS::ElasticLoadBalancingV2::LoadBalancer", "ResourceStatus": "CREATE_COMPLETE",
+				"PhysicalResourceId": "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/0123456789abcdef",
+				"DriftInformation": {"StackResourceDriftStatus": "MODIFIED"}},
+			{"LogicalResourceId": "Assets", "ResourceType": "AWS::S3::Bucket", "PhysicalResourceId": "web-assets", "ResourceStatus": "CREATE_COMPLETE"},
+			{"LogicalResourceId": "Old", "ResourceType": "AWS::EC2::Instance", "PhysicalResourceId": "i-retained", "ResourceStatus": "DELETE_SKIPPED"}
+		]},
+		{"StackName": "db", "StackStatus": "CREATE_COMPLETE", "Resources": [
+			{"LogicalResourceId": "Database", "ResourceType": "AWS::RDS::DBInstance", "PhysicalResourceId": "orders", "ResourceStatus": "CREATE_COMPLETE"}
+		]}
+	]},
+	"ec2": {"us-east-1": [
+		{"InstanceId": "i-web", "AutoScalingGroupName": "web-asg"},
+		{"InstanceId": "i-retained"},
+		{"InstanceId": "i-manual"}
+	]},
+	"rds": {"us-east-1": [{"DBInstanceIdentifier": "orders"}], "eu-west-1": [{"DBInstanceIdentifier": "orders"}]},
+	"loadbalancer": [
+		{},
+		{"us-east-1": [{"LoadBalancerName": "web", "LoadBalancerArn": "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/0123456789abcdef"}]}
+	],
+	"s3": {"eu-west-1": [{"Name": "web-assets"}]}
+}`
+
+func TestFindStackOwnership(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testOwnershipInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	report := FindStackOwnership(inv)
+
+	expected := []OwnedResource{
+		{"ec2", "123456789012", "us-east-1", "i-manual", Unmanaged, "", ""},
+		{"ec2", "123456789012", "us-east-1", "i-retained", Unmanaged, "", ""},
+		{"ec2", "123456789012", "us-east-1", "i-web", "web", "UPDATE_COMPLETE", ""},
+		{"loadbalancer", "123456789012", "us-east-1", "web", "web", "UPDATE_COMPLETE", "MODIFIED"},
+		{"rds", "123456789012", "eu-west-1", "orders", Unmanaged, "", ""},
+		{"rds", "123456789012", "us-east-1", "orders", "db", "CREATE_COMPLETE", ""},
+		{"s3", "123456789012", "eu-west-1", "web-assets", "web", "UPDATE_COMPLETE", ""},
+	}
+	if len(report.Resources) != len(expected) {
+		t.Fatalf("Expected %d resources, got %+v", len(expected), report.Resources)
+	}
+	for i, r := range expected {
+		if report.Resources[i] != r {
+			t.Errorf("Expected %+v, got %+v", r, report.Resources[i])
+		}
+	}
+
+	expectedCoverage := []CoverageSummary{
+		{"123456789012", "ec2", 3, 1, 100.0 / 3},
+		{"123456789012", "loadbalancer", 1, 1, 100},
+		{"123456789012", "rds", 2, 1, 50},
+		{"123456789012", "s3", 1, 1, 100},
+	}
+	if len(report.Coverage) != len(expectedCoverage) {
+		t.Fatalf("Expected %d coverage summaries, got %+v", len(expectedCoverage), report.Coverage)
+	}
+	for i, c := range expectedCoverage {
+		if report.Coverage[i] != c {
+			t.Errorf("Expected %+v, got %+v", c, report.Coverage[i])
+		}
+	}
+}
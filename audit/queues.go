@@ -0,0 +1,79 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// QueueSummary is an SQS queue with its dead-letter queue, encryption and retention.
+// MissingDeadLetterQueue is set for queues without dead-letter queue which are not themselves one
+type QueueSummary struct {
+	Account                string `json:"account"`
+	Region                 string `json:"region"`
+	Name                   string `json:"name"`
+	ARN                    string `json:"arn"`
+	DeadLetterQueue        string `json:"dead_letter_queue,omitempty"`
+	IsDeadLetterQueue      bool   `json:"is_dead_letter_queue"`
+	MissingDeadLetterQueue bool   `json:"missing_dead_letter_queue"`
+	Encrypted              bool   `json:"encrypted"`
+	RetentionSeconds       int    `json:"retention_seconds"`
+}
+
+// SummarizeQueues lists the SQS queues of an inventory dumped with sqs
+func SummarizeQueues(inv collector.AWSInventory) []QueueSummary {
+	summaries := []QueueSummary{}
+	deadLetterQueues := make(map[string]bool)
+	for _, queues := range inv.SQS {
+		for _, q := range queues {
+			if q.DeadLetterTargetArn != "" {
+				deadLetterQueues[q.DeadLetterTargetArn] = true
+			}
+		}
+	}
+	for region, queues := range inv.SQS {
+		for _, q := range queues {
+			attribute := func(name string) string {
+				return aws.StringValue(q.Attributes[name])
+			}
+			arn := attribute("QueueArn")
+			retention, _ := strconv.Atoi(attribute("MessageRetentionPeriod"))
+			s := QueueSummary{
+				Account:           inv.Account,
+				Region:            region,
+				Name:              q.URL[strings.LastIndex(q.URL, "/")+1:],
+				ARN:               arn,
+				DeadLetterQueue:   q.DeadLetterTargetArn,
+				IsDeadLetterQueue: deadLetterQueues[arn],
+				// Queues are encrypted with a KMS key or with SQS managed keys
+				Encrypted:        attribute("KmsMasterKeyId") != "" || attribute("SqsManagedSseEnabled") == "true",
+				RetentionSeconds: retention,
+			}
+			s.MissingDeadLetterQueue = s.DeadLetterQueue == "" && !s.IsDeadLetterQueue
+			summaries = append(summaries, s)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.Name < b.Name
+	})
+	return summaries
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testQueueInventory = `{
+	"account": "123456789012",
+	"sqs": {
+		"us-east-1": [
+			{"URL": "https://sqs.us-east-1.amazonaws.com/123456789012/orders", "DeadLetterTargetArn": "arn:aws:sqs:us-east-1:123456789012:orders-dlq",
+				"Attributes": {"QueueArn": "arn:aws:sqs:us-east-1:123456789012:orders", "MessageRetentionPeriod": "345600", "KmsMasterKeyId": "alias/aws/sqs"}},
+			{"URL": "https://sqs.us-east-1.amazonaws.com/123456789012/orders-dlq",
+				"Attributes": {"QueueArn": "arn:aws:sqs:us-east-1:123456789012:orders-dlq", "MessageRetentionPeriod": "1209600", "SqsManagedSseEnabled": "true"}}
+		],
+		"eu-west-1": [
+			{"URL": "https://sqs.eu-west-1.amazonaws.com/123456789012/events",
+				"Attributes": {"QueueArn": "arn:aws:sqs:eu-west-1:123456789012:events", "MessageRetentionPeriod": "60", "SqsManagedSseEnabled": "false"}}
+		]
+	}
+}`
+
+func TestSummarizeQueues(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testQueueInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	queues := SummarizeQueues(inv)
+
+	expected := []QueueSummary{
+		{"123456789012", "eu-west-1", "events", "arn:aws:sqs:eu-west-1:123456789012:events", "", false, true, false, 60},
+		{"123456789012", "us-east-1", "orders", "arn:aws:sqs:us-east-1:123456789012:orders", "arn:aws:sqs:us-east-1:123456789012:orders-dlq", false, false, true, 345600},
+		{"123456789012", "us-east-1", "orders-dlq", "arn:aws:sqs:us-east-1:123456789012:orders-dlq", "", true, false, true, 1209600},
+	}
+	if len(queues) != len(expected) {
+		t.Fatalf("Expected %d queues, got %+v", len(expected), queues)
+	}
+	for i, q := range expected {
+		if queues[i] != q {
+			t.Errorf("Expected %+v, got %+v", q, queues[i])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// EC2Resources converts a region to []*ec2.Instance map into auditable resources
+func EC2Resources(account string, instances map[string][]*ec2.Instance) []Resource {
+	var resources []Resource
+	for region, ii := range instances {
+		for _, i := range ii {
+			tags := make(map[string]string)
+			for _, t := range i.Tags {
+				tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			resources = append(resources, Resource{"ec2", account, region, aws.StringValue(i.InstanceId), tags})
+		}
+	}
+	return resources
+}
+
+// RDSResources converts a region to []*awslib.DBInstance map into auditable resources
+func RDSResources(account string, instances map[string][]*awslib.DBInstance) []Resource {
+	var resources []Resource
+	for region, ii := range instances {
+		for _, i := range ii {
+			tags := make(map[string]string)
+			for _, t := range i.TagList {
+				tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			resources = append(resources, Resource{"rds", account, region, aws.StringValue(i.DBInstanceIdentifier), tags})
+		}
+	}
+	return resources
+}
+
+// ClassicLoadBalancerResources converts a region to []*awslib.ClassicLoadBalancer map into auditable resources
+func ClassicLoadBalancerResources(account string, lbs map[string][]*awslib.ClassicLoadBalancer) []Resource {
+	var resources []Resource
+	for region, ll := range lbs {
+		for _, lb := range ll {
+			tags := make(map[string]string)
+			for _, t := range lb.Tags {
+				tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			resources = append(resources, Resource{"loadbalancer", account, region, aws.StringValue(lb.LoadBalancerName), tags})
+		}
+	}
+	return resources
+}
+
+// ApplicationAndNetworkLoadBalancerResources converts a region to []*awslib.ApplicationNetworkLoadBalancer map into auditable resources
+func ApplicationAndNetworkLoadBalancerResources(account string, lbs map[string][]*awslib.ApplicationNetworkLoadBalancer) []Resource {
+	var resources []Resource
+	for region, ll := range lbs {
+		for _, lb := range ll {
+			tags := make(map[string]string)
+			for _, t := range lb.Tags {
+				tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			resources = append(resources, Resource{"loadbalancer", account, region, aws.StringValue(lb.LoadBalancerName), tags})
+		}
+	}
+	return resources
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"sort"
+	"time"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// PendingDeletionKey is a customer managed KMS key scheduled for deletion
+type PendingDeletionKey struct {
+	Account      string     `json:"account"`
+	Region       string     `json:"region"`
+	KeyID        string     `json:"key_id"`
+	Aliases      []string   `json:"aliases,omitempty"`
+	DeletionDate *time.Time `json:"deletion_date,omitempty"`
+}
+
+// StaleSecret is a Secrets Manager secret not rotated within the allowed age.
+// Secrets never rotated are aged from their creation
+type StaleSecret struct {
+	Account         string     `json:"account"`
+	Region          string     `json:"region"`
+	Name            string     `json:"name"`
+	RotationEnabled bool       `json:"rotation_enabled"`
+	LastRotated     *time.Time `json:"last_rotated,omitempty"`
+	LastAccessed    *time.Time `json:"last_accessed,omitempty"`
+	AgeDays         int        `json:"age_days"`
+}
+
+// RotationReport lists the KMS keys pending deletion and the stale secrets of an inventory
+type RotationReport struct {
+	KeysPendingDeletion []PendingDeletionKey `json:"keys_pending_deletion"`
+	StaleSecrets        []StaleSecret        `json:"stale_secrets"`
+}
+
+// FindRotationIssues reports the KMS keys pending deletion and the secrets not rotated within maxAge of an
+// inventory dumped with kms,secretsmanager
+func FindRotationIssues(inv collector.AWSInventory, maxAge time.Duration, now time.Time) RotationReport {
+	report := RotationReport{KeysPendingDeletion: []PendingDeletionKey{}, StaleSecrets: []StaleSecret{}}
+	limit := now.Add(-maxAge)
+
+	for region, keys := range inv.KMS {
+		for _, k := range keys {
+			if k.KeyMetadata == nil {
+				continue
+			}
+			switch aws.StringValue(k.KeyState) {
+			case kms.KeyStatePendingDeletion, kms.KeyStatePendingReplicaDeletion:
+				report.KeysPendingDeletion = append(report.KeysPendingDeletion,
+					PendingDeletionKey{inv.Account, region, aws.StringValue(k.KeyId), k.Aliases, k.DeletionDate})
+			}
+		}
+	}
+
+	for region, secrets := range inv.Secrets {
+		for _, s := range secrets {
+			rotated := s.LastRotatedDate
+			if rotated == nil {
+				rotated = s.CreatedDate
+			}
+			if rotated == nil || !rotated.Before(limit) {
+				continue
+			}
+			report.StaleSecrets = append(report.StaleSecrets, StaleSecret{inv.Account, region, aws.StringValue(s.Name),
+				aws.BoolValue(s.RotationEnabled), s.LastRotatedDate, s.LastAccessedDate, daysSince(*rotated, now)})
+		}
+	}
+
+	sort.Slice(report.KeysPendingDeletion, func(i, j int) bool {
+		a, b := report.KeysPendingDeletion[i], report.KeysPendingDeletion[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.KeyID < b.KeyID
+	})
+	sort.Slice(report.StaleSecrets, func(i, j int) bool {
+		a, b := report.StaleSecrets[i], report.StaleSecrets[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testRotationInventory = `{
+	"account": "123456789012",
+	"kms": {
+		"us-east-1": [
+			{"KeyId": "1111", "KeyState": "Enabled", "RotationEnabled": true},
+			{"KeyId": "2222", "KeyState": "PendingDeletion", "DeletionDate": "2020-06-08T00:00:00Z", "Aliases": ["alias/legacy"]}
+		]
+	},
+	"secretsmanager": {
+		"us-east-1": [
+			{"Name": "rotated", "RotationEnabled": true, "CreatedDate": "2019-01-01T00:00:00Z", "LastRotatedDate": "2020-05-01T00:00:00Z"},
+			{"Name": "stale", "RotationEnabled": true, "CreatedDate": "2019-01-01T00:00:00Z", "LastRotatedDate": "2020-01-01T00:00:00Z"},
+			{"Name": "new", "CreatedDate": "2020-05-20T00:00:00Z"},
+			{"Name": "never", "CreatedDate": "2019-06-01T00:00:00Z", "LastAccessedDate": "2020-05-31T00:00:00Z"}
+		]
+	}
+}`
+
+func TestFindRotationIssues(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testRotationInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	report := FindRotationIssues(inv, 90*24*time.Hour, now)
+
+	if len(report.KeysPendingDeletion) != 1 || report.KeysPendingDeletion[0].KeyID != "2222" || report.KeysPendingDeletion[0].Aliases[0] != "alias/legacy" {
+		t.Errorf("Expected key 2222 to be pending deletion, got %+v", report.KeysPendingDeletion)
+	}
+
+	expected := []struct {
+		name    string
+		rotated bool
+		age     int
+	}{
+		{"never", false, 366},
+		{"stale", true, 152},
+	}
+	if len(report.StaleSecrets) != len(expected) {
+		t.Fatalf("Expected %d stale secrets, got %+v", len(expected), report.StaleSecrets)
+	}
+	for i, e := range expected {
+		got := report.StaleSecrets[i]
+		if got.Name != e.name || got.RotationEnabled != e.rotated || got.AgeDays != e.age {
+			t.Errorf("Expected %+v, got %+v", e, got)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Storage finding kinds
+const (
+	UnattachedVolume  = "unattached_volume"
+	UnencryptedVolume = "unencrypted_volume"
+	OrphanedSnapshot  = "orphaned_snapshot"
+	UnusedImage       = "unused_image"
+)
+
+// imageSnapshotDescription matches the description CreateImage gives to the snapshots of an AMI
+var imageSnapshotDescription = regexp.MustCompile(`for (ami-[0-9a-f]+)`)
+
+// StorageFinding is a volume, snapshot or AMI wasting storage or left unencrypted.
+// SizeGiB is the size of the volume, or of the source volume of a snapshot
+type StorageFinding struct {
+	Kind    string `json:"kind"`
+	Account string `json:"account"`
+	Region  string `json:"region"`
+	ID      string `json:"id"`
+	SizeGiB int64  `json:"size_gib"`
+	Reason  string `json:"reason"`
+}
+
+// StorageReport lists the storage findings of an inventory.
+// WastedGiB sums the size of the unattached volumes and orphaned snapshots
+type StorageReport struct {
+	Findings  []StorageFinding `json:"findings"`
+	WastedGiB int64            `json:"wasted_gib"`
+}
+
+// FindOrphans reports the unattached and unencrypted volumes, the snapshots whose source volume or AMI no longer
+// exists and the AMIs used by no instance, launch template or launch configuration. AMIs are only checked
+// when the inventory was dumped with both ec2 and autoscaling
+func FindOrphans(inv collector.AWSInventory) StorageReport {
+	report := StorageReport{Findings: []StorageFinding{}}
+	add := func(kind, region, id string, size int64, reason string) {
+		report.Findings = append(report.Findings, StorageFinding{kind, inv.Account, region, id, size, reason})
+		if kind == UnattachedVolume || kind == OrphanedSnapshot {
+			report.WastedGiB += size
+		}
+	}
+
+	for region, storage := range inv.EBS {
+		if storage == nil {
+			continue
+		}
+		volumes := make(map[string]bool)
+		for _, v := range storage.Volumes {
+			id := aws.StringValue(v.VolumeId)
+			volumes[id] = true
+			if len(v.Attachments) == 0 {
+				add(UnattachedVolume, region, id, aws.Int64Value(v.Size), "volume is "+aws.StringValue(v.State)+" and attached to no instance")
+			}
+			if !aws.BoolValue(v.Encrypted) {
+				add(UnencryptedVolume, region, id, aws.Int64Value(v.Size), "volume is not encrypted")
+			}
+		}
+
+		images := make(map[string]bool)
+		imageSnapshots := make(map[string]bool)
+		for _, i := range storage.Images {
+			images[aws.StringValue(i.ImageId)] = true
+			for _, bdm := range i.BlockDeviceMappings {
+				if bdm.Ebs != nil {
+					imageSnapshots[aws.StringValue(bdm.Ebs.SnapshotId)] = true
+				}
+			}
+		}
+
+		for _, s := range storage.Snapshots {
+			id := aws.StringValue(s.SnapshotId)
+			// Snapshots backing an existing AMI are in use whatever happened to their source volume
+			if imageSnapshots[id] {
+				continue
+			}
+			if m := imageSnapshotDescription.FindStringSubmatch(aws.StringValue(s.Description)); m != nil {
+				if !images[m[1]] {
+					add(OrphanedSnapshot, region, id, aws.Int64Value(s.VolumeSize), "AMI "+m[1]+" no longer exists")
+				}
+				continue
+			}
+			if volume := aws.StringValue(s.VolumeId); !volumes[volume] {
+				add(OrphanedSnapshot, region, id, aws.Int64Value(s.VolumeSize), "source volume "+volume+" no longer exists")
+			}
+		}
+
+		// Without instances, launch templates and launch configurations every AMI would look unused
+		if inv.EC2 == nil || inv.AutoScaling == nil {
+			continue
+		}
+		used := make(map[string]bool)
+		for _, i := range inv.EC2[region] {
+			used[aws.StringValue(i.ImageId)] = true
+		}
+		if a := inv.AutoScaling[region]; a != nil {
+			for _, v := range a.LaunchTemplateVersions {
+				if v.LaunchTemplateData != nil {
+					used[aws.StringValue(v.LaunchTemplateData.ImageId)] = true
+				}
+			}
+			for _, c := range a.LaunchConfigurations {
+				used[aws.StringValue(c.ImageId)] = true
+			}
+		}
+		for _, i := range storage.Images {
+			id := aws.StringValue(i.ImageId)
+			if used[id] {
+				continue
+			}
+			var size int64
+			for _, bdm := range i.BlockDeviceMappings {
+				if bdm.Ebs != nil {
+					size += aws.Int64Value(bdm.Ebs.VolumeSize)
+				}
+			}
+			add(UnusedImage, region, id, size, "AMI "+aws.StringValue(i.Name)+" is used by no instance, launch template or launch configuration")
+		}
+	}
+
+	sort.Slice(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.ID < b.ID
+	})
+	return report
+}
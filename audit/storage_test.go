@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testStorageInventory = `{
+	"account": "123456789012",
+	"ec2": {"us-east-1": [{"InstanceId": "i-1", "ImageId": "ami-used"}]},
+	"ebs": {"us-east-1": {
+		"Volumes": [
+			{"VolumeId": "vol-attached", "Size": 8, "Encrypted": true, "State": "in-use", "Attachments": [{"InstanceId": "i-1"}]},
+			{"VolumeId": "vol-free", "Size": 100, "Encrypted": true, "State": "available"},
+			{"VolumeId": "vol-plain", "Size": 20, "Encrypted": false, "State": "in-use", "Attachments": [{"InstanceId": "i-1"}]}
+		],
+		"Snapshots": [
+			{"SnapshotId": "snap-live", "VolumeId": "vol-attached", "VolumeSize": 8},
+			{"SnapshotId": "snap-gone", "VolumeId": "vol-deleted", "VolumeSize": 50},
+			{"SnapshotId": "snap-ami", "VolumeId": "vol-deleted", "VolumeSize": 8, "Description": "Created by CreateImage(i-1) for ami-used"},
+			{"SnapshotId": "snap-deregistered", "VolumeId": "vol-deleted", "VolumeSize": 30, "Description": "Created by CreateImage(i-1) for ami-0ld"}
+		],
+		"Images": [
+			{"ImageId": "ami-used", "Name": "used", "BlockDeviceMappings": [{"Ebs": {"SnapshotId": "snap-ami", "VolumeSize": 8}}]},
+			{"ImageId": "ami-idle", "Name": "idle", "BlockDeviceMappings": [{"Ebs": {"SnapshotId": "snap-live", "VolumeSize": 8}}]},
+			{"ImageId": "ami-template", "Name": "template"},
+			{"ImageId": "ami-config", "Name": "config"}
+		]
+	}},
+	"autoscaling": {"us-east-1": {
+		"LaunchTemplateVersions": [{"LaunchTemplateId": "lt-1", "LaunchTemplateData": {"ImageId": "ami-template"}}],
+		"LaunchConfigurations": [{"LaunchConfigurationName": "lc", "ImageId": "ami-config"}]
+	}}
+}`
+
+func TestFindOrphans(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testStorageInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	report := FindOrphans(inv)
+
+	expected := []struct{ kind, id string }{
+		{OrphanedSnapshot, "snap-deregistered"},
+		{OrphanedSnapshot, "snap-gone"},
+		{UnattachedVolume, "vol-free"},
+		{UnencryptedVolume, "vol-plain"},
+		{UnusedImage, "ami-idle"},
+	}
+	if len(report.Findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %+v", len(expected), report.Findings)
+	}
+	for i, e := range expected {
+		if report.Findings[i].Kind != e.kind || report.Findings[i].ID != e.id {
+			t.Errorf("Expected %s %s, got %+v", e.kind, e.id, report.Findings[i])
+		}
+	}
+	if report.WastedGiB != 180 {
+		t.Errorf("Expected 180 GiB wasted, got %d", report.WastedGiB)
+	}
+
+	// AMIs cannot be checked without instances, launch templates and launch configurations
+	withoutAutoScaling := inv
+	withoutAutoScaling.AutoScaling = nil
+	inv.EC2 = nil
+	for _, partial := range []collector.AWSInventory{inv, withoutAutoScaling} {
+		for _, f := range FindOrphans(partial).Findings {
+			if f.Kind == UnusedImage {
+				t.Errorf("Expected no unused AMI from a partial inventory, got %+v", f)
+			}
+		}
+	}
+}
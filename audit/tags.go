@@ -0,0 +1,197 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+)
+
+// TagRule describes a tag key that must be present on a resource.
+// When AllowedValues is set the value must be one of them, when Pattern is set the value must match it
+type TagRule struct {
+	Key           string   `json:"key"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
+	Pattern       string   `json:"pattern,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// TagPolicy lists the required tags per service.
+// Default rules apply to every service, Services rules only to the named service (e.g ec2/rds/loadbalancer)
+type TagPolicy struct {
+	Default  []TagRule            `json:"default,omitempty"`
+	Services map[string][]TagRule `json:"services,omitempty"`
+}
+
+// Resource is a tagged cloud resource to be evaluated against a TagPolicy
+type Resource struct {
+	Service string            `json:"service"`
+	Account string            `json:"account"`
+	Region  string            `json:"region"`
+	ID      string            `json:"id"`
+	Tags    map[string]string `json:"-"`
+}
+
+// InvalidTag is a tag present on a resource whose value does not satisfy its rule
+type InvalidTag struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// TagViolation lists the missing and invalid tags of a non-compliant resource
+type TagViolation struct {
+	Resource
+	Missing []string     `json:"missing,omitempty"`
+	Invalid []InvalidTag `json:"invalid,omitempty"`
+}
+
+// ComplianceSummary is the tag compliance of all resources in an account and region
+type ComplianceSummary struct {
+	Account    string  `json:"account"`
+	Region     string  `json:"region"`
+	Total      int     `json:"total"`
+	Compliant  int     `json:"compliant"`
+	Percentage float64 `json:"percentage"`
+}
+
+// TagReport is the result of evaluating resources against a TagPolicy
+type TagReport struct {
+	NonCompliant []TagViolation      `json:"non_compliant"`
+	Summary      []ComplianceSummary `json:"summary"`
+}
+
+// LoadTagPolicy reads a JSON tag policy from the given path
+func LoadTagPolicy(path string) (TagPolicy, error) {
+	var policy TagPolicy
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	err = json.Unmarshal(data, &policy)
+	if err != nil {
+		return policy, fmt.Errorf("Invalid tag policy %s: %v", path, err)
+	}
+	err = policy.Compile()
+	return policy, err
+}
+
+// Compile validates the policy and prepares the patterns of every rule.
+// It must be called on policies that are not read with LoadTagPolicy, rules whose pattern is not compiled are never satisfied
+func (p *TagPolicy) Compile() error {
+	compileRules := func(rules []TagRule) error {
+		for i := range rules {
+			if rules[i].Key == "" {
+				return fmt.Errorf("Tag rule without a key")
+			}
+			if rules[i].Pattern == "" {
+				continue
+			}
+			re, err := regexp.Compile(rules[i].Pattern)
+			if err != nil {
+				return fmt.Errorf("Invalid pattern for tag %s: %v", rules[i].Key, err)
+			}
+			rules[i].re = re
+		}
+		return nil
+	}
+	if err := compileRules(p.Default); err != nil {
+		return err
+	}
+	for _, rules := range p.Services {
+		if err := compileRules(rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rulesFor returns the default rules followed by the rules specific to service
+func (p TagPolicy) rulesFor(service string) []TagRule {
+	rules := append([]TagRule{}, p.Default...)
+	return append(rules, p.Services[service]...)
+}
+
+// Check returns the violation of a single resource and whether it is compliant
+func (p TagPolicy) Check(r Resource) (TagViolation, bool) {
+	v := TagViolation{Resource: r}
+	for _, rule := range p.rulesFor(r.Service) {
+		value, ok := r.Tags[rule.Key]
+		if !ok {
+			v.Missing = append(v.Missing, rule.Key)
+			continue
+		}
+		if len(rule.AllowedValues) > 0 && !stringInSlice(value, rule.AllowedValues) {
+			v.Invalid = append(v.Invalid, InvalidTag{Key: rule.Key, Value: value, Reason: "value not allowed"})
+			continue
+		}
+		if rule.Pattern == "" {
+			continue
+		}
+		if rule.re == nil {
+			v.Invalid = append(v.Invalid, InvalidTag{Key: rule.Key, Value: value, Reason: fmt.Sprintf("pattern %s not compiled", rule.Pattern)})
+			continue
+		}
+		if !rule.re.MatchString(value) {
+			v.Invalid = append(v.Invalid, InvalidTag{Key: rule.Key, Value: value, Reason: fmt.Sprintf("does not match %s", rule.Pattern)})
+		}
+	}
+	return v, len(v.Missing) == 0 && len(v.Invalid) == 0
+}
+
+// Evaluate checks every resource against the policy and summarizes compliance per account and region
+func (p TagPolicy) Evaluate(resources []Resource) TagReport {
+	report := TagReport{NonCompliant: []TagViolation{}, Summary: []ComplianceSummary{}}
+	summaries := make(map[[2]string]*ComplianceSummary)
+
+	for _, r := range resources {
+		key := [2]string{r.Account, r.Region}
+		s, ok := summaries[key]
+		if !ok {
+			s = &ComplianceSummary{Account: r.Account, Region: r.Region}
+			summaries[key] = s
+		}
+		s.Total++
+		v, compliant := p.Check(r)
+		if compliant {
+			s.Compliant++
+			continue
+		}
+		report.NonCompliant = append(report.NonCompliant, v)
+	}
+
+	for _, s := range summaries {
+		s.Percentage = 100 * float64(s.Compliant) / float64(s.Total)
+		report.Summary = append(report.Summary, *s)
+	}
+	sort.Slice(report.Summary, func(i, j int) bool {
+		if report.Summary[i].Account != report.Summary[j].Account {
+			return report.Summary[i].Account < report.Summary[j].Account
+		}
+		return report.Summary[i].Region < report.Summary[j].Region
+	})
+	return report
+}
+
+func stringInSlice(a string, list []string) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
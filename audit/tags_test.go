@@ -0,0 +1,135 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPolicy = `{
+	"default": [{"key": "Owner"}],
+	"services": {
+		"ec2": [
+			{"key": "Environment", "allowed_values": ["prod", "dev"]},
+			{"key": "CostCenter", "pattern": "^[0-9]{4}$"}
+		]
+	}
+}`
+
+func writeTestPolicy(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("Unable to create temp dir: %v", err)
+	}
+	path := filepath.Join(dir, "policy.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Unable to write policy: %v", err)
+	}
+	return path
+}
+
+// TestLoadTagPolicy checks that valid policies load and invalid patterns are rejected
+func TestLoadTagPolicy(t *testing.T) {
+	path := writeTestPolicy(t, testPolicy)
+	defer os.RemoveAll(filepath.Dir(path))
+	policy, err := LoadTagPolicy(path)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+	if len(policy.rulesFor("ec2")) != 3 || len(policy.rulesFor("rds")) != 1 {
+		t.Errorf("Unexpected rules: ec2=%v rds=%v", policy.rulesFor("ec2"), policy.rulesFor("rds"))
+	}
+
+	bad := writeTestPolicy(t, `{"default": [{"key": "Owner", "pattern": "["}]}`)
+	defer os.RemoveAll(filepath.Dir(bad))
+	if _, err := LoadTagPolicy(bad); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
+
+// TestCheckUncompiledPattern checks that a pattern rule of a policy that was not compiled is reported instead of compiled on the fly
+func TestCheckUncompiledPattern(t *testing.T) {
+	policy := TagPolicy{Default: []TagRule{{Key: "CostCenter", Pattern: "["}}}
+	v, compliant := policy.Check(Resource{"ec2", "1", "us-east-1", "i-1", map[string]string{"CostCenter": "1234"}})
+	if compliant || len(v.Invalid) != 1 {
+		t.Errorf("Expected an invalid tag, found %+v", v)
+	}
+	if err := policy.Compile(); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+
+	policy = TagPolicy{Default: []TagRule{{Key: "CostCenter", Pattern: "^[0-9]{4}$"}}}
+	if err := policy.Compile(); err != nil {
+		t.Fatalf("Failed to compile policy: %v", err)
+	}
+	if _, compliant := policy.Check(Resource{"ec2", "1", "us-east-1", "i-1", map[string]string{"CostCenter": "1234"}}); !compliant {
+		t.Errorf("Expected a compliant resource")
+	}
+}
+
+// TestEvaluate checks missing and invalid tags as well as the compliance summary
+func TestEvaluate(t *testing.T) {
+	path := writeTestPolicy(t, testPolicy)
+	defer os.RemoveAll(filepath.Dir(path))
+	policy, err := LoadTagPolicy(path)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+	resources := []Resource{
+		{"ec2", "1", "us-east-1", "i-good", map[string]string{"Owner": "a", "Environment": "prod", "CostCenter": "1234"}},
+		{"ec2", "1", "us-east-1", "i-bad", map[string]string{"Environment": "qa", "CostCenter": "12"}},
+		{"rds", "1", "us-east-1", "db", map[string]string{"Owner": "a"}},
+		{"loadbalancer", "1", "eu-west-1", "lb", map[string]string{}},
+	}
+	report := policy.Evaluate(resources)
+
+	if len(report.NonCompliant) != 2 {
+		t.Fatalf("Expected 2 non compliant resources, found %d", len(report.NonCompliant))
+	}
+	bad := report.NonCompliant[0]
+	if bad.ID != "i-bad" || len(bad.Missing) != 1 || bad.Missing[0] != "Owner" || len(bad.Invalid) != 2 {
+		t.Errorf("Unexpected violation: %+v", bad)
+	}
+	if report.NonCompliant[1].ID != "lb" {
+		t.Errorf("Unexpected violation: %+v", report.NonCompliant[1])
+	}
+
+	if len(report.Summary) != 2 {
+		t.Fatalf("Expected 2 summaries, found %d", len(report.Summary))
+	}
+	for _, testCase := range []struct {
+		region     string
+		total      int
+		percentage float64
+	}{
+		{"eu-west-1", 1, 0},
+		{"us-east-1", 3, 100 * 2.0 / 3.0},
+	} {
+		var found bool
+		for _, s := range report.Summary {
+			if s.Region != testCase.region {
+				continue
+			}
+			found = true
+			if s.Total != testCase.total || s.Percentage != testCase.percentage {
+				t.Errorf("%s\tWant:%d/%f\tHave:%d/%f", testCase.region, testCase.total, testCase.percentage, s.Total, s.Percentage)
+			}
+		}
+		if !found {
+			t.Errorf("Missing summary for %s", testCase.region)
+		}
+	}
+}
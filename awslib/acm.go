@@ -0,0 +1,56 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+)
+
+// GetAllCertificates returns a complete list of ACM certificates of any key type, with their status, expiry,
+// the ARNs of the resources using them and their renewal eligibility, for a given session
+func GetAllCertificates(sess *session.Session) ([]*acm.CertificateDetail, error) {
+	acmc := acm.New(sess)
+	var arns []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := acmc.ListCertificates(&acm.ListCertificatesInput{
+			// Without key types only RSA 2048 certificates are listed
+			Includes:  &acm.Filters{KeyTypes: aws.StringSlice(acm.KeyAlgorithm_Values())},
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range result.CertificateSummaryList {
+			arns = append(arns, c.CertificateArn)
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var certificates []*acm.CertificateDetail
+	for _, arn := range arns {
+		var result *acm.DescribeCertificateOutput
+		err := retry(func() (err error) {
+			result, err = acmc.DescribeCertificate(&acm.DescribeCertificateInput{CertificateArn: arn})
+			return err
+		})
+		if err != nil {
+			return certificates, err
+		}
+		certificates = append(certificates, result.Certificate)
+	}
+	return certificates, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+)
+
+// maxAPIGatewayItems is the largest page size accepted by API Gateway list calls
+const maxAPIGatewayItems = 500
+
+// APIGateway holds the API Gateway REST APIs and custom domain names of a region
+type APIGateway struct {
+	RestAPIs    []*RestAPI
+	DomainNames []*RestDomainName
+}
+
+// RestAPI is an API Gateway REST API along with its stages
+type RestAPI struct {
+	*apigateway.RestApi
+	Stages []*apigateway.Stage
+	Stack  string `json:",omitempty"`
+}
+
+// RestDomainName is an API Gateway custom domain name along with the REST API stages mapped to its base paths
+type RestDomainName struct {
+	*apigateway.DomainName
+	BasePathMappings []*apigateway.BasePathMapping
+}
+
+// APIGatewayV2 holds the API Gateway HTTP and WebSocket APIs and custom domain names of a region
+type APIGatewayV2 struct {
+	APIs        []*API
+	DomainNames []*APIDomainName
+}
+
+// API is an API Gateway HTTP or WebSocket API along with its stages, routes and integrations
+type API struct {
+	*apigatewayv2.Api
+	Stages       []*apigatewayv2.Stage
+	Routes       []*apigatewayv2.Route
+	Integrations []*apigatewayv2.Integration
+	Stack        string `json:",omitempty"`
+}
+
+// APIDomainName is an API Gateway v2 custom domain name along with its API mappings
+type APIDomainName struct {
+	*apigatewayv2.DomainName
+	APIMappings []*apigatewayv2.ApiMapping
+}
+
+// GetAPIGateway returns the REST APIs with their stages and the custom domain names with their base path mappings
+// for a given session
+func GetAPIGateway(sess *session.Session) (*APIGateway, error) {
+	agc := apigateway.New(sess)
+	gateway := &APIGateway{}
+	err := paginate(func(position *string) (*string, error) {
+		result, err := agc.GetRestApis(&apigateway.GetRestApisInput{Limit: aws.Int64(maxAPIGatewayItems), Position: position})
+		if err != nil {
+			return nil, err
+		}
+		for _, api := range result.Items {
+			gateway.RestAPIs = append(gateway.RestAPIs, &RestAPI{RestApi: api})
+		}
+		return result.Position, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, api := range gateway.RestAPIs {
+		var stages *apigateway.GetStagesOutput
+		err := retry(func() (err error) {
+			stages, err = agc.GetStages(&apigateway.GetStagesInput{RestApiId: api.Id})
+			return err
+		})
+		if err != nil {
+			return gateway, err
+		}
+		api.Stages = stages.Item
+	}
+
+	err = paginate(func(position *string) (*string, error) {
+		result, err := agc.GetDomainNames(&apigateway.GetDomainNamesInput{Limit: aws.Int64(maxAPIGatewayItems), Position: position})
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range result.Items {
+			gateway.DomainNames = append(gateway.DomainNames, &RestDomainName{DomainName: d})
+		}
+		return result.Position, nil
+	})
+	if err != nil {
+		return gateway, err
+	}
+	for _, d := range gateway.DomainNames {
+		err := paginate(func(position *string) (*string, error) {
+			result, err := agc.GetBasePathMappings(&apigateway.GetBasePathMappingsInput{
+				DomainName: d.DomainName.DomainName,
+				Limit:      aws.Int64(maxAPIGatewayItems),
+				Position:   position,
+			})
+			if err != nil {
+				return nil, err
+			}
+			d.BasePathMappings = append(d.BasePathMappings, result.Items...)
+			return result.Position, nil
+		})
+		if err != nil {
+			return gateway, err
+		}
+	}
+	return gateway, nil
+}
+
+// GetAPIGatewayV2 returns the HTTP and WebSocket APIs with their stages, routes and integrations and the custom
+// domain names with their API mappings for a given session
+func GetAPIGatewayV2(sess *session.Session) (*APIGatewayV2, error) {
+	agc := apigatewayv2.New(sess)
+	gateway := &APIGatewayV2{}
+	err := paginate(func(token *string) (*string, error) {
+		result, err := agc.GetApis(&apigatewayv2.GetApisInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, api := range result.Items {
+			gateway.APIs = append(gateway.APIs, &API{Api: api})
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, api := range gateway.APIs {
+		err := paginate(func(token *string) (*string, error) {
+			result, err := agc.GetStages(&apigatewayv2.GetStagesInput{ApiId: api.ApiId, NextToken: token})
+			if err != nil {
+				return nil, err
+			}
+			api.Stages = append(api.Stages, result.Items...)
+			return result.NextToken, nil
+		})
+		if err != nil {
+			return gateway, err
+		}
+		err = paginate(func(token *string) (*string, error) {
+			result, err := agc.GetRoutes(&apigatewayv2.GetRoutesInput{ApiId: api.ApiId, NextToken: token})
+			if err != nil {
+				return nil, err
+			}
+			api.Routes = append(api.Routes, result.Items...)
+			return result.NextToken, nil
+		})
+		if err != nil {
+			return gateway, err
+		}
+		err = paginate(func(token *string) (*string, error) {
+			result, err := agc.GetIntegrations(&apigatewayv2.GetIntegrationsInput{ApiId: api.ApiId, NextToken: token})
+			if err != nil {
+				return nil, err
+			}
+			api.Integrations = append(api.Integrations, result.Items...)
+			return result.NextToken, nil
+		})
+		if err != nil {
+			return gateway, err
+		}
+	}
+
+	err = paginate(func(token *string) (*string, error) {
+		result, err := agc.GetDomainNames(&apigatewayv2.GetDomainNamesInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range result.Items {
+			gateway.DomainNames = append(gateway.DomainNames, &APIDomainName{DomainName: d})
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return gateway, err
+	}
+	for _, d := range gateway.DomainNames {
+		err := paginate(func(token *string) (*string, error) {
+			result, err := agc.GetApiMappings(&apigatewayv2.GetApiMappingsInput{DomainName: d.DomainName.DomainName, NextToken: token})
+			if err != nil {
+				return nil, err
+			}
+			d.APIMappings = append(d.APIMappings, result.Items...)
+			return result.NextToken, nil
+		})
+		if err != nil {
+			return gateway, err
+		}
+	}
+	return gateway, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AutoScaling is the Auto Scaling inventory of a region. Groups carry their desired, min and max capacities
+// along with the target groups (TargetGroupARNs) and Classic Load Balancers (LoadBalancerNames) they register with.
+// LaunchTemplateVersions holds the default and latest versions of every launch template
+type AutoScaling struct {
+	Groups                 []*autoscaling.Group
+	LaunchTemplates        []*ec2.LaunchTemplate
+	LaunchTemplateVersions []*ec2.LaunchTemplateVersion `json:",omitempty"`
+	LaunchConfigurations   []*autoscaling.LaunchConfiguration
+}
+
+// GetAutoScaling returns the Auto Scaling groups, launch templates and launch configurations for a given session
+func GetAutoScaling(sess *session.Session) (*AutoScaling, error) {
+	var a AutoScaling
+	var err error
+	if a.Groups, err = GetAllAutoScalingGroups(sess); err != nil {
+		return nil, err
+	}
+	if a.LaunchTemplates, err = GetAllLaunchTemplates(sess); err != nil {
+		return nil, err
+	}
+	if a.LaunchTemplateVersions, err = GetAllLaunchTemplateVersions(sess); err != nil {
+		return nil, err
+	}
+	if a.LaunchConfigurations, err = GetAllLaunchConfigurations(sess); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetAllAutoScalingGroups returns a complete list of Auto Scaling groups for a given session
+func GetAllAutoScalingGroups(sess *session.Session) ([]*autoscaling.Group, error) {
+	asc := autoscaling.New(sess)
+	var all []*autoscaling.Group
+	err := paginate(func(token *string) (*string, error) {
+		result, err := asc.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.AutoScalingGroups...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllLaunchTemplates returns a complete list of launch templates for a given session
+func GetAllLaunchTemplates(sess *session.Session) ([]*ec2.LaunchTemplate, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.LaunchTemplate
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeLaunchTemplates(&ec2.DescribeLaunchTemplatesInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.LaunchTemplates...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllLaunchTemplateVersions returns the default and latest versions of every launch template for a given session
+func GetAllLaunchTemplateVersions(sess *session.Session) ([]*ec2.LaunchTemplateVersion, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.LaunchTemplateVersion
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeLaunchTemplateVersions(&ec2.DescribeLaunchTemplateVersionsInput{
+			Versions:  aws.StringSlice([]string{"$Default", "$Latest"}),
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.LaunchTemplateVersions...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllLaunchConfigurations returns a complete list of launch configurations for a given session
+func GetAllLaunchConfigurations(sess *session.Session) ([]*autoscaling.LaunchConfiguration, error) {
+	asc := autoscaling.New(sess)
+	var all []*autoscaling.LaunchConfiguration
+	err := paginate(func(token *string) (*string, error) {
+		result, err := asc.DescribeLaunchConfigurations(&autoscaling.DescribeLaunchConfigurationsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.LaunchConfigurations...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// AnnotateInstances sets the Auto Scaling group name and lifecycle state of the instances belonging to one of
+// the given groups, as listed by DescribeAutoScalingGroups
+func AnnotateInstances(instances []*Instance, groups []*autoscaling.Group) {
+	membership := make(map[string]*autoscaling.Instance)
+	names := make(map[string]string)
+	for _, g := range groups {
+		for _, i := range g.Instances {
+			membership[aws.StringValue(i.InstanceId)] = i
+			names[aws.StringValue(i.InstanceId)] = aws.StringValue(g.AutoScalingGroupName)
+		}
+	}
+	for _, i := range instances {
+		if m, ok := membership[aws.StringValue(i.InstanceId)]; ok {
+			i.AutoScalingGroupName = names[aws.StringValue(i.InstanceId)]
+			i.LifecycleState = aws.StringValue(m.LifecycleState)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// TestAnnotateInstances checks that only the instances listed by a group get its name and their lifecycle state
+func TestAnnotateInstances(t *testing.T) {
+	instances := NewInstances([]*ec2.Instance{{InstanceId: aws.String("i-1")}, {InstanceId: aws.String("i-2")}})
+	AnnotateInstances(instances, []*autoscaling.Group{{
+		AutoScalingGroupName: aws.String("web"),
+		Instances:            []*autoscaling.Instance{{InstanceId: aws.String("i-1"), LifecycleState: aws.String("InService")}},
+	}})
+	if instances[0].AutoScalingGroupName != "web" || instances[0].LifecycleState != "InService" {
+		t.Errorf("Expected i-1 to be in service in web, got %+v", instances[0])
+	}
+	if instances[1].AutoScalingGroupName != "" || instances[1].LifecycleState != "" {
+		t.Errorf("Expected i-2 to belong to no group, got %+v", instances[1])
+	}
+}
+
+// TestNewInstances checks that the Auto Scaling group name is taken from the tag Auto Scaling puts on its instances
+func TestNewInstances(t *testing.T) {
+	instances := NewInstances([]*ec2.Instance{
+		{InstanceId: aws.String("i-1"), Tags: []*ec2.Tag{{Key: aws.String("aws:autoscaling:groupName"), Value: aws.String("web")}}},
+		{InstanceId: aws.String("i-2"), Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("web")}}},
+	})
+	if instances[0].AutoScalingGroupName != "web" || instances[0].LifecycleState != "" {
+		t.Errorf("Expected i-1 to belong to web with no lifecycle state, got %+v", instances[0])
+	}
+	if instances[1].AutoScalingGroupName != "" {
+		t.Errorf("Expected i-2 to belong to no group, got %+v", instances[1])
+	}
+}
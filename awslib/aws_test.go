@@ -59,3 +59,27 @@ func TestGetAllDBInstances(t *testing.T) {
 		t.Logf("Found %d instances in %s", len(dbinstances), r)
 	}
 }
+
+// TestGetAllBuckets checks if the lib is able to list and describe buckets properly or not.
+// This test REQUIRES a working AWS account and credentials to read from S3
+// This test does NOT fail unless there is an error in the gathering, the gathering itself is not validated.
+func TestGetAllBuckets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode")
+	}
+	sessions, err := BuildSessions([]string{"us-east-1"})
+	if err != nil {
+		t.Fatalf("Unable to get sessions : %v", err)
+	}
+	buckets, err := GetAllBuckets(sessions["us-east-1"])
+	if err != nil {
+		t.Errorf("Failed to list buckets because %v", err)
+	}
+	for _, b := range buckets {
+		region, err := GetBucketRegion(sessions["us-east-1"], *b.Name)
+		if err != nil {
+			t.Errorf("Failed to get region of %s because %v", *b.Name, err)
+		}
+		t.Logf("Found bucket %s in %s", *b.Name, region)
+	}
+}
@@ -14,13 +14,16 @@ package awslib
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/defaults"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/jpillora/backoff"
 )
 
 // GetAllRegions returns all regions for AWS except US-Gov and China
@@ -70,3 +73,72 @@ func BuildSessionsWithCredentials(regions []string, creds *credentials.Credentia
 	}
 	return sessions, errMain
 }
+
+// newBackoff returns the backoff used to retry throttled API calls
+func newBackoff() *backoff.Backoff {
+	return &backoff.Backoff{
+		//These are the defaults
+		Min:    10 * time.Millisecond,
+		Max:    30 * time.Second,
+		Factor: 2,
+		Jitter: false,
+	}
+}
+
+// isRateExceeded reports whether err is a throttling error worth retrying with backoff
+func isRateExceeded(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "RateExceeded", "Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequestsException", "SlowDown":
+			return true
+		}
+	}
+	return false
+}
+
+// isAccessDenied reports whether err comes from a call denied to the caller, such as a key or bucket policy
+// not granting the account access to the resource
+func isAccessDenied(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "AccessDeniedException", "AccessDenied":
+			return true
+		}
+	}
+	return false
+}
+
+// paginate calls page with the token returned by its previous call until no token is returned.
+// Throttled calls are retried with backoff
+func paginate(page func(token *string) (*string, error)) error {
+	b := newBackoff()
+	var token *string
+	for {
+		next, err := page(token)
+		if err != nil {
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return err
+		}
+		b.Reset()
+		if aws.StringValue(next) == "" {
+			return nil
+		}
+		token = next
+	}
+}
+
+// retry calls fn until it succeeds or fails with an error other than throttling
+func retry(fn func() error) error {
+	b := newBackoff()
+	for {
+		err := fn()
+		if err != nil && isRateExceeded(err) {
+			time.Sleep(b.Duration())
+			continue
+		}
+		return err
+	}
+}
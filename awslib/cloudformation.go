@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// Stack is a CloudFormation stack, with its status and drift status, along with the resources it manages.
+// Resources dumped along with their stacks carry the name of the stack owning them, or unmanaged, in their own Stack field
+type Stack struct {
+	*cloudformation.Stack
+	Resources []*cloudformation.StackResourceSummary
+}
+
+// GetAllStacks returns a complete list of the CloudFormation stacks not deleted, with their resources, for a given session
+func GetAllStacks(sess *session.Session) ([]*Stack, error) {
+	cfc := cloudformation.New(sess)
+	var stacks []*Stack
+	err := paginate(func(token *string) (*string, error) {
+		result, err := cfc.DescribeStacks(&cloudformation.DescribeStacksInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range result.Stacks {
+			stacks = append(stacks, &Stack{Stack: s})
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, stack := range stacks {
+		err := paginate(func(token *string) (*string, error) {
+			result, err := cfc.ListStackResources(&cloudformation.ListStackResourcesInput{StackName: stack.StackId, NextToken: token})
+			if err != nil {
+				return nil, err
+			}
+			stack.Resources = append(stack.Resources, result.StackResourceSummaries...)
+			return result.NextToken, nil
+		})
+		if err != nil {
+			return stacks, err
+		}
+	}
+	return stacks, nil
+}
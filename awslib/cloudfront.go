@@ -0,0 +1,41 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+)
+
+// GetAllDistributions returns a complete list of CloudFront distributions, with their origins, aliases and viewer certificate
+func GetAllDistributions(sess *session.Session) ([]*cloudfront.DistributionSummary, error) {
+	cfc := cloudfront.New(sess)
+	var all []*cloudfront.DistributionSummary
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := cfc.ListDistributions(&cloudfront.ListDistributionsInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		list := result.DistributionList
+		if list == nil {
+			return nil, nil
+		}
+		all = append(all, list.Items...)
+		if !aws.BoolValue(list.IsTruncated) {
+			return nil, nil
+		}
+		return list.NextMarker, nil
+	})
+	return all, err
+}
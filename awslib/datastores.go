@@ -0,0 +1,122 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/opensearchservice"
+)
+
+// maxDescribeDomains is the maximum number of domains accepted by a single DescribeDomains call
+const maxDescribeDomains = 5
+
+// ElastiCache holds the cache clusters, with their nodes, and the replication groups of a region
+type ElastiCache struct {
+	CacheClusters     []*elasticache.CacheCluster
+	ReplicationGroups []*elasticache.ReplicationGroup
+}
+
+// GetAllTables returns a complete list of DynamoDB tables, including their size, billing mode and encryption, for a given session
+func GetAllTables(sess *session.Session) ([]*dynamodb.TableDescription, error) {
+	dynamoc := dynamodb.New(sess)
+	var names []*string
+	err := paginate(func(last *string) (*string, error) {
+		result, err := dynamoc.ListTables(&dynamodb.ListTablesInput{ExclusiveStartTableName: last})
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, result.TableNames...)
+		return result.LastEvaluatedTableName, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tables []*dynamodb.TableDescription
+	for _, name := range names {
+		var result *dynamodb.DescribeTableOutput
+		err := retry(func() (err error) {
+			result, err = dynamoc.DescribeTable(&dynamodb.DescribeTableInput{TableName: name})
+			return err
+		})
+		if err != nil {
+			return tables, err
+		}
+		tables = append(tables, result.Table)
+	}
+	return tables, nil
+}
+
+// GetElastiCache returns the cache clusters and replication groups for a given session
+func GetElastiCache(sess *session.Session) (*ElastiCache, error) {
+	cachec := elasticache.New(sess)
+	var e ElastiCache
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := cachec.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{
+			ShowCacheNodeInfo: aws.Bool(true),
+			Marker:            marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+		e.CacheClusters = append(e.CacheClusters, result.CacheClusters...)
+		return result.Marker, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = paginate(func(marker *string) (*string, error) {
+		result, err := cachec.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		e.ReplicationGroups = append(e.ReplicationGroups, result.ReplicationGroups...)
+		return result.Marker, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetAllDomains returns a complete list of OpenSearch domains for a given session
+func GetAllDomains(sess *session.Session) ([]*opensearchservice.DomainStatus, error) {
+	osc := opensearchservice.New(sess)
+	var result *opensearchservice.ListDomainNamesOutput
+	err := retry(func() (err error) {
+		result, err = osc.ListDomainNames(&opensearchservice.ListDomainNamesInput{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []*string
+	for _, d := range result.DomainNames {
+		names = append(names, d.DomainName)
+	}
+	var domains []*opensearchservice.DomainStatus
+	for _, batch := range batches(names, maxDescribeDomains) {
+		var described *opensearchservice.DescribeDomainsOutput
+		err := retry(func() (err error) {
+			described, err = osc.DescribeDomains(&opensearchservice.DescribeDomainsInput{DomainNames: batch})
+			return err
+		})
+		if err != nil {
+			return domains, err
+		}
+		domains = append(domains, described.DomainStatusList...)
+	}
+	return domains, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// TestGetAllDomains checks that domains are described in batches of at most maxDescribeDomains
+func TestGetAllDomains(t *testing.T) {
+	var sizes []int
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2021-01-01/domain":
+			var names []string
+			for i := 0; i < 12; i++ {
+				names = append(names, fmt.Sprintf(`{"DomainName": "domain-%d"}`, i))
+			}
+			fmt.Fprintf(w, `{"DomainNames": [%s]}`, strings.Join(names, ","))
+		case r.URL.Path == "/2021-01-01/opensearch/domain-info":
+			var input struct{ DomainNames []string }
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Errorf("Unable to decode DescribeDomains input: %v", err)
+			}
+			sizes = append(sizes, len(input.DomainNames))
+			var statuses []string
+			for _, name := range input.DomainNames {
+				statuses = append(statuses, fmt.Sprintf(`{"DomainName": "%s"}`, name))
+			}
+			fmt.Fprintf(w, `{"DomainStatusList": [%s]}`, strings.Join(statuses, ","))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	})
+	defer closeServer()
+
+	domains, err := GetAllDomains(sess)
+	if err != nil {
+		t.Fatalf("Failed to get domains: %v", err)
+	}
+	if len(domains) != 12 || aws.StringValue(domains[11].DomainName) != "domain-11" {
+		t.Errorf("Unexpected domains: %v", domains)
+	}
+	if fmt.Sprint(sizes) != "[5 5 2]" {
+		t.Errorf("Expected batches of 5, 5 and 2 domains, got %v", sizes)
+	}
+}
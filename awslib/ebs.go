@@ -0,0 +1,93 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// EBS is the block storage inventory of a region: its volumes, the snapshots and AMIs owned by the account
+type EBS struct {
+	Volumes   []*ec2.Volume
+	Snapshots []*ec2.Snapshot
+	Images    []*ec2.Image
+}
+
+// GetEBS returns the volumes, and the snapshots and AMIs owned by the account for a given session
+func GetEBS(sess *session.Session) (*EBS, error) {
+	var e EBS
+	var err error
+	if e.Volumes, err = GetAllVolumes(sess); err != nil {
+		return nil, err
+	}
+	if e.Snapshots, err = GetAllSnapshots(sess); err != nil {
+		return nil, err
+	}
+	if e.Images, err = GetAllImages(sess); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetAllVolumes returns a complete list of EBS volumes for a given session
+func GetAllVolumes(sess *session.Session) ([]*ec2.Volume, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.Volume
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeVolumes(&ec2.DescribeVolumesInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.Volumes...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllSnapshots returns a complete list of EBS snapshots owned by the account for a given session
+func GetAllSnapshots(sess *session.Session) ([]*ec2.Snapshot, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.Snapshot
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeSnapshots(&ec2.DescribeSnapshotsInput{
+			OwnerIds:  aws.StringSlice([]string{"self"}),
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.Snapshots...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllImages returns a complete list of AMIs owned by the account for a given session
+func GetAllImages(sess *session.Session) ([]*ec2.Image, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.Image
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeImages(&ec2.DescribeImagesInput{
+			Owners:    aws.StringSlice([]string{"self"}),
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.Images...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
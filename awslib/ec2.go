@@ -15,12 +15,40 @@ package awslib
 import (
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/jpillora/backoff"
 )
 
+// Instance is an EC2 instance along with the Auto Scaling group it belongs to and its lifecycle state in the group
+type Instance struct {
+	*ec2.Instance
+	AutoScalingGroupName string `json:",omitempty"`
+	LifecycleState       string `json:",omitempty"`
+	Stack                string `json:",omitempty"`
+}
+
+// autoScalingGroupTag is the tag Auto Scaling puts on the instances it launches
+const autoScalingGroupTag = "aws:autoscaling:groupName"
+
+// NewInstances wraps EC2 instances, taking their Auto Scaling group name from the tag Auto Scaling puts on them.
+// Their lifecycle state is only known once annotated with AnnotateInstances
+func NewInstances(instances []*ec2.Instance) []*Instance {
+	var all []*Instance
+	for _, i := range instances {
+		instance := &Instance{Instance: i}
+		for _, t := range i.Tags {
+			if aws.StringValue(t.Key) == autoScalingGroupTag {
+				instance.AutoScalingGroupName = aws.StringValue(t.Value)
+			}
+		}
+		all = append(all, instance)
+	}
+	return all
+}
+
 // GetAllInstances returns a complete list of instances for a given session
 func GetAllInstances(sess *session.Session) ([]*ec2.Instance, error) {
 	ec2c := ec2.New(sess)
@@ -0,0 +1,180 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	// maxDescribeClusters is the maximum number of clusters accepted by a single DescribeClusters call
+	maxDescribeClusters = 100
+	// maxDescribeServices is the maximum number of services accepted by a single DescribeServices call
+	maxDescribeServices = 10
+	// maxDescribeContainerInstances is the maximum number of container instances accepted by a single DescribeContainerInstances call
+	maxDescribeContainerInstances = 100
+)
+
+// ECSCluster is an ECS cluster along with its services, the task definitions they run,
+// the details of its capacity providers and its container instances, each one carrying its Ec2InstanceId
+type ECSCluster struct {
+	*ecs.Cluster
+	Services                []*ecs.Service
+	TaskDefinitions         []*ecs.TaskDefinition
+	CapacityProviderDetails []*ecs.CapacityProvider
+	ContainerInstances      []*ecs.ContainerInstance
+	Stack                   string `json:",omitempty"`
+}
+
+// batches splits items in slices of at most size items
+func batches(items []*string, size int) [][]*string {
+	var all [][]*string
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		all = append(all, items[start:end])
+	}
+	return all
+}
+
+// GetAllECSClusters returns a complete list of ECS clusters with their services, task definitions in use,
+// capacity providers and container instances for a given session
+func GetAllECSClusters(sess *session.Session) ([]*ECSCluster, error) {
+	ecsc := ecs.New(sess)
+	var arns []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ecsc.ListClusters(&ecs.ListClustersInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		arns = append(arns, result.ClusterArns...)
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []*ECSCluster
+	for _, batch := range batches(arns, maxDescribeClusters) {
+		var result *ecs.DescribeClustersOutput
+		err := retry(func() (err error) {
+			result, err = ecsc.DescribeClusters(&ecs.DescribeClustersInput{Clusters: batch})
+			return err
+		})
+		if err != nil {
+			return clusters, err
+		}
+		for _, c := range result.Clusters {
+			cluster := &ECSCluster{Cluster: c}
+			if err := describeECSCluster(sess, cluster); err != nil {
+				return clusters, err
+			}
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters, nil
+}
+
+// describeECSCluster fills the services, task definitions, capacity providers and container instances of a cluster
+func describeECSCluster(sess *session.Session, cluster *ECSCluster) error {
+	ecsc := ecs.New(sess)
+	arn := cluster.ClusterArn
+
+	var serviceArns []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ecsc.ListServices(&ecs.ListServicesInput{Cluster: arn, NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		serviceArns = append(serviceArns, result.ServiceArns...)
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches(serviceArns, maxDescribeServices) {
+		var result *ecs.DescribeServicesOutput
+		err := retry(func() (err error) {
+			result, err = ecsc.DescribeServices(&ecs.DescribeServicesInput{Cluster: arn, Services: batch})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		cluster.Services = append(cluster.Services, result.Services...)
+	}
+
+	inUse := make(map[string]bool)
+	for _, s := range cluster.Services {
+		taskDefinition := aws.StringValue(s.TaskDefinition)
+		if taskDefinition == "" || inUse[taskDefinition] {
+			continue
+		}
+		inUse[taskDefinition] = true
+		var result *ecs.DescribeTaskDefinitionOutput
+		err := retry(func() (err error) {
+			result, err = ecsc.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{TaskDefinition: s.TaskDefinition})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		cluster.TaskDefinitions = append(cluster.TaskDefinitions, result.TaskDefinition)
+	}
+
+	if len(cluster.CapacityProviders) > 0 {
+		err = paginate(func(token *string) (*string, error) {
+			result, err := ecsc.DescribeCapacityProviders(&ecs.DescribeCapacityProvidersInput{
+				CapacityProviders: cluster.CapacityProviders,
+				NextToken:         token,
+			})
+			if err != nil {
+				return nil, err
+			}
+			cluster.CapacityProviderDetails = append(cluster.CapacityProviderDetails, result.CapacityProviders...)
+			return result.NextToken, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var instanceArns []*string
+	err = paginate(func(token *string) (*string, error) {
+		result, err := ecsc.ListContainerInstances(&ecs.ListContainerInstancesInput{Cluster: arn, NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		instanceArns = append(instanceArns, result.ContainerInstanceArns...)
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches(instanceArns, maxDescribeContainerInstances) {
+		var result *ecs.DescribeContainerInstancesOutput
+		err := retry(func() (err error) {
+			result, err = ecsc.DescribeContainerInstances(&ecs.DescribeContainerInstancesInput{Cluster: arn, ContainerInstances: batch})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		cluster.ContainerInstances = append(cluster.ContainerInstances, result.ContainerInstances...)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+// MinSupportedEKSVersion is the oldest Kubernetes version still in EKS standard support
+const MinSupportedEKSVersion = "1.34"
+
+// EKSCluster is an EKS cluster along with its node groups, Fargate profiles and version support status
+type EKSCluster struct {
+	*eks.Cluster
+	NodeGroups         []*eks.Nodegroup
+	FargateProfiles    []*eks.FargateProfile
+	UnsupportedVersion bool
+	Stack              string `json:",omitempty"`
+}
+
+// UnsupportedEKSVersion reports whether a major.minor Kubernetes version is older than the given minimum.
+// Versions that cannot be parsed, or an invalid minimum, are never reported
+func UnsupportedEKSVersion(version, min string) bool {
+	major, minor, ok := parseKubernetesVersion(version)
+	if !ok {
+		return false
+	}
+	minMajor, minMinor, ok := parseKubernetesVersion(min)
+	if !ok {
+		return false
+	}
+	return major < minMajor || (major == minMajor && minor < minMinor)
+}
+
+// ValidKubernetesVersion reports whether a version can be compared by UnsupportedEKSVersion
+func ValidKubernetesVersion(version string) bool {
+	_, _, ok := parseKubernetesVersion(version)
+	return ok
+}
+
+func parseKubernetesVersion(version string) (int, int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// GetAllEKSClusters returns a complete list of EKS clusters with their node groups and Fargate profiles for a given session
+func GetAllEKSClusters(sess *session.Session) ([]*EKSCluster, error) {
+	eksc := eks.New(sess)
+	var names []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := eksc.ListClusters(&eks.ListClustersInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, result.Clusters...)
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []*EKSCluster
+	for _, name := range names {
+		var result *eks.DescribeClusterOutput
+		err := retry(func() (err error) {
+			result, err = eksc.DescribeCluster(&eks.DescribeClusterInput{Name: name})
+			return err
+		})
+		if err != nil {
+			return clusters, err
+		}
+		cluster := &EKSCluster{Cluster: result.Cluster, UnsupportedVersion: UnsupportedEKSVersion(aws.StringValue(result.Cluster.Version), MinSupportedEKSVersion)}
+		if cluster.NodeGroups, err = GetNodeGroups(sess, aws.StringValue(name)); err != nil {
+			return clusters, err
+		}
+		if cluster.FargateProfiles, err = GetFargateProfiles(sess, aws.StringValue(name)); err != nil {
+			return clusters, err
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// GetNodeGroups returns the managed node groups of an EKS cluster
+func GetNodeGroups(sess *session.Session, cluster string) ([]*eks.Nodegroup, error) {
+	eksc := eks.New(sess)
+	var names []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := eksc.ListNodegroups(&eks.ListNodegroupsInput{ClusterName: aws.String(cluster), NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, result.Nodegroups...)
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var nodeGroups []*eks.Nodegroup
+	for _, name := range names {
+		var result *eks.DescribeNodegroupOutput
+		err := retry(func() (err error) {
+			result, err = eksc.DescribeNodegroup(&eks.DescribeNodegroupInput{ClusterName: aws.String(cluster), NodegroupName: name})
+			return err
+		})
+		if err != nil {
+			return nodeGroups, err
+		}
+		nodeGroups = append(nodeGroups, result.Nodegroup)
+	}
+	return nodeGroups, nil
+}
+
+// GetFargateProfiles returns the Fargate profiles of an EKS cluster
+func GetFargateProfiles(sess *session.Session, cluster string) ([]*eks.FargateProfile, error) {
+	eksc := eks.New(sess)
+	var names []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := eksc.ListFargateProfiles(&eks.ListFargateProfilesInput{ClusterName: aws.String(cluster), NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, result.FargateProfileNames...)
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var profiles []*eks.FargateProfile
+	for _, name := range names {
+		var result *eks.DescribeFargateProfileOutput
+		err := retry(func() (err error) {
+			result, err = eksc.DescribeFargateProfile(&eks.DescribeFargateProfileInput{ClusterName: aws.String(cluster), FargateProfileName: name})
+			return err
+		})
+		if err != nil {
+			return profiles, err
+		}
+		profiles = append(profiles, result.FargateProfile)
+	}
+	return profiles, nil
+}
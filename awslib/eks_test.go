@@ -0,0 +1,36 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import "testing"
+
+func TestUnsupportedEKSVersion(t *testing.T) {
+	versions := map[string]bool{
+		"1.23":    true,
+		"1.29":    true,
+		"1.30":    false,
+		"1.31":    false,
+		"v1.28.3": true,
+		"2.0":     false,
+		"":        false,
+		"latest":  false,
+	}
+	for version, unsupported := range versions {
+		if UnsupportedEKSVersion(version, "1.30") != unsupported {
+			t.Errorf("Expected UnsupportedEKSVersion(%q) to be %t", version, unsupported)
+		}
+	}
+	if UnsupportedEKSVersion("1.23", "latest") {
+		t.Errorf("Expected no version to be unsupported below an invalid minimum")
+	}
+}
@@ -7,6 +7,12 @@ import (
 	"time"
 )
 
+// HostedZone is a Route53 hosted zone along with its record sets
+type HostedZone struct {
+	*route53.HostedZone
+	Records []*route53.ResourceRecordSet `json:",omitempty"`
+}
+
 // GetAllInstances returns a complete list of instances for a given session
 func GetAllHostedZones(sess *session.Session) ([]*route53.HostedZone, error) {
 
@@ -66,8 +72,14 @@ func GetHostedZoneRecords(sess *session.Session, hostedZoneId string) ([]*route5
 
 		response , err := r53.ListResourceRecordSets(request)
 		if err != nil {
-			time.Sleep(b.Duration())
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return nil, err
 		}else {
+			b.Reset()
 			records = append(records, response.ResourceRecordSets...)
 			if response.IsTruncated == nil || !*response.IsTruncated {
 				nextPageExists = false
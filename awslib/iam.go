@@ -0,0 +1,182 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// IAM is the inventory of the global IAM entities of an account.
+// Users, groups and roles carry their attached managed policies and inline policies, Policies only lists customer managed policies
+type IAM struct {
+	Users       []*iam.UserDetail
+	Groups      []*iam.GroupDetail
+	Roles       []*iam.RoleDetail
+	Policies    []*iam.ManagedPolicyDetail
+	Credentials []*CredentialReportEntry
+}
+
+// CredentialReportEntry is the credential report line of a user, the root account being named <root_account>
+type CredentialReportEntry struct {
+	User             string
+	ARN              string
+	UserCreationTime *time.Time `json:",omitempty"`
+	PasswordEnabled  bool
+	PasswordLastUsed *time.Time `json:",omitempty"`
+	MFAActive        bool
+	AccessKeys       []AccessKeyReport `json:",omitempty"`
+}
+
+// AccessKeyReport is the age and last use of one of the two access keys of a user
+type AccessKeyReport struct {
+	Number          int
+	Active          bool
+	LastRotated     *time.Time `json:",omitempty"`
+	LastUsed        *time.Time `json:",omitempty"`
+	LastUsedService string     `json:",omitempty"`
+}
+
+// credentialReportPoll is the delay between two checks of the credential report generation
+var credentialReportPoll = 2 * time.Second
+
+// GetIAM returns the users, groups, roles, customer managed policies and credential report of the account
+func GetIAM(sess *session.Session) (*IAM, error) {
+	iamc := iam.New(sess)
+	var i IAM
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := iamc.GetAccountAuthorizationDetails(&iam.GetAccountAuthorizationDetailsInput{
+			Filter: aws.StringSlice([]string{"User", "Group", "Role", "LocalManagedPolicy"}),
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+		i.Users = append(i.Users, result.UserDetailList...)
+		i.Groups = append(i.Groups, result.GroupDetailList...)
+		i.Roles = append(i.Roles, result.RoleDetailList...)
+		i.Policies = append(i.Policies, result.Policies...)
+		if !aws.BoolValue(result.IsTruncated) {
+			return nil, nil
+		}
+		return result.Marker, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if i.Credentials, err = GetCredentialReport(sess); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// GetCredentialReport generates the account credential report, waits for it to complete and parses it
+func GetCredentialReport(sess *session.Session) ([]*CredentialReportEntry, error) {
+	iamc := iam.New(sess)
+	for {
+		var result *iam.GenerateCredentialReportOutput
+		err := retry(func() (err error) {
+			result, err = iamc.GenerateCredentialReport(&iam.GenerateCredentialReportInput{})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if aws.StringValue(result.State) == iam.ReportStateTypeComplete {
+			break
+		}
+		time.Sleep(credentialReportPoll)
+	}
+	var report *iam.GetCredentialReportOutput
+	err := retry(func() (err error) {
+		report, err = iamc.GetCredentialReport(&iam.GetCredentialReportInput{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ParseCredentialReport(bytes.NewReader(report.Content))
+}
+
+// parseReportTime reads a credential report date, which is N/A, no_information or not_supported when unknown
+func parseReportTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ParseCredentialReport parses a CSV credential report
+func ParseCredentialReport(r io.Reader) ([]*CredentialReportEntry, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("Empty credential report")
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, name := range []string{"user", "arn", "mfa_active"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("Credential report has no %s column", name)
+		}
+	}
+
+	var entries []*CredentialReportEntry
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		entry := &CredentialReportEntry{
+			User:             field("user"),
+			ARN:              field("arn"),
+			UserCreationTime: parseReportTime(field("user_creation_time")),
+			PasswordEnabled:  field("password_enabled") == "true",
+			PasswordLastUsed: parseReportTime(field("password_last_used")),
+			MFAActive:        field("mfa_active") == "true",
+		}
+		for n := 1; n <= 2; n++ {
+			prefix := fmt.Sprintf("access_key_%d_", n)
+			key := AccessKeyReport{
+				Number:          n,
+				Active:          field(prefix+"active") == "true",
+				LastRotated:     parseReportTime(field(prefix + "last_rotated")),
+				LastUsed:        parseReportTime(field(prefix + "last_used_date")),
+				LastUsedService: field(prefix + "last_used_service"),
+			}
+			if key.LastUsedService == "N/A" {
+				key.LastUsedService = ""
+			}
+			// A key never created has neither a rotation date nor an active flag
+			if key.Active || key.LastRotated != nil {
+				entry.AccessKeys = append(entry.AccessKeys, key)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
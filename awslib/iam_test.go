@@ -0,0 +1,54 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCredentialReport = `user,arn,user_creation_time,password_enabled,password_last_used,password_last_changed,password_next_rotation,mfa_active,access_key_1_active,access_key_1_last_rotated,access_key_1_last_used_date,access_key_1_last_used_region,access_key_1_last_used_service,access_key_2_active,access_key_2_last_rotated,access_key_2_last_used_date,access_key_2_last_used_region,access_key_2_last_used_service,cert_1_active,cert_1_last_rotated,cert_2_active,cert_2_last_rotated
+<root_account>,arn:aws:iam::123456789012:root,2015-01-01T00:00:00+00:00,not_supported,2020-01-01T00:00:00+00:00,not_supported,not_supported,true,false,N/A,N/A,N/A,N/A,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A
+deploy,arn:aws:iam::123456789012:user/deploy,2018-05-01T10:00:00+00:00,false,N/A,N/A,N/A,false,true,2018-05-01T10:00:00+00:00,2019-02-03T04:05:00+00:00,us-east-1,s3,false,2019-01-01T00:00:00+00:00,N/A,N/A,N/A,false,N/A,false,N/A
+`
+
+func TestParseCredentialReport(t *testing.T) {
+	entries, err := ParseCredentialReport(strings.NewReader(testCredentialReport))
+	if err != nil {
+		t.Fatalf("Failed to parse credential report: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	root := entries[0]
+	if root.User != "<root_account>" || !root.MFAActive || root.PasswordEnabled || len(root.AccessKeys) != 0 {
+		t.Errorf("Unexpected root entry %+v", root)
+	}
+
+	deploy := entries[1]
+	if deploy.MFAActive || deploy.PasswordLastUsed != nil || len(deploy.AccessKeys) != 2 {
+		t.Fatalf("Unexpected deploy entry %+v", deploy)
+	}
+	key := deploy.AccessKeys[0]
+	if !key.Active || key.LastRotated == nil || key.LastUsed == nil || key.LastUsed.Year() != 2019 || key.LastUsedService != "s3" {
+		t.Errorf("Unexpected first access key %+v", key)
+	}
+	if key := deploy.AccessKeys[1]; key.Active || key.LastUsed != nil || key.LastUsedService != "" {
+		t.Errorf("Unexpected second access key %+v", key)
+	}
+
+	if _, err := ParseCredentialReport(strings.NewReader("a,b\n")); err == nil {
+		t.Errorf("Expected an error for a report without the user column")
+	}
+}
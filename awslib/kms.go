@@ -0,0 +1,201 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// Key is a customer managed KMS key along with its rotation status, aliases and the principals named by its key policy.
+// DeniedOperations lists the calls the key policy denied, a key whose DescribeKey is denied only carrying its KeyId
+type Key struct {
+	*kms.KeyMetadata
+	RotationEnabled  bool
+	Aliases          []string `json:",omitempty"`
+	PolicyPrincipals []string `json:",omitempty"`
+	DeniedOperations []string `json:",omitempty"`
+	Stack            string   `json:",omitempty"`
+}
+
+// GetAllKeys returns a complete list of customer managed KMS keys for a given session. AWS managed keys are skipped
+func GetAllKeys(sess *session.Session) ([]*Key, error) {
+	kmsc := kms.New(sess)
+	var ids []*string
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := kmsc.ListKeys(&kms.ListKeysInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range result.Keys {
+			ids = append(ids, k.KeyId)
+		}
+		if !aws.BoolValue(result.Truncated) {
+			return nil, nil
+		}
+		return result.NextMarker, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make(map[string][]string)
+	err = paginate(func(marker *string) (*string, error) {
+		result, err := kmsc.ListAliases(&kms.ListAliasesInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range result.Aliases {
+			if id := aws.StringValue(a.TargetKeyId); id != "" {
+				aliases[id] = append(aliases[id], aws.StringValue(a.AliasName))
+			}
+		}
+		if !aws.BoolValue(result.Truncated) {
+			return nil, nil
+		}
+		return result.NextMarker, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*Key
+	for _, id := range ids {
+		var described *kms.DescribeKeyOutput
+		err := retry(func() (err error) {
+			described, err = kmsc.DescribeKey(&kms.DescribeKeyInput{KeyId: id})
+			return err
+		})
+		if isAccessDenied(err) {
+			keys = append(keys, &Key{KeyMetadata: &kms.KeyMetadata{KeyId: id}, Aliases: aliases[aws.StringValue(id)],
+				DeniedOperations: []string{"DescribeKey"}})
+			continue
+		}
+		if err != nil {
+			return keys, err
+		}
+		if aws.StringValue(described.KeyMetadata.KeyManager) != kms.KeyManagerTypeCustomer {
+			continue
+		}
+		key := &Key{KeyMetadata: described.KeyMetadata, Aliases: aliases[aws.StringValue(id)]}
+
+		var rotation *kms.GetKeyRotationStatusOutput
+		err = retry(func() (err error) {
+			rotation, err = kmsc.GetKeyRotationStatus(&kms.GetKeyRotationStatusInput{KeyId: id})
+			return err
+		})
+		if err == nil {
+			key.RotationEnabled = aws.BoolValue(rotation.KeyRotationEnabled)
+		} else if isAccessDenied(err) {
+			key.DeniedOperations = append(key.DeniedOperations, "GetKeyRotationStatus")
+		} else if !isUnsupportedKeyOperation(err) {
+			return keys, err
+		}
+
+		var policy *kms.GetKeyPolicyOutput
+		err = retry(func() (err error) {
+			policy, err = kmsc.GetKeyPolicy(&kms.GetKeyPolicyInput{KeyId: id, PolicyName: aws.String("default")})
+			return err
+		})
+		if err == nil {
+			key.PolicyPrincipals = PolicyPrincipals(aws.StringValue(policy.Policy))
+		} else if isAccessDenied(err) {
+			key.DeniedOperations = append(key.DeniedOperations, "GetKeyPolicy")
+		} else if !isUnsupportedKeyOperation(err) {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// isUnsupportedKeyOperation reports whether err comes from a call not applicable to a key, such as the rotation
+// status of an asymmetric key or of a key pending deletion
+func isUnsupportedKeyOperation(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case kms.ErrCodeUnsupportedOperationException, kms.ErrCodeInvalidStateException:
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyPrincipals returns the sorted principals allowed or denied by the statements of a policy document,
+// prefixed with their type such as AWS: or Service:
+func PolicyPrincipals(document string) []string {
+	var policy struct {
+		Statement []struct {
+			Principal interface{}
+		}
+	}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		// A single statement may not be wrapped in an array
+		var single struct {
+			Statement struct {
+				Principal interface{}
+			}
+		}
+		if json.Unmarshal([]byte(document), &single) != nil {
+			return nil
+		}
+		policy.Statement = append(policy.Statement, single.Statement)
+	}
+	seen := make(map[string]bool)
+	for _, s := range policy.Statement {
+		switch p := s.Principal.(type) {
+		case string:
+			seen[p] = true
+		case map[string]interface{}:
+			for kind, values := range p {
+				switch v := values.(type) {
+				case string:
+					seen[kind+":"+v] = true
+				case []interface{}:
+					for _, value := range v {
+						if value, ok := value.(string); ok {
+							seen[kind+":"+value] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	var principals []string
+	for p := range seen {
+		principals = append(principals, p)
+	}
+	sort.Strings(principals)
+	return principals
+}
+
+// GetAllSecrets returns the metadata of every Secrets Manager secret for a given session, never their value
+func GetAllSecrets(sess *session.Session) ([]*secretsmanager.SecretListEntry, error) {
+	smc := secretsmanager.New(sess)
+	var all []*secretsmanager.SecretListEntry
+	err := paginate(func(token *string) (*string, error) {
+		result, err := smc.ListSecrets(&secretsmanager.ListSecretsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.SecretList...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestPolicyPrincipals(t *testing.T) {
+	policy := `{
+		"Version": "2012-10-17",
+		"Statement": [
+			{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:root"}, "Action": "kms:*", "Resource": "*"},
+			{"Effect": "Allow", "Principal": {"AWS": ["arn:aws:iam::123456789012:role/app", "arn:aws:iam::210987654321:root"], "Service": "logs.amazonaws.com"}, "Action": "kms:Decrypt", "Resource": "*"},
+			{"Effect": "Allow", "Principal": "*", "Action": "kms:Encrypt", "Resource": "*"}
+		]
+	}`
+	expected := []string{
+		"*",
+		"AWS:arn:aws:iam::123456789012:role/app",
+		"AWS:arn:aws:iam::123456789012:root",
+		"AWS:arn:aws:iam::210987654321:root",
+		"Service:logs.amazonaws.com",
+	}
+	if got := PolicyPrincipals(policy); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	single := `{"Statement": {"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:root"}}}`
+	if got := PolicyPrincipals(single); !reflect.DeepEqual(got, []string{"AWS:arn:aws:iam::123456789012:root"}) {
+		t.Errorf("Expected the principal of a single statement, got %v", got)
+	}
+}
+
+// TestGetAllKeysAccessDenied checks that keys whose policy denies the caller are recorded rather than failing the region
+func TestGetAllKeysAccessDenied(t *testing.T) {
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		var input struct{ KeyId string }
+		json.NewDecoder(r.Body).Decode(&input)
+		denied := func() {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type": "AccessDeniedException", "message": "denied"}`)
+		}
+		switch target := r.Header.Get("X-Amz-Target"); target {
+		case "TrentService.ListKeys":
+			fmt.Fprint(w, `{"Keys": [{"KeyId": "denied"}, {"KeyId": "open"}], "Truncated": false}`)
+		case "TrentService.ListAliases":
+			fmt.Fprint(w, `{"Aliases": [{"AliasName": "alias/denied", "TargetKeyId": "denied"}], "Truncated": false}`)
+		case "TrentService.DescribeKey":
+			if input.KeyId == "denied" {
+				denied()
+				return
+			}
+			fmt.Fprintf(w, `{"KeyMetadata": {"KeyId": "%s", "KeyManager": "CUSTOMER"}}`, input.KeyId)
+		case "TrentService.GetKeyRotationStatus":
+			fmt.Fprint(w, `{"KeyRotationEnabled": true}`)
+		case "TrentService.GetKeyPolicy":
+			denied()
+		default:
+			t.Errorf("Unexpected target %s", target)
+		}
+	})
+	defer closeServer()
+
+	keys, err := GetAllKeys(sess)
+	if err != nil {
+		t.Fatalf("Failed to get keys: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %v", keys)
+	}
+	if k := keys[0]; aws.StringValue(k.KeyId) != "denied" || !reflect.DeepEqual(k.DeniedOperations, []string{"DescribeKey"}) ||
+		!reflect.DeepEqual(k.Aliases, []string{"alias/denied"}) {
+		t.Errorf("Expected the denied key to be recorded, got %+v", k)
+	}
+	if k := keys[1]; aws.StringValue(k.KeyId) != "open" || !k.RotationEnabled || !reflect.DeepEqual(k.DeniedOperations, []string{"GetKeyPolicy"}) {
+		t.Errorf("Expected the open key with its policy denied, got %+v", k)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+)
+
+// DeprecatedRuntimes lists the Lambda runtimes past their deprecation date
+var DeprecatedRuntimes = map[string]bool{
+	"nodejs":         true,
+	"nodejs4.3":      true,
+	"nodejs4.3-edge": true,
+	"nodejs6.10":     true,
+	"nodejs8.10":     true,
+	"nodejs10.x":     true,
+	"nodejs12.x":     true,
+	"nodejs14.x":     true,
+	"nodejs16.x":     true,
+	"nodejs18.x":     true,
+	"python2.7":      true,
+	"python3.6":      true,
+	"python3.7":      true,
+	"python3.8":      true,
+	"python3.9":      true,
+	"java8":          true,
+	"dotnetcore1.0":  true,
+	"dotnetcore2.0":  true,
+	"dotnetcore2.1":  true,
+	"dotnetcore3.1":  true,
+	"dotnet5.0":      true,
+	"dotnet6":        true,
+	"dotnet7":        true,
+	"ruby2.5":        true,
+	"ruby2.7":        true,
+	"ruby3.2":        true,
+	"go1.x":          true,
+	"provided":       true,
+}
+
+// Function is a Lambda function configuration along with its tags and runtime deprecation status
+type Function struct {
+	*lambda.FunctionConfiguration
+	Tags              map[string]*string
+	DeprecatedRuntime bool
+	Stack             string `json:",omitempty"`
+}
+
+// GetAllFunctions returns a complete list of tagged Lambda functions for a given session
+func GetAllFunctions(sess *session.Session) ([]*Function, error) {
+	lambdac := lambda.New(sess)
+	var allFunctions []*Function
+	input := lambda.ListFunctionsInput{}
+	b := newBackoff()
+	for {
+		result, err := lambdac.ListFunctions(&input)
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return allFunctions, err
+		}
+		b.Reset()
+		for _, f := range result.Functions {
+			tags, err := GetFunctionTags(sess, aws.StringValue(f.FunctionArn))
+			if err != nil {
+				return allFunctions, err
+			}
+			allFunctions = append(allFunctions, &Function{FunctionConfiguration: f, Tags: tags, DeprecatedRuntime: DeprecatedRuntimes[aws.StringValue(f.Runtime)]})
+		}
+		if result.NextMarker == nil {
+			return allFunctions, nil
+		}
+		input.SetMarker(*result.NextMarker)
+	}
+}
+
+// GetFunctionTags returns the tags of the Lambda function with the given ARN
+func GetFunctionTags(sess *session.Session, arn string) (map[string]*string, error) {
+	lambdac := lambda.New(sess)
+	b := newBackoff()
+	for {
+		result, err := lambdac.ListTags(&lambda.ListTagsInput{Resource: aws.String(arn)})
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return nil, err
+		}
+		return result.Tags, nil
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// TestGetAllFunctions checks that every page of functions is listed with its tags and runtime deprecation
+func TestGetAllFunctions(t *testing.T) {
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/2015-03-31/functions"):
+			if r.URL.Query().Get("Marker") == "" {
+				fmt.Fprint(w, `{"Functions": [{"FunctionName": "old", "FunctionArn": "arn:old", "Runtime": "python2.7"}], "NextMarker": "next"}`)
+				return
+			}
+			fmt.Fprint(w, `{"Functions": [{"FunctionName": "new", "FunctionArn": "arn:new", "Runtime": "python3.12"}]}`)
+		case strings.HasPrefix(r.URL.Path, "/2017-03-31/tags/"):
+			fmt.Fprintf(w, `{"Tags": {"Name": "%s"}}`, strings.TrimPrefix(r.URL.Path, "/2017-03-31/tags/"))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	})
+	defer closeServer()
+
+	functions, err := GetAllFunctions(sess)
+	if err != nil {
+		t.Fatalf("Failed to get functions: %v", err)
+	}
+	if len(functions) != 2 {
+		t.Fatalf("Expected 2 functions, found %d", len(functions))
+	}
+	for _, testCase := range []struct {
+		name       string
+		deprecated bool
+	}{
+		{"old", true},
+		{"new", false},
+	} {
+		var found bool
+		for _, f := range functions {
+			if aws.StringValue(f.FunctionName) != testCase.name {
+				continue
+			}
+			found = true
+			if f.DeprecatedRuntime != testCase.deprecated {
+				t.Errorf("%s\tWant:%t\tHave:%t", testCase.name, testCase.deprecated, f.DeprecatedRuntime)
+			}
+			if aws.StringValue(f.Tags["Name"]) != "arn:"+testCase.name {
+				t.Errorf("Unexpected tags for %s: %v", testCase.name, f.Tags)
+			}
+		}
+		if !found {
+			t.Errorf("Missing function %s", testCase.name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+package awslib
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// Listener is an Application or Network Load Balancer listener along with its rules
+type Listener struct {
+	*elbv2.Listener
+	Rules []*elbv2.Rule
+}
+
+// GetListeners returns all listeners of the load balancer with the given ARN
+func GetListeners(sess *session.Session, lbArn string) ([]*elbv2.Listener, error) {
+	lb := elbv2.New(sess)
+	var listeners []*elbv2.Listener
+	input := elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(lbArn)}
+	b := newBackoff()
+	for {
+		result, err := lb.DescribeListeners(&input)
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return listeners, err
+		}
+		b.Reset()
+		listeners = append(listeners, result.Listeners...)
+		if aws.StringValue(result.NextMarker) == "" {
+			return listeners, nil
+		}
+		input.SetMarker(*result.NextMarker)
+	}
+}
+
+// GetListenerRules returns all rules of the listener with the given ARN
+func GetListenerRules(sess *session.Session, listenerArn string) ([]*elbv2.Rule, error) {
+	lb := elbv2.New(sess)
+	var rules []*elbv2.Rule
+	input := elbv2.DescribeRulesInput{ListenerArn: aws.String(listenerArn)}
+	b := newBackoff()
+	for {
+		result, err := lb.DescribeRules(&input)
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return rules, err
+		}
+		b.Reset()
+		rules = append(rules, result.Rules...)
+		if aws.StringValue(result.NextMarker) == "" {
+			return rules, nil
+		}
+		input.SetMarker(*result.NextMarker)
+	}
+}
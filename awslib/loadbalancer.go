@@ -16,6 +16,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/elb"
@@ -23,6 +24,23 @@ import (
 	"github.com/jpillora/backoff"
 )
 
+// ClassicLoadBalancer is a Classic Load Balancer description along with its tags and the health of its instances
+type ClassicLoadBalancer struct {
+	*elb.LoadBalancerDescription
+	Tags           []*elb.Tag
+	InstanceHealth []*elb.InstanceState `json:",omitempty"`
+	Stack          string               `json:",omitempty"`
+}
+
+// ApplicationNetworkLoadBalancer is an Application or Network Load Balancer along with its tags, listeners and target groups
+type ApplicationNetworkLoadBalancer struct {
+	*elbv2.LoadBalancer
+	Tags         []*elbv2.Tag
+	Listeners    []*Listener    `json:",omitempty"`
+	TargetGroups []*TargetGroup `json:",omitempty"`
+	Stack        string         `json:",omitempty"`
+}
+
 // GetAllCLB resturns a complete list of Classic Load Balancers for a given session
 func GetAllCLB(sess *session.Session) ([]*elb.LoadBalancerDescription, error) {
 	lb := elb.New(sess)
@@ -106,3 +124,100 @@ func GetAllALBAndNLB(sess *session.Session) ([]*elbv2.LoadBalancer, error) {
 	}
 	return allLoadBalancers, nil
 }
+
+// TagCLBs attaches the tags of every Classic Load Balancer, described in batches with DescribeTags
+func TagCLBs(sess *session.Session, lbs []*elb.LoadBalancerDescription) ([]*ClassicLoadBalancer, error) {
+	var names []string
+	for _, lb := range lbs {
+		names = append(names, aws.StringValue(lb.LoadBalancerName))
+	}
+	tags, err := GetCLBTags(sess, names)
+	if err != nil {
+		return nil, err
+	}
+	var tagged []*ClassicLoadBalancer
+	for _, lb := range lbs {
+		tagged = append(tagged, &ClassicLoadBalancer{LoadBalancerDescription: lb, Tags: tags[aws.StringValue(lb.LoadBalancerName)]})
+	}
+	return tagged, nil
+}
+
+// TagALBAndNLBs attaches the tags of every Application & Network Load Balancer, described in batches with DescribeTags
+func TagALBAndNLBs(sess *session.Session, lbs []*elbv2.LoadBalancer) ([]*ApplicationNetworkLoadBalancer, error) {
+	var arns []string
+	for _, lb := range lbs {
+		arns = append(arns, aws.StringValue(lb.LoadBalancerArn))
+	}
+	tags, err := GetALBAndNLBTags(sess, arns)
+	if err != nil {
+		return nil, err
+	}
+	var tagged []*ApplicationNetworkLoadBalancer
+	for _, lb := range lbs {
+		tagged = append(tagged, &ApplicationNetworkLoadBalancer{LoadBalancer: lb, Tags: tags[aws.StringValue(lb.LoadBalancerArn)]})
+	}
+	return tagged, nil
+}
+
+// GetCLBInstanceHealth returns the state of every instance registered with the named Classic Load Balancer
+func GetCLBInstanceHealth(sess *session.Session, name string) ([]*elb.InstanceState, error) {
+	lb := elb.New(sess)
+	input := elb.DescribeInstanceHealthInput{LoadBalancerName: aws.String(name)}
+	b := newBackoff()
+	for {
+		result, err := lb.DescribeInstanceHealth(&input)
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return nil, err
+		}
+		return result.InstanceStates, nil
+	}
+}
+
+// DescribeCLBInstanceHealth attaches the health of the registered instances to every Classic Load Balancer
+func DescribeCLBInstanceHealth(sess *session.Session, lbs []*ClassicLoadBalancer) error {
+	for _, lb := range lbs {
+		health, err := GetCLBInstanceHealth(sess, aws.StringValue(lb.LoadBalancerName))
+		if err != nil {
+			return err
+		}
+		lb.InstanceHealth = health
+	}
+	return nil
+}
+
+// DescribeALBAndNLBTargets attaches the listeners with their rules and the target groups with their target health
+// to every Application & Network Load Balancer
+func DescribeALBAndNLBTargets(sess *session.Session, lbs []*ApplicationNetworkLoadBalancer) error {
+	for _, lb := range lbs {
+		lbArn := aws.StringValue(lb.LoadBalancerArn)
+		listeners, err := GetListeners(sess, lbArn)
+		if err != nil {
+			return err
+		}
+		for _, l := range listeners {
+			rules, err := GetListenerRules(sess, aws.StringValue(l.ListenerArn))
+			if err != nil {
+				return err
+			}
+			lb.Listeners = append(lb.Listeners, &Listener{l, rules})
+		}
+
+		targetGroups, err := GetTargetGroups(sess, lbArn)
+		if err != nil {
+			return err
+		}
+		for _, tg := range targetGroups {
+			health, err := GetTargetHealth(sess, aws.StringValue(tg.TargetGroupArn))
+			if err != nil {
+				return err
+			}
+			lb.TargetGroups = append(lb.TargetGroups, &TargetGroup{tg, health})
+		}
+	}
+	return nil
+}
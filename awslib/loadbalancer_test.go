@@ -0,0 +1,97 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// newTestSession returns a session sending every request to handler.
+// The SDK retries are disabled so that only the retries of awslib are exercised
+func newTestSession(t *testing.T, handler http.HandlerFunc) (*session.Session, func()) {
+	server := httptest.NewServer(handler)
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		server.Close()
+		t.Fatalf("Unable to build session: %v", err)
+	}
+	return sess, server.Close
+}
+
+// elbv2Response wraps result in the XML envelope of an elbv2 action
+func elbv2Response(w http.ResponseWriter, action, result string) {
+	fmt.Fprintf(w, `<%[1]sResponse xmlns="http://elasticloadbalancing.amazonaws.com/doc/2015-12-01/"><%[1]sResult>%[2]s</%[1]sResult></%[1]sResponse>`, action, result)
+}
+
+// TestDescribeALBAndNLBTargets checks that paginated listeners get their rules and target groups their target health
+func TestDescribeALBAndNLBTargets(t *testing.T) {
+	throttled := false
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch action := r.Form.Get("Action"); action {
+		case "DescribeListeners":
+			if r.Form.Get("Marker") == "" {
+				elbv2Response(w, action, `<Listeners><member><ListenerArn>l1</ListenerArn></member></Listeners><NextMarker>next</NextMarker>`)
+				return
+			}
+			elbv2Response(w, action, `<Listeners><member><ListenerArn>l2</ListenerArn></member></Listeners>`)
+		case "DescribeRules":
+			elbv2Response(w, action, fmt.Sprintf(`<Rules><member><RuleArn>%s-rule</RuleArn></member></Rules>`, r.Form.Get("ListenerArn")))
+		case "DescribeTargetGroups":
+			elbv2Response(w, action, `<TargetGroups><member><TargetGroupArn>tg1</TargetGroupArn></member></TargetGroups>`)
+		case "DescribeTargetHealth":
+			if !throttled {
+				throttled = true
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`)
+				return
+			}
+			elbv2Response(w, action, `<TargetHealthDescriptions><member><Target><Id>i-1</Id></Target><TargetHealth><State>healthy</State></TargetHealth></member></TargetHealthDescriptions>`)
+		default:
+			t.Errorf("Unexpected action %s", action)
+		}
+	})
+	defer closeServer()
+
+	lb := &ApplicationNetworkLoadBalancer{LoadBalancer: &elbv2.LoadBalancer{LoadBalancerArn: aws.String("lb1")}}
+	if err := DescribeALBAndNLBTargets(sess, []*ApplicationNetworkLoadBalancer{lb}); err != nil {
+		t.Fatalf("Failed to describe targets: %v", err)
+	}
+	if len(lb.Listeners) != 2 {
+		t.Fatalf("Expected 2 listeners, found %d", len(lb.Listeners))
+	}
+	for _, l := range lb.Listeners {
+		if len(l.Rules) != 1 || aws.StringValue(l.Rules[0].RuleArn) != aws.StringValue(l.ListenerArn)+"-rule" {
+			t.Errorf("Unexpected rules for %s: %v", aws.StringValue(l.ListenerArn), l.Rules)
+		}
+	}
+	if len(lb.TargetGroups) != 1 || len(lb.TargetGroups[0].TargetHealth) != 1 {
+		t.Fatalf("Unexpected target groups: %v", lb.TargetGroups)
+	}
+	if state := aws.StringValue(lb.TargetGroups[0].TargetHealth[0].TargetHealth.State); state != "healthy" {
+		t.Errorf("Expected a healthy target, found %s", state)
+	}
+}
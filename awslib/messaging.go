@@ -0,0 +1,230 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// maxListQueues is the page size of ListQueues, which only paginates when a maximum is given
+const maxListQueues = 1000
+
+// Queue is an SQS queue along with all its attributes, such as its retention period, encryption and redrive policy.
+// DeadLetterTargetArn is read from the redrive policy and empty without dead-letter queue
+type Queue struct {
+	URL                 string
+	Attributes          map[string]*string
+	DeadLetterTargetArn string `json:",omitempty"`
+	Stack               string `json:",omitempty"`
+}
+
+// Topic is an SNS topic along with its attributes and subscriptions
+type Topic struct {
+	TopicArn      string
+	Attributes    map[string]*string
+	Subscriptions []*sns.Subscription
+	Stack         string `json:",omitempty"`
+}
+
+// EventBus is an EventBridge event bus along with its rules
+type EventBus struct {
+	*eventbridge.EventBus
+	Rules []*EventRule
+	Stack string `json:",omitempty"`
+}
+
+// EventRule is an EventBridge rule along with its targets
+type EventRule struct {
+	*eventbridge.Rule
+	Targets []*eventbridge.Target
+}
+
+// GetAllQueues returns a complete list of SQS queues with their attributes for a given session
+func GetAllQueues(sess *session.Session) ([]*Queue, error) {
+	sqsc := sqs.New(sess)
+	var urls []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := sqsc.ListQueues(&sqs.ListQueuesInput{MaxResults: aws.Int64(maxListQueues), NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, result.QueueUrls...)
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var queues []*Queue
+	for _, url := range urls {
+		var result *sqs.GetQueueAttributesOutput
+		err := retry(func() (err error) {
+			result, err = sqsc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+				QueueUrl:       url,
+				AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameAll}),
+			})
+			return err
+		})
+		if err != nil {
+			return queues, err
+		}
+		queue := &Queue{URL: aws.StringValue(url), Attributes: result.Attributes}
+		if policy := aws.StringValue(result.Attributes[sqs.QueueAttributeNameRedrivePolicy]); policy != "" {
+			var redrive struct {
+				DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+			}
+			if err := json.Unmarshal([]byte(policy), &redrive); err == nil {
+				queue.DeadLetterTargetArn = redrive.DeadLetterTargetArn
+			}
+		}
+		queues = append(queues, queue)
+	}
+	return queues, nil
+}
+
+// GetAllTopics returns a complete list of SNS topics with their attributes and subscriptions for a given session
+func GetAllTopics(sess *session.Session) ([]*Topic, error) {
+	snsc := sns.New(sess)
+	var topics []*Topic
+	byArn := make(map[string]*Topic)
+	err := paginate(func(token *string) (*string, error) {
+		result, err := snsc.ListTopics(&sns.ListTopicsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range result.Topics {
+			topic := &Topic{TopicArn: aws.StringValue(t.TopicArn)}
+			topics = append(topics, topic)
+			byArn[topic.TopicArn] = topic
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range topics {
+		var result *sns.GetTopicAttributesOutput
+		err := retry(func() (err error) {
+			result, err = snsc.GetTopicAttributes(&sns.GetTopicAttributesInput{TopicArn: aws.String(topic.TopicArn)})
+			return err
+		})
+		if err != nil {
+			return topics, err
+		}
+		topic.Attributes = result.Attributes
+	}
+	err = paginate(func(token *string) (*string, error) {
+		result, err := snsc.ListSubscriptions(&sns.ListSubscriptionsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range result.Subscriptions {
+			if topic, ok := byArn[aws.StringValue(s.TopicArn)]; ok {
+				topic.Subscriptions = append(topic.Subscriptions, s)
+			}
+		}
+		return result.NextToken, nil
+	})
+	return topics, err
+}
+
+// GetAllStreams returns a complete list of Kinesis data streams with their shard count, retention and encryption for a given session
+func GetAllStreams(sess *session.Session) ([]*kinesis.StreamDescriptionSummary, error) {
+	kinesisc := kinesis.New(sess)
+	var names []*string
+	err := paginate(func(token *string) (*string, error) {
+		result, err := kinesisc.ListStreams(&kinesis.ListStreamsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, result.StreamNames...)
+		if !aws.BoolValue(result.HasMoreStreams) {
+			return nil, nil
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var streams []*kinesis.StreamDescriptionSummary
+	for _, name := range names {
+		var result *kinesis.DescribeStreamSummaryOutput
+		err := retry(func() (err error) {
+			result, err = kinesisc.DescribeStreamSummary(&kinesis.DescribeStreamSummaryInput{StreamName: name})
+			return err
+		})
+		if err != nil {
+			return streams, err
+		}
+		streams = append(streams, result.StreamDescriptionSummary)
+	}
+	return streams, nil
+}
+
+// GetAllEventBuses returns a complete list of EventBridge event buses with their rules and targets for a given session
+func GetAllEventBuses(sess *session.Session) ([]*EventBus, error) {
+	ebc := eventbridge.New(sess)
+	var buses []*EventBus
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ebc.ListEventBuses(&eventbridge.ListEventBusesInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range result.EventBuses {
+			buses = append(buses, &EventBus{EventBus: b})
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, bus := range buses {
+		err := paginate(func(token *string) (*string, error) {
+			result, err := ebc.ListRules(&eventbridge.ListRulesInput{EventBusName: bus.Name, NextToken: token})
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range result.Rules {
+				bus.Rules = append(bus.Rules, &EventRule{Rule: r})
+			}
+			return result.NextToken, nil
+		})
+		if err != nil {
+			return buses, err
+		}
+		for _, rule := range bus.Rules {
+			err := paginate(func(token *string) (*string, error) {
+				result, err := ebc.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{
+					EventBusName: bus.Name,
+					Rule:         rule.Name,
+					NextToken:    token,
+				})
+				if err != nil {
+					return nil, err
+				}
+				rule.Targets = append(rule.Targets, result.Targets...)
+				return result.NextToken, nil
+			})
+			if err != nil {
+				return buses, err
+			}
+		}
+	}
+	return buses, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Network is the VPC networking inventory of a region
+type Network struct {
+	Vpcs                      []*ec2.Vpc
+	Subnets                   []*ec2.Subnet
+	RouteTables               []*ec2.RouteTable
+	InternetGateways          []*ec2.InternetGateway
+	NatGateways               []*ec2.NatGateway
+	VpcPeeringConnections     []*ec2.VpcPeeringConnection
+	TransitGatewayAttachments []*ec2.TransitGatewayAttachment
+	VpcEndpoints              []*ec2.VpcEndpoint
+}
+
+// GetNetwork returns the VPCs, subnets, route tables, gateways, peering connections,
+// transit gateway attachments and VPC endpoints for a given session
+func GetNetwork(sess *session.Session) (*Network, error) {
+	var n Network
+	var err error
+	if n.Vpcs, err = GetAllVpcs(sess); err != nil {
+		return nil, err
+	}
+	if n.Subnets, err = GetAllSubnets(sess); err != nil {
+		return nil, err
+	}
+	if n.RouteTables, err = GetAllRouteTables(sess); err != nil {
+		return nil, err
+	}
+	if n.InternetGateways, err = GetAllInternetGateways(sess); err != nil {
+		return nil, err
+	}
+	if n.NatGateways, err = GetAllNatGateways(sess); err != nil {
+		return nil, err
+	}
+	if n.VpcPeeringConnections, err = GetAllVpcPeeringConnections(sess); err != nil {
+		return nil, err
+	}
+	if n.TransitGatewayAttachments, err = GetAllTransitGatewayAttachments(sess); err != nil {
+		return nil, err
+	}
+	if n.VpcEndpoints, err = GetAllVpcEndpoints(sess); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// GetAllVpcs returns all VPCs for a given session
+func GetAllVpcs(sess *session.Session) ([]*ec2.Vpc, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.Vpc
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeVpcs(&ec2.DescribeVpcsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.Vpcs...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllSubnets returns all subnets, along with their CIDRs and available IP counts, for a given session
+func GetAllSubnets(sess *session.Session) ([]*ec2.Subnet, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.Subnet
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeSubnets(&ec2.DescribeSubnetsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.Subnets...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllRouteTables returns all route tables for a given session
+func GetAllRouteTables(sess *session.Session) ([]*ec2.RouteTable, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.RouteTable
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeRouteTables(&ec2.DescribeRouteTablesInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.RouteTables...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllInternetGateways returns all internet gateways for a given session
+func GetAllInternetGateways(sess *session.Session) ([]*ec2.InternetGateway, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.InternetGateway
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.InternetGateways...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllNatGateways returns all NAT gateways for a given session
+func GetAllNatGateways(sess *session.Session) ([]*ec2.NatGateway, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.NatGateway
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.NatGateways...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllVpcPeeringConnections returns all VPC peering connections for a given session
+func GetAllVpcPeeringConnections(sess *session.Session) ([]*ec2.VpcPeeringConnection, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.VpcPeeringConnection
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeVpcPeeringConnections(&ec2.DescribeVpcPeeringConnectionsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.VpcPeeringConnections...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllTransitGatewayAttachments returns all transit gateway attachments for a given session
+func GetAllTransitGatewayAttachments(sess *session.Session) ([]*ec2.TransitGatewayAttachment, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.TransitGatewayAttachment
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeTransitGatewayAttachments(&ec2.DescribeTransitGatewayAttachmentsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.TransitGatewayAttachments...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
+
+// GetAllVpcEndpoints returns all VPC endpoints for a given session
+func GetAllVpcEndpoints(sess *session.Session) ([]*ec2.VpcEndpoint, error) {
+	ec2c := ec2.New(sess)
+	var all []*ec2.VpcEndpoint
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeVpcEndpoints(&ec2.DescribeVpcEndpointsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.VpcEndpoints...)
+		return result.NextToken, nil
+	})
+	return all, err
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// TestPaginate checks that pages are requested until no token is returned and that only throttling is retried
+func TestPaginate(t *testing.T) {
+	var tokens []string
+	throttled := false
+	err := paginate(func(token *string) (*string, error) {
+		if aws.StringValue(token) == "2" && !throttled {
+			throttled = true
+			return nil, awserr.New("RequestLimitExceeded", "slow down", nil)
+		}
+		tokens = append(tokens, aws.StringValue(token))
+		switch aws.StringValue(token) {
+		case "":
+			return aws.String("2"), nil
+		case "2":
+			return aws.String("3"), nil
+		}
+		return aws.String(""), nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(tokens) != "[ 2 3]" {
+		t.Errorf("Unexpected tokens: %q", tokens)
+	}
+
+	calls := 0
+	err = paginate(func(token *string) (*string, error) {
+		calls++
+		return nil, errors.New("denied")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Expected a single failing call, found %d calls and %v", calls, err)
+	}
+}
+
+// TestGetNetwork checks that every networking resource is gathered across pages
+func TestGetNetwork(t *testing.T) {
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		action := r.Form.Get("Action")
+		var body string
+		switch action {
+		case "DescribeVpcs":
+			if r.Form.Get("NextToken") == "" {
+				body = `<vpcSet><item><vpcId>vpc-1</vpcId></item></vpcSet><nextToken>next</nextToken>`
+			} else {
+				body = `<vpcSet><item><vpcId>vpc-2</vpcId></item></vpcSet>`
+			}
+		case "DescribeSubnets":
+			body = `<subnetSet><item><subnetId>subnet-1</subnetId><cidrBlock>10.0.0.0/24</cidrBlock><availableIpAddressCount>251</availableIpAddressCount></item></subnetSet>`
+		case "DescribeTransitGatewayAttachments":
+			body = `<transitGatewayAttachments><item><transitGatewayAttachmentId>tgw-attach-1</transitGatewayAttachmentId></item></transitGatewayAttachments>`
+		case "DescribeRouteTables", "DescribeInternetGateways", "DescribeNatGateways", "DescribeVpcPeeringConnections", "DescribeVpcEndpoints":
+		default:
+			t.Errorf("Unexpected action %s", action)
+		}
+		fmt.Fprintf(w, `<%[1]sResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">%[2]s</%[1]sResponse>`, action, body)
+	})
+	defer closeServer()
+
+	n, err := GetNetwork(sess)
+	if err != nil {
+		t.Fatalf("Failed to get network: %v", err)
+	}
+	if len(n.Vpcs) != 2 || aws.StringValue(n.Vpcs[1].VpcId) != "vpc-2" {
+		t.Errorf("Unexpected VPCs: %v", n.Vpcs)
+	}
+	if len(n.Subnets) != 1 || aws.Int64Value(n.Subnets[0].AvailableIpAddressCount) != 251 {
+		t.Errorf("Unexpected subnets: %v", n.Subnets)
+	}
+	if len(n.TransitGatewayAttachments) != 1 || len(n.RouteTables) != 0 {
+		t.Errorf("Unexpected network: %+v", n)
+	}
+}
+
+// TestGetAllSecurityGroups checks that security groups list the network interfaces they are attached to
+func TestGetAllSecurityGroups(t *testing.T) {
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		action := r.Form.Get("Action")
+		var body string
+		switch action {
+		case "DescribeSecurityGroups":
+			body = `<securityGroupInfo><item><groupId>sg-1</groupId></item><item><groupId>sg-2</groupId></item></securityGroupInfo>`
+		case "DescribeNetworkInterfaces":
+			if r.Form.Get("NextToken") == "" {
+				body = `<networkInterfaceSet><item><networkInterfaceId>eni-1</networkInterfaceId><groupSet><item><groupId>sg-1</groupId></item></groupSet></item></networkInterfaceSet><nextToken>next</nextToken>`
+			} else {
+				body = `<networkInterfaceSet><item><networkInterfaceId>eni-2</networkInterfaceId><groupSet><item><groupId>sg-1</groupId></item></groupSet></item></networkInterfaceSet>`
+			}
+		default:
+			t.Errorf("Unexpected action %s", action)
+		}
+		fmt.Fprintf(w, `<%[1]sResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">%[2]s</%[1]sResponse>`, action, body)
+	})
+	defer closeServer()
+
+	groups, err := GetAllSecurityGroups(sess)
+	if err != nil {
+		t.Fatalf("Failed to get security groups: %v", err)
+	}
+	if len(groups) != 2 || fmt.Sprint(groups[0].NetworkInterfaces) != "[eni-1 eni-2]" || len(groups[1].NetworkInterfaces) != 0 {
+		t.Errorf("Unexpected security groups: %v", groups)
+	}
+}
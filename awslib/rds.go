@@ -22,6 +22,12 @@ import (
 	"github.com/jpillora/backoff"
 )
 
+// DBInstance is an RDS DB instance, its tags being in the TagList returned by DescribeDBInstances
+type DBInstance struct {
+	*rds.DBInstance
+	Stack string `json:",omitempty"`
+}
+
 //GetAllDBInstances resturns a complete list of DBInstances for a given session
 func GetAllDBInstances(sess *session.Session) ([]*rds.DBInstance, error) {
 	rdsc := rds.New(sess)
@@ -61,3 +67,12 @@ func GetAllDBInstances(sess *session.Session) ([]*rds.DBInstance, error) {
 	}
 	return allInstances, nil
 }
+
+// NewDBInstances wraps RDS DB instances, leaving their owning stack empty
+func NewDBInstances(instances []*rds.DBInstance) []*DBInstance {
+	var all []*DBInstance
+	for _, i := range instances {
+		all = append(all, &DBInstance{DBInstance: i})
+	}
+	return all
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// RDSTopology holds the DB clusters, the global databases they belong to and the manual snapshots of a region
+type RDSTopology struct {
+	Clusters         []*DBCluster
+	GlobalClusters   []*rds.GlobalCluster
+	Snapshots        []*DBSnapshot
+	ClusterSnapshots []*DBClusterSnapshot
+}
+
+// DBCluster is an RDS DB cluster along with its custom endpoints.
+// Cluster members are listed in DBClusterMembers
+type DBCluster struct {
+	*rds.DBCluster
+	CustomEndpoints []*rds.DBClusterEndpoint
+	Stack           string `json:",omitempty"`
+}
+
+// SnapshotSharing tells whether a manual snapshot can be restored by anyone or by other accounts
+type SnapshotSharing struct {
+	Public     bool
+	SharedWith []string `json:",omitempty"`
+}
+
+// DBSnapshot is a manual DB instance snapshot along with its sharing status
+type DBSnapshot struct {
+	*rds.DBSnapshot
+	SnapshotSharing
+}
+
+// DBClusterSnapshot is a manual DB cluster snapshot along with its sharing status
+type DBClusterSnapshot struct {
+	*rds.DBClusterSnapshot
+	SnapshotSharing
+}
+
+// GetRDSTopology returns the DB clusters, global databases and manual snapshots for a given session.
+// Only the global databases with a member cluster in the session region are returned
+func GetRDSTopology(sess *session.Session) (*RDSTopology, error) {
+	var t RDSTopology
+	var err error
+	if t.Clusters, err = GetAllDBClusters(sess); err != nil {
+		return nil, err
+	}
+	if t.GlobalClusters, err = GetGlobalClusters(sess, t.Clusters); err != nil {
+		return nil, err
+	}
+	if t.Snapshots, err = GetAllDBSnapshots(sess); err != nil {
+		return nil, err
+	}
+	if t.ClusterSnapshots, err = GetAllDBClusterSnapshots(sess); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetAllDBClusters returns a complete list of DB clusters with their custom endpoints for a given session
+func GetAllDBClusters(sess *session.Session) ([]*DBCluster, error) {
+	rdsc := rds.New(sess)
+	var all []*DBCluster
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := rdsc.DescribeDBClusters(&rds.DescribeDBClustersInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range result.DBClusters {
+			all = append(all, &DBCluster{DBCluster: c})
+		}
+		return result.Marker, nil
+	})
+	if err != nil {
+		return all, err
+	}
+	for _, c := range all {
+		err := paginate(func(marker *string) (*string, error) {
+			result, err := rdsc.DescribeDBClusterEndpoints(&rds.DescribeDBClusterEndpointsInput{
+				DBClusterIdentifier: c.DBClusterIdentifier,
+				Marker:              marker,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range result.DBClusterEndpoints {
+				// The writer and reader endpoints are already part of the cluster
+				if aws.StringValue(e.EndpointType) == "CUSTOM" {
+					c.CustomEndpoints = append(c.CustomEndpoints, e)
+				}
+			}
+			return result.Marker, nil
+		})
+		if err != nil {
+			return all, err
+		}
+	}
+	return all, nil
+}
+
+// GetGlobalClusters returns the global databases with one of the given clusters as a member
+func GetGlobalClusters(sess *session.Session, clusters []*DBCluster) ([]*rds.GlobalCluster, error) {
+	local := make(map[string]bool)
+	for _, c := range clusters {
+		local[aws.StringValue(c.DBClusterArn)] = true
+	}
+	rdsc := rds.New(sess)
+	var all []*rds.GlobalCluster
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := rdsc.DescribeGlobalClusters(&rds.DescribeGlobalClustersInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range result.GlobalClusters {
+			for _, m := range g.GlobalClusterMembers {
+				if local[aws.StringValue(m.DBClusterArn)] {
+					all = append(all, g)
+					break
+				}
+			}
+		}
+		return result.Marker, nil
+	})
+	return all, err
+}
+
+// sharingFromAttributes reads the restore attribute of a snapshot, listing the accounts allowed to restore it or all
+func sharingFromAttributes(name *string, values []*string) SnapshotSharing {
+	var sharing SnapshotSharing
+	if aws.StringValue(name) != "restore" {
+		return sharing
+	}
+	for _, v := range aws.StringValueSlice(values) {
+		if v == "all" {
+			sharing.Public = true
+			continue
+		}
+		sharing.SharedWith = append(sharing.SharedWith, v)
+	}
+	return sharing
+}
+
+// GetAllDBSnapshots returns a complete list of manual DB instance snapshots with their sharing status for a given session
+func GetAllDBSnapshots(sess *session.Session) ([]*DBSnapshot, error) {
+	rdsc := rds.New(sess)
+	var all []*DBSnapshot
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := rdsc.DescribeDBSnapshots(&rds.DescribeDBSnapshotsInput{SnapshotType: aws.String("manual"), Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range result.DBSnapshots {
+			all = append(all, &DBSnapshot{DBSnapshot: s})
+		}
+		return result.Marker, nil
+	})
+	if err != nil {
+		return all, err
+	}
+	for _, s := range all {
+		var result *rds.DescribeDBSnapshotAttributesOutput
+		err := retry(func() (err error) {
+			result, err = rdsc.DescribeDBSnapshotAttributes(&rds.DescribeDBSnapshotAttributesInput{DBSnapshotIdentifier: s.DBSnapshotIdentifier})
+			return err
+		})
+		if err != nil {
+			return all, err
+		}
+		if result.DBSnapshotAttributesResult == nil {
+			continue
+		}
+		for _, a := range result.DBSnapshotAttributesResult.DBSnapshotAttributes {
+			if sharing := sharingFromAttributes(a.AttributeName, a.AttributeValues); sharing.Public || len(sharing.SharedWith) > 0 {
+				s.SnapshotSharing = sharing
+			}
+		}
+	}
+	return all, nil
+}
+
+// GetAllDBClusterSnapshots returns a complete list of manual DB cluster snapshots with their sharing status for a given session
+func GetAllDBClusterSnapshots(sess *session.Session) ([]*DBClusterSnapshot, error) {
+	rdsc := rds.New(sess)
+	var all []*DBClusterSnapshot
+	err := paginate(func(marker *string) (*string, error) {
+		result, err := rdsc.DescribeDBClusterSnapshots(&rds.DescribeDBClusterSnapshotsInput{SnapshotType: aws.String("manual"), Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range result.DBClusterSnapshots {
+			all = append(all, &DBClusterSnapshot{DBClusterSnapshot: s})
+		}
+		return result.Marker, nil
+	})
+	if err != nil {
+		return all, err
+	}
+	for _, s := range all {
+		var result *rds.DescribeDBClusterSnapshotAttributesOutput
+		err := retry(func() (err error) {
+			result, err = rdsc.DescribeDBClusterSnapshotAttributes(&rds.DescribeDBClusterSnapshotAttributesInput{DBClusterSnapshotIdentifier: s.DBClusterSnapshotIdentifier})
+			return err
+		})
+		if err != nil {
+			return all, err
+		}
+		if result.DBClusterSnapshotAttributesResult == nil {
+			continue
+		}
+		for _, a := range result.DBClusterSnapshotAttributesResult.DBClusterSnapshotAttributes {
+			if sharing := sharingFromAttributes(a.AttributeName, a.AttributeValues); sharing.Public || len(sharing.SharedWith) > 0 {
+				s.SnapshotSharing = sharing
+			}
+		}
+	}
+	return all, nil
+}
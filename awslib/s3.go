@@ -0,0 +1,175 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Bucket is an S3 bucket along with its region and security posture.
+// Settings that are not configured on the bucket are left empty, DeniedOperations lists the calls a bucket policy denied
+type Bucket struct {
+	*s3.Bucket
+	Region            string
+	Encryption        *s3.ServerSideEncryptionConfiguration `json:",omitempty"`
+	VersioningStatus  string                                `json:",omitempty"`
+	MFADelete         string                                `json:",omitempty"`
+	PublicAccessBlock *s3.PublicAccessBlockConfiguration    `json:",omitempty"`
+	PolicyStatus      *s3.PolicyStatus                      `json:",omitempty"`
+	Logging           *s3.LoggingEnabled                    `json:",omitempty"`
+	LifecycleRules    []*s3.LifecycleRule                   `json:",omitempty"`
+	Tags              []*s3.Tag
+	DeniedOperations  []string `json:",omitempty"`
+	Stack             string   `json:",omitempty"`
+}
+
+// GetAllBuckets returns every bucket of the account. Buckets are global to a partition, any session can list them
+func GetAllBuckets(sess *session.Session) ([]*s3.Bucket, error) {
+	s3c := s3.New(sess)
+	b := newBackoff()
+	for {
+		result, err := s3c.ListBuckets(&s3.ListBucketsInput{})
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return nil, err
+		}
+		return result.Buckets, nil
+	}
+}
+
+// GetBucketRegion returns the region a bucket lives in
+func GetBucketRegion(sess *session.Session, name string) (string, error) {
+	s3c := s3.New(sess)
+	b := newBackoff()
+	for {
+		result, err := s3c.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String(name)})
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return "", err
+		}
+		return s3.NormalizeBucketLocation(aws.StringValue(result.LocationConstraint)), nil
+	}
+}
+
+// isNotConfigured reports whether err tells a bucket setting is simply not configured
+func isNotConfigured(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "ServerSideEncryptionConfigurationNotFoundError", "NoSuchPublicAccessBlockConfiguration",
+			"NoSuchBucketPolicy", "NoSuchLifecycleConfiguration", "NoSuchTagSet":
+			return true
+		}
+	}
+	return false
+}
+
+// retryBucketCall calls fn until it is not throttled, ignoring settings that are not configured
+func retryBucketCall(fn func() error) error {
+	b := newBackoff()
+	for {
+		err := fn()
+		if err == nil || isNotConfigured(err) {
+			return nil
+		}
+		// Retry with backoff incase Rate has been exceeded
+		if isRateExceeded(err) {
+			time.Sleep(b.Duration())
+			continue
+		}
+		return err
+	}
+}
+
+// DescribeBucket fills the security posture of a bucket. The session must be in the bucket region.
+// Calls denied by the bucket policy are recorded in DeniedOperations and leave their setting empty
+func DescribeBucket(sess *session.Session, bucket *Bucket) error {
+	s3c := s3.New(sess)
+	name := bucket.Name
+	calls := []struct {
+		operation string
+		call      func() error
+	}{
+		{"GetBucketEncryption", func() error {
+			result, err := s3c.GetBucketEncryption(&s3.GetBucketEncryptionInput{Bucket: name})
+			if err == nil {
+				bucket.Encryption = result.ServerSideEncryptionConfiguration
+			}
+			return err
+		}},
+		{"GetBucketVersioning", func() error {
+			result, err := s3c.GetBucketVersioning(&s3.GetBucketVersioningInput{Bucket: name})
+			if err == nil {
+				bucket.VersioningStatus = aws.StringValue(result.Status)
+				bucket.MFADelete = aws.StringValue(result.MFADelete)
+			}
+			return err
+		}},
+		{"GetPublicAccessBlock", func() error {
+			result, err := s3c.GetPublicAccessBlock(&s3.GetPublicAccessBlockInput{Bucket: name})
+			if err == nil {
+				bucket.PublicAccessBlock = result.PublicAccessBlockConfiguration
+			}
+			return err
+		}},
+		{"GetBucketPolicyStatus", func() error {
+			result, err := s3c.GetBucketPolicyStatus(&s3.GetBucketPolicyStatusInput{Bucket: name})
+			if err == nil {
+				bucket.PolicyStatus = result.PolicyStatus
+			}
+			return err
+		}},
+		{"GetBucketLogging", func() error {
+			result, err := s3c.GetBucketLogging(&s3.GetBucketLoggingInput{Bucket: name})
+			if err == nil {
+				bucket.Logging = result.LoggingEnabled
+			}
+			return err
+		}},
+		{"GetBucketLifecycleConfiguration", func() error {
+			result, err := s3c.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{Bucket: name})
+			if err == nil {
+				bucket.LifecycleRules = result.Rules
+			}
+			return err
+		}},
+		{"GetBucketTagging", func() error {
+			result, err := s3c.GetBucketTagging(&s3.GetBucketTaggingInput{Bucket: name})
+			if err == nil {
+				bucket.Tags = result.TagSet
+			}
+			return err
+		}},
+	}
+	for _, c := range calls {
+		err := retryBucketCall(c.call)
+		if isAccessDenied(err) {
+			bucket.DeniedOperations = append(bucket.DeniedOperations, c.operation)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
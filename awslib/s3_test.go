@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// s3Error answers an S3 call with the given error code
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+// TestDescribeBucket checks that settings not configured are left empty, throttled calls retried and denied calls
+// recorded, while other errors fail the bucket
+func TestDescribeBucket(t *testing.T) {
+	throttled := false
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/broken") {
+			s3Error(w, http.StatusInternalServerError, "InternalError")
+			return
+		}
+		query := r.URL.Query()
+		switch {
+		case query.Has("encryption"):
+			s3Error(w, http.StatusNotFound, "ServerSideEncryptionConfigurationNotFoundError")
+		case query.Has("versioning"):
+			fmt.Fprint(w, `<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`)
+		case query.Has("publicAccessBlock"):
+			s3Error(w, http.StatusForbidden, "AccessDenied")
+		case query.Has("policyStatus"):
+			fmt.Fprint(w, `<PolicyStatus><IsPublic>false</IsPublic></PolicyStatus>`)
+		case query.Has("logging"):
+			fmt.Fprint(w, `<BucketLoggingStatus></BucketLoggingStatus>`)
+		case query.Has("lifecycle"):
+			s3Error(w, http.StatusNotFound, "NoSuchLifecycleConfiguration")
+		case query.Has("tagging"):
+			if !throttled {
+				throttled = true
+				s3Error(w, http.StatusServiceUnavailable, "SlowDown")
+				return
+			}
+			fmt.Fprint(w, `<Tagging><TagSet><Tag><Key>Team</Key><Value>a</Value></Tag></TagSet></Tagging>`)
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	})
+	defer closeServer()
+	sess = sess.Copy(&aws.Config{S3ForcePathStyle: aws.Bool(true)})
+
+	bucket := &Bucket{Bucket: &s3.Bucket{Name: aws.String("logs")}, Region: "us-east-1"}
+	if err := DescribeBucket(sess, bucket); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bucket.Encryption != nil || bucket.LifecycleRules != nil {
+		t.Errorf("Expected settings not configured to be left empty, got %+v", bucket)
+	}
+	if bucket.VersioningStatus != "Enabled" || bucket.PolicyStatus == nil || len(bucket.Tags) != 1 {
+		t.Errorf("Expected versioning, policy status and tags, got %+v", bucket)
+	}
+	if bucket.PublicAccessBlock != nil || !reflect.DeepEqual(bucket.DeniedOperations, []string{"GetPublicAccessBlock"}) {
+		t.Errorf("Expected GetPublicAccessBlock to be denied, got %v", bucket.DeniedOperations)
+	}
+
+	broken := &Bucket{Bucket: &s3.Bucket{Name: aws.String("broken")}, Region: "us-east-1"}
+	if err := DescribeBucket(sess, broken); err == nil {
+		t.Errorf("Expected an error for a failing bucket")
+	}
+}
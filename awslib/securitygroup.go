@@ -0,0 +1,65 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// SecurityGroup is a security group along with the network interfaces it is attached to
+type SecurityGroup struct {
+	*ec2.SecurityGroup
+	NetworkInterfaces []string `json:",omitempty"`
+	Stack             string   `json:",omitempty"`
+}
+
+// GetAllSecurityGroups returns a complete list of security groups, with the IDs of the network interfaces
+// using them, for a given session
+func GetAllSecurityGroups(sess *session.Session) ([]*SecurityGroup, error) {
+	ec2c := ec2.New(sess)
+	var all []*SecurityGroup
+	err := paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, sg := range result.SecurityGroups {
+			all = append(all, &SecurityGroup{SecurityGroup: sg})
+		}
+		return result.NextToken, nil
+	})
+	if err != nil {
+		return all, err
+	}
+
+	attached := make(map[string][]string)
+	err = paginate(func(token *string) (*string, error) {
+		result, err := ec2c.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{NextToken: token})
+		if err != nil {
+			return nil, err
+		}
+		for _, eni := range result.NetworkInterfaces {
+			for _, g := range eni.Groups {
+				id := aws.StringValue(g.GroupId)
+				attached[id] = append(attached[id], aws.StringValue(eni.NetworkInterfaceId))
+			}
+		}
+		return result.NextToken, nil
+	})
+	for _, sg := range all {
+		sg.NetworkInterfaces = attached[aws.StringValue(sg.GroupId)]
+	}
+	return all, err
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// maxDescribeTags is the maximum number of load balancers accepted by a single DescribeTags call
+const maxDescribeTags = 20
+
+// GetCLBTags returns the tags of the given Classic Load Balancers keyed by load balancer name.
+// Names are described in batches of 20, the maximum allowed by DescribeTags
+func GetCLBTags(sess *session.Session, names []string) (map[string][]*elb.Tag, error) {
+	lb := elb.New(sess)
+	tags := make(map[string][]*elb.Tag)
+	b := newBackoff()
+	for start := 0; start < len(names); start += maxDescribeTags {
+		end := start + maxDescribeTags
+		if end > len(names) {
+			end = len(names)
+		}
+		input := elb.DescribeTagsInput{LoadBalancerNames: aws.StringSlice(names[start:end])}
+		for {
+			result, err := lb.DescribeTags(&input)
+			if err != nil {
+				// Retry with backoff incase Rate has been exceeded
+				if isRateExceeded(err) {
+					time.Sleep(b.Duration())
+					continue
+				}
+				return tags, err
+			}
+			b.Reset()
+			for _, desc := range result.TagDescriptions {
+				tags[aws.StringValue(desc.LoadBalancerName)] = desc.Tags
+			}
+			break
+		}
+	}
+	return tags, nil
+}
+
+// GetALBAndNLBTags returns the tags of the given Application & Network Load Balancers keyed by ARN.
+// ARNs are described in batches of 20, the maximum allowed by DescribeTags
+func GetALBAndNLBTags(sess *session.Session, arns []string) (map[string][]*elbv2.Tag, error) {
+	lb := elbv2.New(sess)
+	tags := make(map[string][]*elbv2.Tag)
+	b := newBackoff()
+	for start := 0; start < len(arns); start += maxDescribeTags {
+		end := start + maxDescribeTags
+		if end > len(arns) {
+			end = len(arns)
+		}
+		input := elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice(arns[start:end])}
+		for {
+			result, err := lb.DescribeTags(&input)
+			if err != nil {
+				// Retry with backoff incase Rate has been exceeded
+				if isRateExceeded(err) {
+					time.Sleep(b.Duration())
+					continue
+				}
+				return tags, err
+			}
+			b.Reset()
+			for _, desc := range result.TagDescriptions {
+				tags[aws.StringValue(desc.ResourceArn)] = desc.Tags
+			}
+			break
+		}
+	}
+	return tags, nil
+}
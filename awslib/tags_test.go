@@ -0,0 +1,106 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package awslib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// TestTaggedResourceJSON checks that tagged resources keep the SDK fields at the top level, tags included once
+func TestTaggedResourceJSON(t *testing.T) {
+	db := NewDBInstances([]*rds.DBInstance{{
+		DBInstanceIdentifier: aws.String("db1"),
+		TagList:              []*rds.Tag{{Key: aws.String("Owner"), Value: aws.String("team")}},
+	}})[0]
+	data, err := json.Marshal(db)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var flat map[string]interface{}
+	if err := json.Unmarshal(data, &flat); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if flat["DBInstanceIdentifier"] != "db1" || flat["TagList"] == nil || flat["Tags"] != nil {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var back DBInstance
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if aws.StringValue(back.DBInstanceIdentifier) != "db1" || len(back.TagList) != 1 {
+		t.Errorf("Unexpected round trip: %+v", back)
+	}
+}
+
+// describedTags answers a DescribeTags call with a Team tag for every load balancer of the member list named by
+// param, recording the size of the batch
+func describedTags(r *http.Request, param, idElement string, batches *[]int) string {
+	r.ParseForm()
+	var descriptions strings.Builder
+	for i := 1; ; i++ {
+		id := r.Form.Get(fmt.Sprintf("%s.member.%d", param, i))
+		if id == "" {
+			*batches = append(*batches, i-1)
+			break
+		}
+		fmt.Fprintf(&descriptions, `<member><%[1]s>%[2]s</%[1]s><Tags><member><Key>Team</Key><Value>%[2]s</Value></member></Tags></member>`, idElement, id)
+	}
+	return "<TagDescriptions>" + descriptions.String() + "</TagDescriptions>"
+}
+
+// TestGetLoadBalancerTagsBatches checks that load balancers are described 20 at a time and all get their tags
+func TestGetLoadBalancerTagsBatches(t *testing.T) {
+	var ids []string
+	for i := 0; i < 45; i++ {
+		ids = append(ids, fmt.Sprintf("lb-%d", i))
+	}
+	expected := []int{20, 20, 5}
+
+	var batches []int
+	sess, closeServer := newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		result := describedTags(r, "LoadBalancerNames", "LoadBalancerName", &batches)
+		fmt.Fprintf(w, `<DescribeTagsResponse xmlns="http://elasticloadbalancing.amazonaws.com/doc/2012-06-01/"><DescribeTagsResult>%s</DescribeTagsResult></DescribeTagsResponse>`, result)
+	})
+	defer closeServer()
+	clbTags, err := GetCLBTags(sess, ids)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(batches) != fmt.Sprint(expected) || len(clbTags) != len(ids) {
+		t.Errorf("Expected batches %v tagging %d CLBs, got %v tagging %d", expected, len(ids), batches, len(clbTags))
+	}
+	if tags := clbTags["lb-44"]; len(tags) != 1 || aws.StringValue(tags[0].Value) != "lb-44" {
+		t.Errorf("Unexpected tags of lb-44: %v", tags)
+	}
+
+	batches = nil
+	sess, closeServer = newTestSession(t, func(w http.ResponseWriter, r *http.Request) {
+		elbv2Response(w, "DescribeTags", describedTags(r, "ResourceArns", "ResourceArn", &batches))
+	})
+	defer closeServer()
+	albTags, err := GetALBAndNLBTags(sess, ids)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(batches) != fmt.Sprint(expected) || len(albTags) != len(ids) {
+		t.Errorf("Expected batches %v tagging %d ALBs and NLBs, got %v tagging %d", expected, len(ids), batches, len(albTags))
+	}
+}
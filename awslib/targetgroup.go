@@ -0,0 +1,70 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+package awslib
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// TargetGroup is a target group along with the health of its registered targets
+type TargetGroup struct {
+	*elbv2.TargetGroup
+	TargetHealth []*elbv2.TargetHealthDescription
+}
+
+// GetTargetGroups returns all target groups attached to the load balancer with the given ARN
+func GetTargetGroups(sess *session.Session, lbArn string) ([]*elbv2.TargetGroup, error) {
+	lb := elbv2.New(sess)
+	var targetGroups []*elbv2.TargetGroup
+	input := elbv2.DescribeTargetGroupsInput{LoadBalancerArn: aws.String(lbArn)}
+	b := newBackoff()
+	for {
+		result, err := lb.DescribeTargetGroups(&input)
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return targetGroups, err
+		}
+		b.Reset()
+		targetGroups = append(targetGroups, result.TargetGroups...)
+		if aws.StringValue(result.NextMarker) == "" {
+			return targetGroups, nil
+		}
+		input.SetMarker(*result.NextMarker)
+	}
+}
+
+// GetTargetHealth returns the health of every target registered with the target group with the given ARN
+func GetTargetHealth(sess *session.Session, targetGroupArn string) ([]*elbv2.TargetHealthDescription, error) {
+	lb := elbv2.New(sess)
+	input := elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(targetGroupArn)}
+	b := newBackoff()
+	for {
+		result, err := lb.DescribeTargetHealth(&input)
+		if err != nil {
+			// Retry with backoff incase Rate has been exceeded
+			if isRateExceeded(err) {
+				time.Sleep(b.Duration())
+				continue
+			}
+			return nil, err
+		}
+		return result.TargetHealthDescriptions, nil
+	}
+}
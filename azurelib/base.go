@@ -0,0 +1,171 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package azurelib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobe/cloudinventory/httplib"
+)
+
+// DefaultEndpoint is the Azure Resource Manager endpoint of the public cloud
+const DefaultEndpoint = "https://management.azure.com"
+
+// DefaultAuthorityHost is the Azure Active Directory endpoint of the public cloud
+const DefaultAuthorityHost = "https://login.microsoftonline.com"
+
+// TokenSource provides bearer tokens for the Azure Resource Manager API
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// ServicePrincipal obtains tokens with the client credentials of an Azure Active Directory application.
+// Tokens are cached until shortly before they expire
+type ServicePrincipal struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// AuthorityHost defaults to DefaultAuthorityHost
+	AuthorityHost string
+	// Scope defaults to the .default scope of DefaultEndpoint
+	Scope string
+
+	tokens httplib.TokenCache
+}
+
+// EnvironmentCredentials returns the service principal defined by the AZURE_TENANT_ID, AZURE_CLIENT_ID,
+// AZURE_CLIENT_SECRET and optional AZURE_AUTHORITY_HOST environment variables
+func EnvironmentCredentials() (*ServicePrincipal, error) {
+	sp := &ServicePrincipal{
+		TenantID:      os.Getenv("AZURE_TENANT_ID"),
+		ClientID:      os.Getenv("AZURE_CLIENT_ID"),
+		ClientSecret:  os.Getenv("AZURE_CLIENT_SECRET"),
+		AuthorityHost: os.Getenv("AZURE_AUTHORITY_HOST"),
+	}
+	if sp.TenantID == "" || sp.ClientID == "" || sp.ClientSecret == "" {
+		return nil, fmt.Errorf("AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET must be set")
+	}
+	return sp, nil
+}
+
+// Token returns a cached token, or requests a new one with the client credentials grant
+func (sp *ServicePrincipal) Token() (string, error) {
+	return sp.tokens.Token(sp.requestToken)
+}
+
+// requestToken requests a token with the client credentials grant
+func (sp *ServicePrincipal) requestToken() (string, time.Time, error) {
+	authority := sp.AuthorityHost
+	if authority == "" {
+		authority = DefaultAuthorityHost
+	}
+	scope := sp.Scope
+	if scope == "" {
+		scope = DefaultEndpoint + "/.default"
+	}
+	resp, err := http.PostForm(strings.TrimSuffix(authority, "/")+"/"+url.PathEscape(sp.TenantID)+"/oauth2/v2.0/token", url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {sp.ClientID},
+		"client_secret": {sp.ClientSecret},
+		"scope":         {scope},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", time.Time{}, fmt.Errorf("Invalid token response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("Failed to get Azure token: %s %s", result.Error, result.ErrorDescription)
+	}
+	return result.AccessToken, time.Now().Add(time.Duration(result.ExpiresIn) * time.Second), nil
+}
+
+// Client is an Azure Resource Manager API client
+type Client struct {
+	// Endpoint defaults to DefaultEndpoint
+	Endpoint    string
+	Credentials TokenSource
+	HTTPClient  *http.Client
+}
+
+// NewClient returns a client of the public cloud Resource Manager endpoint
+func NewClient(creds TokenSource) *Client {
+	return &Client{Endpoint: DefaultEndpoint, Credentials: creds, HTTPClient: http.DefaultClient}
+}
+
+// get decodes the JSON response of a GET request, throttled requests being retried by httplib.Get
+func (c *Client) get(rawURL string, out interface{}) error {
+	body, err := httplib.Get(c.HTTPClient, rawURL, func(req *http.Request) error {
+		token, err := c.Credentials.Token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+	if serr, ok := err.(*httplib.StatusError); ok {
+		var armErr struct {
+			Error struct {
+				Code    string
+				Message string
+			}
+		}
+		if json.Unmarshal(serr.Body, &armErr) == nil && armErr.Error.Code != "" {
+			return fmt.Errorf("%s: %s", armErr.Error.Code, armErr.Error.Message)
+		}
+		return serr
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// list calls page with every item of a Resource Manager collection, following its next links
+func (c *Client) list(path, apiVersion string, page func(item json.RawMessage) error) error {
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	next := strings.TrimSuffix(endpoint, "/") + path + "?api-version=" + apiVersion
+	for next != "" {
+		var result struct {
+			Value    []json.RawMessage `json:"value"`
+			NextLink string            `json:"nextLink"`
+		}
+		if err := c.get(next, &result); err != nil {
+			return err
+		}
+		for _, item := range result.Value {
+			if err := page(item); err != nil {
+				return err
+			}
+		}
+		next = result.NextLink
+	}
+	return nil
+}
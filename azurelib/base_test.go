@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package azurelib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newStandIn returns a stand-in for the token and Resource Manager endpoints, serving the given paths.
+// Retries and token caching are left to the httplib tests
+func newStandIn(t *testing.T, pages map[string]string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+			r.ParseForm()
+			if r.Form.Get("client_secret") != "secret" || r.Form.Get("grant_type") != "client_credentials" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error": "invalid_client", "error_description": "bad secret"}`)
+				return
+			}
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Missing bearer token on %s", r.URL)
+		}
+		page, ok := pages[r.URL.Path+"?"+r.URL.RawQuery]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": "ResourceNotFound", "message": "not found"}}`)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(page, "{server}", server.URL, -1))
+	}))
+	return server
+}
+
+func TestList(t *testing.T) {
+	server := newStandIn(t, map[string]string{
+		"/subscriptions/s1/providers/Microsoft.Compute/virtualMachines?api-version=" + computeAPIVersion: `{
+			"value": [{"id": "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/web", "name": "web",
+				"location": "westeurope", "tags": {"env": "prod"}, "properties": {"hardwareProfile": {"vmSize": "Standard_B2s"}}}],
+			"nextLink": "{server}/subscriptions/s1/providers/Microsoft.Compute/virtualMachines?api-version=` + computeAPIVersion + `&skiptoken=2"}`,
+		"/subscriptions/s1/providers/Microsoft.Compute/virtualMachines?api-version=" + computeAPIVersion + "&skiptoken=2": `{
+			"value": [{"id": "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/db", "name": "db", "location": "northeurope"}]}`,
+	})
+	defer server.Close()
+
+	c := &Client{Endpoint: server.URL, HTTPClient: server.Client(),
+		Credentials: &ServicePrincipal{TenantID: "tenant", ClientID: "app", ClientSecret: "secret", AuthorityHost: server.URL}}
+	vms, err := GetAllVirtualMachines(c, "s1")
+	if err != nil {
+		t.Fatalf("Failed to list virtual machines: %v", err)
+	}
+	if len(vms) != 2 || vms[0].Name != "web" || vms[0].Tags["env"] != "prod" || vms[1].Location != "northeurope" {
+		t.Errorf("Unexpected virtual machines %+v", vms)
+	}
+	if !strings.Contains(string(vms[0].Properties), "Standard_B2s") {
+		t.Errorf("Expected the properties to be kept, got %s", vms[0].Properties)
+	}
+	_, err = GetAllDisks(c, "s1")
+	if err == nil || err.Error() != "ResourceNotFound: not found" {
+		t.Errorf("Expected the Resource Manager error, got %v", err)
+	}
+}
+
+func TestServicePrincipalError(t *testing.T) {
+	server := newStandIn(t, nil)
+	defer server.Close()
+
+	sp := &ServicePrincipal{TenantID: "tenant", ClientID: "app", ClientSecret: "wrong", AuthorityHost: server.URL}
+	if _, err := sp.Token(); err == nil || !strings.Contains(err.Error(), "bad secret") {
+		t.Errorf("Expected the token error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package azurelib
+
+import (
+	"encoding/json"
+)
+
+// API versions of the Resource Manager providers
+const (
+	subscriptionsAPIVersion = "2022-12-01"
+	computeAPIVersion       = "2024-07-01"
+	disksAPIVersion         = "2024-03-02"
+	sqlAPIVersion           = "2021-11-01"
+	networkAPIVersion       = "2024-05-01"
+	dnsAPIVersion           = "2018-05-01"
+)
+
+// Subscription is an Azure subscription the credentials have access to
+type Subscription struct {
+	SubscriptionID string `json:"subscriptionId"`
+	DisplayName    string `json:"displayName"`
+	State          string `json:"state"`
+	TenantID       string `json:"tenantId,omitempty"`
+}
+
+// Resource is an Azure Resource Manager resource. Properties are kept as returned by the provider API
+type Resource struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	SKU        *SKU              `json:"sku,omitempty"`
+	Zones      []string          `json:"zones,omitempty"`
+	ManagedBy  string            `json:"managedBy,omitempty"`
+	Properties json.RawMessage   `json:"properties,omitempty"`
+}
+
+// SKU is the pricing tier of a resource
+type SKU struct {
+	Name     string `json:"name,omitempty"`
+	Tier     string `json:"tier,omitempty"`
+	Capacity int    `json:"capacity,omitempty"`
+}
+
+// SQLServer is an Azure SQL logical server along with its databases
+type SQLServer struct {
+	*Resource
+	Databases []*Resource
+}
+
+// GetAllSubscriptions returns the subscriptions the client credentials have access to
+func GetAllSubscriptions(c *Client) ([]*Subscription, error) {
+	var subscriptions []*Subscription
+	err := c.list("/subscriptions", subscriptionsAPIVersion, func(item json.RawMessage) error {
+		var s Subscription
+		if err := json.Unmarshal(item, &s); err != nil {
+			return err
+		}
+		subscriptions = append(subscriptions, &s)
+		return nil
+	})
+	return subscriptions, err
+}
+
+// listResources returns every resource of a collection
+func listResources(c *Client, path, apiVersion string) ([]*Resource, error) {
+	var resources []*Resource
+	err := c.list(path, apiVersion, func(item json.RawMessage) error {
+		var r Resource
+		if err := json.Unmarshal(item, &r); err != nil {
+			return err
+		}
+		resources = append(resources, &r)
+		return nil
+	})
+	return resources, err
+}
+
+// GetAllVirtualMachines returns a complete list of virtual machines for a given subscription
+func GetAllVirtualMachines(c *Client, subscriptionID string) ([]*Resource, error) {
+	return listResources(c, "/subscriptions/"+subscriptionID+"/providers/Microsoft.Compute/virtualMachines", computeAPIVersion)
+}
+
+// GetAllDisks returns a complete list of managed disks for a given subscription
+func GetAllDisks(c *Client, subscriptionID string) ([]*Resource, error) {
+	return listResources(c, "/subscriptions/"+subscriptionID+"/providers/Microsoft.Compute/disks", disksAPIVersion)
+}
+
+// GetAllSQLServers returns a complete list of SQL servers with their databases for a given subscription.
+// The master database of every server is skipped
+func GetAllSQLServers(c *Client, subscriptionID string) ([]*SQLServer, error) {
+	servers, err := listResources(c, "/subscriptions/"+subscriptionID+"/providers/Microsoft.Sql/servers", sqlAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	var all []*SQLServer
+	for _, s := range servers {
+		databases, err := listResources(c, s.ID+"/databases", sqlAPIVersion)
+		if err != nil {
+			return all, err
+		}
+		server := &SQLServer{Resource: s}
+		for _, db := range databases {
+			if db.Name != "master" {
+				server.Databases = append(server.Databases, db)
+			}
+		}
+		all = append(all, server)
+	}
+	return all, nil
+}
+
+// GetAllLoadBalancers returns a complete list of load balancers for a given subscription
+func GetAllLoadBalancers(c *Client, subscriptionID string) ([]*Resource, error) {
+	return listResources(c, "/subscriptions/"+subscriptionID+"/providers/Microsoft.Network/loadBalancers", networkAPIVersion)
+}
+
+// GetAllPublicIPAddresses returns a complete list of public IP addresses for a given subscription
+func GetAllPublicIPAddresses(c *Client, subscriptionID string) ([]*Resource, error) {
+	return listResources(c, "/subscriptions/"+subscriptionID+"/providers/Microsoft.Network/publicIPAddresses", networkAPIVersion)
+}
+
+// GetAllDNSZones returns a complete list of public DNS zones for a given subscription
+func GetAllDNSZones(c *Client, subscriptionID string) ([]*Resource, error) {
+	return listResources(c, "/subscriptions/"+subscriptionID+"/providers/Microsoft.Network/dnszones", dnsAPIVersion)
+}
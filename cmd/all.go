@@ -0,0 +1,166 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+// allCmd represents the all command
+var allCmd = &cobra.Command{
+	Use:   "all",
+	Short: "Dump the inventory of every cloud with credentials into a single file. Currently supports AWS/Azure/GCP/Kubernetes/OpenStack/DigitalOcean",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		services := strings.Split(filter, ",")
+		validators := []func(string) bool{validateAWSFilter, validateAzureFilter, validateGCPFilter, validateK8sFilter, validateOpenStackFilter, validateDigitalOceanFilter}
+		for _, service := range services {
+			valid := false
+			for _, validate := range validators {
+				valid = valid || validate(service)
+			}
+			if !valid {
+				fmt.Printf("Invalid filter selected, please select a service supported by at least one cloud")
+				return
+			}
+		}
+
+		// A cloud failing to dump is left out, the others are still written
+		var result collector.MultiCloudInventory
+		if s := supportedServices(services, validateAWSFilter); len(s) > 0 {
+			col, err := collector.NewAWSCollector(partition, nil)
+			if err != nil {
+				fmt.Printf("Skipping AWS: %v\n", err)
+			} else {
+				inv, err := collectAWSInventory(col, s)
+				if err != nil {
+					fmt.Printf("Skipping AWS: %v\n", err)
+				} else {
+					result.AWS = &inv
+				}
+			}
+		}
+		if s := supportedServices(services, validateAzureFilter); len(s) > 0 {
+			col, err := collector.NewAzureCollector(nil)
+			if err != nil {
+				fmt.Printf("Skipping Azure: %v\n", err)
+			} else {
+				if len(azureSubscriptions) > 0 {
+					err = col.Restrict(azureSubscriptions)
+				}
+				var inv collector.AzureInventory
+				if err == nil {
+					inv, err = collectAzureInventory(col, s)
+				}
+				if err != nil {
+					fmt.Printf("Skipping Azure: %v\n", err)
+				} else {
+					result.Azure = &inv
+				}
+			}
+		}
+		if s := supportedServices(services, validateGCPFilter); len(s) > 0 {
+			col, err := collector.NewGCPCollector(nil, gcpProjects)
+			if err != nil {
+				fmt.Printf("Skipping GCP: %v\n", err)
+			} else {
+				inv, err := collectGCPInventory(col, s)
+				if err != nil {
+					fmt.Printf("Skipping GCP: %v\n", err)
+				} else {
+					result.GCP = &inv
+				}
+			}
+		}
+		if s := supportedServices(services, validateK8sFilter); len(s) > 0 {
+			col, err := collector.NewK8sCollector(kubeconfigs, k8sContexts)
+			if err != nil {
+				fmt.Printf("Skipping Kubernetes: %v\n", err)
+			} else {
+				inv, err := collectK8sInventory(col, s)
+				if err != nil {
+					fmt.Printf("Skipping Kubernetes: %v\n", err)
+				} else {
+					if result.AWS != nil {
+						fmt.Printf("Linked %d objects to AWS resources\n", collector.LinkAWS(&inv, *result.AWS))
+					}
+					result.K8s = &inv
+				}
+			}
+		}
+		if s := supportedServices(services, validateOpenStackFilter); len(s) > 0 {
+			col, err := collector.NewOpenStackCollector(openStackCloud)
+			if err != nil {
+				fmt.Printf("Skipping OpenStack: %v\n", err)
+			} else {
+				inv, err := collectOpenStackInventory(col, s)
+				if err != nil {
+					fmt.Printf("Skipping OpenStack: %v\n", err)
+				} else {
+					result.OpenStack = &inv
+				}
+			}
+		}
+		if s := supportedServices(services, validateDigitalOceanFilter); len(s) > 0 {
+			col, err := collector.NewDigitalOceanCollector("")
+			if err != nil {
+				fmt.Printf("Skipping DigitalOcean: %v\n", err)
+			} else {
+				inv, err := collectDigitalOceanInventory(col, s)
+				if err != nil {
+					fmt.Printf("Skipping DigitalOcean: %v\n", err)
+				} else {
+					result.DigitalOcean = &inv
+				}
+			}
+		}
+
+		fmt.Printf("Dumping to %s\n", path)
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = ioutil.WriteFile(path, jsonBytes, 0644)
+		if err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+		}
+	},
+}
+
+// supportedServices returns the services a cloud supports according to its filter validation
+func supportedServices(services []string, validate func(string) bool) []string {
+	var supported []string
+	for _, service := range services {
+		if validate(service) {
+			supported = append(supported, service)
+		}
+	}
+	return supported
+}
+
+func init() {
+	allCmd.Flags().StringVarP(&partition, "partition", "", "default", "Which partition of AWS to run for default/china")
+	allCmd.Flags().StringSliceVarP(&azureSubscriptions, "subscriptions", "", nil, "limit the Azure dump to a comma separated list of subscription IDs or names")
+	allCmd.Flags().StringSliceVarP(&gcpProjects, "projects", "", nil, "comma separated list of Google Cloud projects to dump, defaults to the project of the credentials")
+	allCmd.Flags().StringSliceVarP(&kubeconfigs, "kubeconfig", "", nil, "comma separated list of kubeconfig files, defaults to $KUBECONFIG or ~/.kube/config")
+	allCmd.Flags().StringSliceVarP(&k8sContexts, "contexts", "", nil, "comma separated list of kubeconfig contexts to dump, defaults to the current context")
+	allCmd.Flags().StringVarP(&openStackCloud, "cloud", "", "", "cloud of clouds.yaml to dump, defaults to $OS_CLOUD")
+	dumpCmd.AddCommand(allCmd)
+}
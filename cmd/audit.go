@@ -0,0 +1,49 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audits the inventory against compliance rules",
+}
+
+// writeReport writes a report as JSON to output, unless no output was given
+func writeReport(output string, v interface{}) {
+	if output == "" {
+		return
+	}
+	fmt.Printf("Dumping report to %s\n", output)
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		fmt.Printf("Error Marshalling JSON: %v\n", err)
+		return
+	}
+	err = ioutil.WriteFile(output, jsonBytes, 0644)
+	if err != nil {
+		fmt.Printf("Error writing file: %v\n", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.PersistentFlags().StringP("output", "o", "", "optional file path to write the JSON audit report in")
+}
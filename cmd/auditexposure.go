@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+var exposureInventories []string
+
+// auditExposureCmd represents the audit exposure command
+var auditExposureCmd = &cobra.Command{
+	Use:   "exposure",
+	Short: "Report resources reachable from the internet, unused security groups and shared RDS snapshots",
+	Long: `Report EC2 instances, RDS instances, load balancers and Lambda functions whose security groups
+accept traffic from 0.0.0.0/0 or ::/0, and internet-facing network load balancers without security groups,
+along with the security groups attached to no network interface nor referenced by another group
+and the manual RDS snapshots shared publicly or with other accounts.
+Resources are collected live unless inventories dumped with ec2,rds,loadbalancer,lambda,securitygroup are given`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output := cmd.Flag("output").Value.String()
+		var inventories []collector.AWSInventory
+		if len(exposureInventories) > 0 {
+			var err error
+			inventories, err = loadInventories(exposureInventories)
+			if err != nil {
+				return
+			}
+		} else {
+			inv, err := collectExposureInventory()
+			if err != nil {
+				return
+			}
+			inventories = append(inventories, inv)
+		}
+
+		var report audit.ExposureReport
+		for _, inv := range inventories {
+			r := audit.FindExposure(inv)
+			report.Exposed = append(report.Exposed, r.Exposed...)
+			report.UnusedSecurityGroups = append(report.UnusedSecurityGroups, r.UnusedSecurityGroups...)
+			report.SharedSnapshots = append(report.SharedSnapshots, r.SharedSnapshots...)
+		}
+		printExposureReport(report)
+
+		writeReport(output, report)
+	},
+}
+
+func collectExposureInventory() (collector.AWSInventory, error) {
+	var result collector.AWSInventory
+	col, err := collector.NewAWSCollector(partition, nil)
+	if err != nil {
+		fmt.Printf("Failed to create AWS collector: %v\n", err)
+		return result, err
+	}
+	// The account ID only labels the findings, do not fail the audit when it can't be looked up
+	if result.Account, err = col.AccountID(); err != nil {
+		fmt.Printf("Warning: failed to get AWS Account ID, leaving it empty: %v\n", err)
+	}
+	for _, collect := range []func(collector.AWSCollector, *collector.AWSInventory) error{
+		collectSecurityGroups, collectEC2, collectRDS, collectRDSTopology, collectLoadBalancers, collectLambda,
+	} {
+		if err := collect(col, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func printExposureReport(report audit.ExposureReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tACCOUNT\tREGION\tRESOURCE\tPUBLIC\tOPEN PORTS")
+	for _, e := range report.Exposed {
+		var ports []string
+		for _, p := range e.Permissions {
+			ports = append(ports, fmt.Sprintf("%s %d-%d from %s (%s)", p.Protocol, p.FromPort, p.ToPort, p.Source, p.GroupID))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%s\n", e.Service, e.Account, e.Region, e.ID, e.Public, strings.Join(ports, ","))
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "UNUSED SECURITY GROUP\tNAME\tACCOUNT\tREGION\tVPC")
+	for _, sg := range report.UnusedSecurityGroups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", sg.GroupID, sg.Name, sg.Account, sg.Region, sg.VpcID)
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SHARED SNAPSHOT\tCLUSTER\tACCOUNT\tREGION\tPUBLIC\tSHARED WITH")
+	for _, s := range report.SharedSnapshots {
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%t\t%s\n", s.ID, s.Cluster, s.Account, s.Region, s.Public, strings.Join(s.SharedWith, ","))
+	}
+	w.Flush()
+}
+
+func init() {
+	auditExposureCmd.Flags().StringSliceVarP(&exposureInventories, "inventory", "i", nil, "comma separated inventory files to audit instead of collecting live")
+	auditExposureCmd.Flags().StringVarP(&partition, "partition", "", "default", "Which partition of AWS to run for default/china")
+	auditCmd.AddCommand(auditExposureCmd)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+var iamInventories []string
+var iamMaxAge int
+
+// auditIAMCmd represents the audit iam command
+var auditIAMCmd = &cobra.Command{
+	Use:   "iam",
+	Short: "Report stale access keys, unused roles and users without MFA",
+	Long: `Report the active access keys not rotated or not used within --max-age days, the roles not assumed
+within --max-age days and the users without MFA.
+The IAM inventory is collected live unless inventories dumped with iam are given`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output := cmd.Flag("output").Value.String()
+		var inventories []collector.AWSInventory
+		if len(iamInventories) > 0 {
+			var err error
+			inventories, err = loadInventories(iamInventories)
+			if err != nil {
+				return
+			}
+		} else {
+			inv, err := collectIAMInventory()
+			if err != nil {
+				return
+			}
+			inventories = append(inventories, inv)
+		}
+
+		var report audit.IAMReport
+		maxAge := time.Duration(iamMaxAge) * 24 * time.Hour
+		now := time.Now()
+		for _, inv := range inventories {
+			r := audit.FindStaleCredentials(inv, maxAge, now)
+			report.StaleAccessKeys = append(report.StaleAccessKeys, r.StaleAccessKeys...)
+			report.UnusedRoles = append(report.UnusedRoles, r.UnusedRoles...)
+			report.UsersWithoutMFA = append(report.UsersWithoutMFA, r.UsersWithoutMFA...)
+		}
+		printIAMReport(report)
+
+		writeReport(output, report)
+	},
+}
+
+func collectIAMInventory() (collector.AWSInventory, error) {
+	var result collector.AWSInventory
+	col, err := collector.NewAWSCollector(partition, nil)
+	if err != nil {
+		fmt.Printf("Failed to create AWS collector: %v\n", err)
+		return result, err
+	}
+	// The account ID only labels the findings, do not fail the audit when it can't be looked up
+	if result.Account, err = col.AccountID(); err != nil {
+		fmt.Printf("Warning: failed to get AWS Account ID, leaving it empty: %v\n", err)
+	}
+	err = collectIAM(col, &result)
+	return result, err
+}
+
+// formatDate prints an optional date, or never when missing
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
+
+func printIAMReport(report audit.IAMReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER\tACCOUNT\tKEY\tAGE (DAYS)\tLAST USED\tREASON")
+	for _, k := range report.StaleAccessKeys {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n", k.User, k.Account, k.Key, k.AgeDays, formatDate(k.LastUsed), k.Reason)
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "UNUSED ROLE\tACCOUNT\tCREATED\tLAST USED")
+	for _, r := range report.UnusedRoles {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Role, r.Account, formatDate(r.Created), formatDate(r.LastUsed))
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER WITHOUT MFA\tACCOUNT")
+	for _, u := range report.UsersWithoutMFA {
+		fmt.Fprintf(w, "%s\t%s\n", u.User, u.Account)
+	}
+	w.Flush()
+}
+
+func init() {
+	auditIAMCmd.Flags().StringSliceVarP(&iamInventories, "inventory", "i", nil, "comma separated inventory files to audit instead of collecting live")
+	auditIAMCmd.Flags().IntVarP(&iamMaxAge, "max-age", "", 90, "days after which an access key or role is considered stale")
+	auditIAMCmd.Flags().StringVarP(&partition, "partition", "", "default", "Which partition of AWS to run for default/china")
+	auditCmd.AddCommand(auditIAMCmd)
+}
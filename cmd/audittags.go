@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+var tagPolicy string
+
+// auditTagsCmd represents the audit tags command
+var auditTagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Report EC2/RDS/LoadBalancers not compliant with a required-tag policy",
+	Run: func(cmd *cobra.Command, args []string) {
+		output := cmd.Flag("output").Value.String()
+		policy, err := audit.LoadTagPolicy(tagPolicy)
+		if err != nil {
+			fmt.Printf("Failed to load tag policy: %v\n", err)
+			return
+		}
+
+		col, err := collector.NewAWSCollector(partition, nil)
+		if err != nil {
+			fmt.Printf("Failed to create AWS collector: %v\n", err)
+			return
+		}
+		resources, err := collectTaggedResources(col)
+		if err != nil {
+			return
+		}
+
+		report := policy.Evaluate(resources)
+		printTagReport(report)
+
+		writeReport(output, report)
+	},
+}
+
+func collectTaggedResources(col collector.AWSCollector) ([]audit.Resource, error) {
+	// The account ID only labels the findings, do not fail the audit when it can't be looked up
+	account, err := col.AccountID()
+	if err != nil {
+		fmt.Printf("Warning: failed to get AWS Account ID, leaving it empty: %v\n", err)
+	}
+
+	instances, err := col.CollectEC2()
+	if err != nil {
+		fmt.Printf("Failed to gather EC2 Data: %v\n", err)
+		return nil, err
+	}
+	resources := audit.EC2Resources(account, instances)
+
+	dbs, err := col.CollectRDS()
+	if err != nil {
+		fmt.Printf("Failed to gather RDS Data: %v\n", err)
+		return nil, err
+	}
+	resources = append(resources, audit.RDSResources(account, dbs)...)
+
+	clbs, err := col.CollectClassicLoadBalancers()
+	if err != nil {
+		fmt.Printf("Failed to gather classic load balancers: %v\n", err)
+		return nil, err
+	}
+	resources = append(resources, audit.ClassicLoadBalancerResources(account, clbs)...)
+
+	anlbs, err := col.CollectApplicationAndNetworkLoadBalancers()
+	if err != nil {
+		fmt.Printf("Failed to gather application and network load balancers: %v\n", err)
+		return nil, err
+	}
+	resources = append(resources, audit.ApplicationAndNetworkLoadBalancerResources(account, anlbs)...)
+
+	fmt.Printf("Gathered %d resources to audit\n", len(resources))
+	return resources, nil
+}
+
+func printTagReport(report audit.TagReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tACCOUNT\tREGION\tRESOURCE\tMISSING\tINVALID")
+	for _, v := range report.NonCompliant {
+		var invalid []string
+		for _, i := range v.Invalid {
+			invalid = append(invalid, fmt.Sprintf("%s=%s (%s)", i.Key, i.Value, i.Reason))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", v.Service, v.Account, v.Region, v.ID, strings.Join(v.Missing, ","), strings.Join(invalid, ","))
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tREGION\tCOMPLIANT\tTOTAL\tPERCENTAGE")
+	for _, s := range report.Summary {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f%%\n", s.Account, s.Region, s.Compliant, s.Total, s.Percentage)
+	}
+	w.Flush()
+}
+
+func init() {
+	auditTagsCmd.Flags().StringVarP(&tagPolicy, "policy", "", "tagpolicy.json", "JSON file listing the required tags per service")
+	auditTagsCmd.Flags().StringVarP(&partition, "partition", "", "default", "Which partition of AWS to run for default/china")
+	auditCmd.AddCommand(auditTagsCmd)
+}
@@ -16,8 +16,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/adobe/cloudinventory/ansible"
+	"github.com/adobe/cloudinventory/awslib"
 	"github.com/adobe/cloudinventory/collector"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/spf13/cobra"
@@ -31,13 +33,16 @@ var ansiblePriv bool
 // awsCmd represents the aws command
 var awsCmd = &cobra.Command{
 	Use:   "aws",
-	Short: "Dump AWS inventory. Currently supports EC2/RDS/Route53/LoadBalancers",
+	Short: "Dump AWS inventory. Currently supports EC2/RDS/RDSTopology/Route53/LoadBalancers/S3/Lambda/Network/SecurityGroups/EBS/EKS/ECS/AutoScaling/DynamoDB/ElastiCache/OpenSearch/IAM/CloudFront/ACM/SQS/SNS/Kinesis/EventBridge/KMS/SecretsManager/APIGateway/CloudFormation",
 	Run: func(cmd *cobra.Command, args []string) {
 		path := cmd.Flag("path").Value.String()
 		filter := cmd.Flag("filter").Value.String()
-		if !validateAWSFilter(filter) {
-			fmt.Printf("Invalid filter selected, please select a supported AWS service")
-			return
+		services := strings.Split(filter, ",")
+		for _, service := range services {
+			if !validateAWSFilter(service) {
+				fmt.Printf("Invalid filter selected, please select a supported AWS service")
+				return
+			}
 		}
 
 		col, err := collector.NewAWSCollector(partition, nil)
@@ -46,39 +51,9 @@ var awsCmd = &cobra.Command{
 			return
 		}
 
-		// Create a map per service
-		result := make(map[string]interface{})
-
-		switch filter {
-		case "ec2":
-			err := collectEC2(col, result)
-			if err != nil {
-				return
-			}
-		case "rds":
-			err := collectRDS(col, result)
-			if err != nil {
-				return
-			}
-		case "hostedzone":
-			err := collectHostedZone(col, result)
-			if err != nil {
-				return
-			}
-		case "loadbalancer":
-			err := collectLoadBalancers(col, result)
-			if err != nil {
-				return
-			}
-		default:
-			err := collectEC2(col, result)
-			if err != nil {
-				return
-			}
-			err = collectRDS(col, result)
-			if err != nil {
-				return
-			}
+		result, err := collectAWSInventory(col, services)
+		if err != nil {
+			return
 		}
 		fmt.Printf("Dumping to %s\n", path)
 		jsonBytes, err := json.Marshal(result)
@@ -92,7 +67,13 @@ var awsCmd = &cobra.Command{
 
 		if ansibleEnable {
 			fmt.Printf("Building Inventory for Ansible at: %s", ansibleinv)
-			ansinv, err := ansible.BuildEC2Inventory(result["ec2"].(map[string][]*ec2.Instance), ansiblePriv)
+			ec2dump := make(map[string][]*ec2.Instance)
+			for region, instances := range result.EC2 {
+				for _, i := range instances {
+					ec2dump[region] = append(ec2dump[region], i.Instance)
+				}
+			}
+			ansinv, err := ansible.BuildEC2Inventory(ec2dump, ansiblePriv)
 			if err != nil {
 				fmt.Printf("Error while building Ansible Inventory: %v\n", err)
 			}
@@ -104,12 +85,123 @@ var awsCmd = &cobra.Command{
 	},
 }
 
+// collectAWSInventory gathers the given services, EC2 and RDS for an empty service
+func collectAWSInventory(col collector.AWSCollector, services []string) (collector.AWSInventory, error) {
+	var result collector.AWSInventory
+	var err error
+	// The account ID only labels the inventory, do not fail the dump when it can't be looked up
+	if result.Account, err = col.AccountID(); err != nil {
+		fmt.Printf("Warning: failed to get AWS Account ID, leaving it empty: %v\n", err)
+	}
+
+	for _, service := range services {
+		switch service {
+		case "ec2":
+			err = collectEC2(col, &result)
+		case "rds":
+			err = collectRDS(col, &result)
+		case "rdstopology":
+			err = collectRDSTopology(col, &result)
+		case "hostedzone":
+			err = collectHostedZone(col, &result)
+		case "loadbalancer":
+			err = collectLoadBalancers(col, &result)
+		case "s3":
+			err = collectS3(col, &result)
+		case "lambda":
+			err = collectLambda(col, &result)
+		case "network":
+			err = collectNetwork(col, &result)
+		case "securitygroup":
+			err = collectSecurityGroups(col, &result)
+		case "ebs":
+			err = collectEBS(col, &result)
+		case "eks":
+			err = collectEKS(col, &result)
+		case "ecs":
+			err = collectECS(col, &result)
+		case "autoscaling":
+			err = collectAutoScaling(col, &result)
+		case "dynamodb":
+			err = collectDynamoDB(col, &result)
+		case "elasticache":
+			err = collectElastiCache(col, &result)
+		case "opensearch":
+			err = collectOpenSearch(col, &result)
+		case "iam":
+			err = collectIAM(col, &result)
+		case "cloudfront":
+			err = collectCloudFront(col, &result)
+		case "acm":
+			err = collectACM(col, &result)
+		case "sqs":
+			err = collectSQS(col, &result)
+		case "sns":
+			err = collectSNS(col, &result)
+		case "kinesis":
+			err = collectKinesis(col, &result)
+		case "eventbridge":
+			err = collectEventBridge(col, &result)
+		case "kms":
+			err = collectKMS(col, &result)
+		case "secretsmanager":
+			err = collectSecrets(col, &result)
+		case "apigateway":
+			err = collectAPIGateway(col, &result)
+		case "cloudformation":
+			err = collectCloudFormation(col, &result)
+		case "messaging":
+			err = collectMessaging(col, &result)
+		case "datastores":
+			err = collectDataStores(col, &result)
+		default:
+			err = collectEC2(col, &result)
+			if err != nil {
+				return result, err
+			}
+			err = collectRDS(col, &result)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	// The lifecycle state of the instances is only listed by the Auto Scaling groups dumped along with them
+	collector.AnnotateEC2(result.EC2, result.AutoScaling)
+	collector.AnnotateStacks(result)
+	return result, nil
+}
+
 func validateAWSFilter(filter string) bool {
 	validSlice := []string{
 		"ec2",
 		"rds",
+		"rdstopology",
 		"hostedzone",
 		"loadbalancer",
+		"s3",
+		"lambda",
+		"network",
+		"securitygroup",
+		"ebs",
+		"eks",
+		"ecs",
+		"autoscaling",
+		"dynamodb",
+		"elasticache",
+		"opensearch",
+		"iam",
+		"cloudfront",
+		"acm",
+		"sqs",
+		"sns",
+		"kinesis",
+		"eventbridge",
+		"kms",
+		"secretsmanager",
+		"apigateway",
+		"cloudformation",
+		"messaging",
+		"datastores",
 		"",
 	}
 	for _, service := range validSlice {
@@ -120,48 +212,339 @@ func validateAWSFilter(filter string) bool {
 	return false
 }
 
-func collectEC2(col collector.AWSCollector, result map[string]interface{}) error {
+func collectEC2(col collector.AWSCollector, result *collector.AWSInventory) error {
 	instances, err := col.CollectEC2()
 	if err != nil {
 		fmt.Printf("Failed to gather EC2 Data: %v\n", err)
 		return err
 	}
 	fmt.Printf("Gathered EC2 Instances across %d regions\n", len(instances))
-	result["ec2"] = instances
+	result.EC2 = make(map[string][]*awslib.Instance)
+	for region, ii := range instances {
+		result.EC2[region] = awslib.NewInstances(ii)
+	}
 	return nil
 }
 
-func collectHostedZone(col collector.AWSCollector, result map[string]interface{}) error {
-	instances, err := col.CollectZones()
+func collectHostedZone(col collector.AWSCollector, result *collector.AWSInventory) error {
+	zones, err := col.CollectZones()
 	if err != nil {
 		fmt.Printf("Failed to gather HostedZones Data: %v\n", err)
 		return err
 	}
+	instances, err := col.CollectZoneRecords(zones)
+	if err != nil {
+		fmt.Printf("Failed to gather HostedZones Records: %v\n", err)
+		return err
+	}
 	fmt.Printf("Gathered HostedZone data across all regions\n")
-	result["hostedzones"] = instances
+	result.HostedZones = instances
 	return nil
 }
 
-func collectRDS(col collector.AWSCollector, result map[string]interface{}) error {
+func collectRDS(col collector.AWSCollector, result *collector.AWSInventory) error {
 	instances, err := col.CollectRDS()
 	if err != nil {
 		fmt.Printf("Failed to gather RDS Data: %v\n", err)
 		return err
 	}
 	fmt.Printf("Gathered RDS Instances across %d regions\n", len(instances))
-	result["rds"] = instances
+	result.RDS = instances
+	return nil
+}
+
+func collectRDSTopology(col collector.AWSCollector, result *collector.AWSInventory) error {
+	topologies, err := col.CollectRDSTopology()
+	if err != nil {
+		fmt.Printf("Failed to gather RDS Topology Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered RDS Clusters and Snapshots across %d regions\n", len(topologies))
+	result.RDSTopology = topologies
+	return nil
+}
+
+func collectS3(col collector.AWSCollector, result *collector.AWSInventory) error {
+	buckets, err := col.CollectS3()
+	if err != nil {
+		fmt.Printf("Failed to gather S3 Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered S3 Buckets across %d regions\n", len(buckets))
+	result.S3 = buckets
+	return nil
+}
+
+func collectLambda(col collector.AWSCollector, result *collector.AWSInventory) error {
+	functions, err := col.CollectLambda()
+	if err != nil {
+		fmt.Printf("Failed to gather Lambda Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Lambda Functions across %d regions\n", len(functions))
+	result.Lambda = functions
+	return nil
+}
+
+func collectNetwork(col collector.AWSCollector, result *collector.AWSInventory) error {
+	networks, err := col.CollectNetwork()
+	if err != nil {
+		fmt.Printf("Failed to gather Network Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered VPC Networking across %d regions\n", len(networks))
+	result.Network = networks
+	return nil
+}
+
+func collectSecurityGroups(col collector.AWSCollector, result *collector.AWSInventory) error {
+	groups, err := col.CollectSecurityGroups()
+	if err != nil {
+		fmt.Printf("Failed to gather Security Group Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Security Groups across %d regions\n", len(groups))
+	result.SecurityGroups = groups
+	return nil
+}
+
+func collectEBS(col collector.AWSCollector, result *collector.AWSInventory) error {
+	storage, err := col.CollectEBS()
+	if err != nil {
+		fmt.Printf("Failed to gather EBS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EBS Volumes, Snapshots and AMIs across %d regions\n", len(storage))
+	result.EBS = storage
+	return nil
+}
+
+func collectEKS(col collector.AWSCollector, result *collector.AWSInventory) error {
+	clusters, err := col.CollectEKS()
+	if err != nil {
+		fmt.Printf("Failed to gather EKS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EKS Clusters across %d regions\n", len(clusters))
+	result.EKS = clusters
+	return nil
+}
+
+func collectECS(col collector.AWSCollector, result *collector.AWSInventory) error {
+	clusters, err := col.CollectECS()
+	if err != nil {
+		fmt.Printf("Failed to gather ECS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered ECS Clusters across %d regions\n", len(clusters))
+	result.ECS = clusters
+	return nil
+}
+
+func collectAutoScaling(col collector.AWSCollector, result *collector.AWSInventory) error {
+	groups, err := col.CollectAutoScaling()
+	if err != nil {
+		fmt.Printf("Failed to gather Auto Scaling Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Auto Scaling Groups across %d regions\n", len(groups))
+	result.AutoScaling = groups
+	return nil
+}
+
+func collectDynamoDB(col collector.AWSCollector, result *collector.AWSInventory) error {
+	tables, err := col.CollectDynamoDB()
+	if err != nil {
+		fmt.Printf("Failed to gather DynamoDB Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered DynamoDB Tables across %d regions\n", len(tables))
+	result.DynamoDB = tables
+	return nil
+}
+
+func collectElastiCache(col collector.AWSCollector, result *collector.AWSInventory) error {
+	caches, err := col.CollectElastiCache()
+	if err != nil {
+		fmt.Printf("Failed to gather ElastiCache Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered ElastiCache Clusters across %d regions\n", len(caches))
+	result.ElastiCache = caches
+	return nil
+}
+
+func collectOpenSearch(col collector.AWSCollector, result *collector.AWSInventory) error {
+	domains, err := col.CollectOpenSearch()
+	if err != nil {
+		fmt.Printf("Failed to gather OpenSearch Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered OpenSearch Domains across %d regions\n", len(domains))
+	result.OpenSearch = domains
+	return nil
+}
+
+func collectIAM(col collector.AWSCollector, result *collector.AWSInventory) error {
+	inventory, err := col.CollectIAM()
+	if err != nil {
+		fmt.Printf("Failed to gather IAM Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered %d IAM Users and %d Roles\n", len(inventory.Users), len(inventory.Roles))
+	result.IAM = inventory
+	return nil
+}
+
+func collectCloudFront(col collector.AWSCollector, result *collector.AWSInventory) error {
+	distributions, err := col.CollectCloudFront()
+	if err != nil {
+		fmt.Printf("Failed to gather CloudFront Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered %d CloudFront Distributions\n", len(distributions))
+	result.CloudFront = distributions
+	return nil
+}
+
+func collectACM(col collector.AWSCollector, result *collector.AWSInventory) error {
+	certificates, err := col.CollectACM()
+	if err != nil {
+		fmt.Printf("Failed to gather ACM Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered ACM Certificates across %d regions\n", len(certificates))
+	result.ACM = certificates
+	return nil
+}
+
+func collectSQS(col collector.AWSCollector, result *collector.AWSInventory) error {
+	queues, err := col.CollectSQS()
+	if err != nil {
+		fmt.Printf("Failed to gather SQS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered SQS Queues across %d regions\n", len(queues))
+	result.SQS = queues
+	return nil
+}
+
+func collectSNS(col collector.AWSCollector, result *collector.AWSInventory) error {
+	topics, err := col.CollectSNS()
+	if err != nil {
+		fmt.Printf("Failed to gather SNS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered SNS Topics across %d regions\n", len(topics))
+	result.SNS = topics
+	return nil
+}
+
+func collectKinesis(col collector.AWSCollector, result *collector.AWSInventory) error {
+	streams, err := col.CollectKinesis()
+	if err != nil {
+		fmt.Printf("Failed to gather Kinesis Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Kinesis Streams across %d regions\n", len(streams))
+	result.Kinesis = streams
+	return nil
+}
+
+func collectEventBridge(col collector.AWSCollector, result *collector.AWSInventory) error {
+	buses, err := col.CollectEventBridge()
+	if err != nil {
+		fmt.Printf("Failed to gather EventBridge Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered EventBridge Buses across %d regions\n", len(buses))
+	result.EventBridge = buses
+	return nil
+}
+
+func collectKMS(col collector.AWSCollector, result *collector.AWSInventory) error {
+	keys, err := col.CollectKMS()
+	if err != nil {
+		fmt.Printf("Failed to gather KMS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered KMS Keys across %d regions\n", len(keys))
+	result.KMS = keys
+	return nil
+}
+
+func collectSecrets(col collector.AWSCollector, result *collector.AWSInventory) error {
+	secrets, err := col.CollectSecrets()
+	if err != nil {
+		fmt.Printf("Failed to gather Secrets Manager Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Secrets Manager Secrets across %d regions\n", len(secrets))
+	result.Secrets = secrets
 	return nil
 }
 
-func collectLoadBalancers(col collector.AWSCollector, result map[string]interface{}) error {
+// collectAPIGateway gathers both the REST APIs and the HTTP and WebSocket APIs
+func collectAPIGateway(col collector.AWSCollector, result *collector.AWSInventory) error {
+	rest, err := col.CollectAPIGateway()
+	if err != nil {
+		fmt.Printf("Failed to gather API Gateway Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered API Gateway REST APIs across %d regions\n", len(rest))
+	result.APIGateway = rest
+
+	apis, err := col.CollectAPIGatewayV2()
+	if err != nil {
+		fmt.Printf("Failed to gather API Gateway V2 Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered API Gateway HTTP and WebSocket APIs across %d regions\n", len(apis))
+	result.APIGatewayV2 = apis
+	return nil
+}
+
+func collectCloudFormation(col collector.AWSCollector, result *collector.AWSInventory) error {
+	stacks, err := col.CollectCloudFormation()
+	if err != nil {
+		fmt.Printf("Failed to gather CloudFormation Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered CloudFormation Stacks across %d regions\n", len(stacks))
+	result.CloudFormation = stacks
+	return nil
+}
+
+// collectMessaging gathers SQS, SNS, Kinesis and EventBridge
+func collectMessaging(col collector.AWSCollector, result *collector.AWSInventory) error {
+	for _, collect := range []func(collector.AWSCollector, *collector.AWSInventory) error{
+		collectSQS, collectSNS, collectKinesis, collectEventBridge,
+	} {
+		if err := collect(col, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectDataStores gathers the whole data tier: RDS, DynamoDB, ElastiCache and OpenSearch
+func collectDataStores(col collector.AWSCollector, result *collector.AWSInventory) error {
+	for _, collect := range []func(collector.AWSCollector, *collector.AWSInventory) error{
+		collectRDS, collectRDSTopology, collectDynamoDB, collectElastiCache, collectOpenSearch,
+	} {
+		if err := collect(col, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	var allLbs []interface{}
+func collectLoadBalancers(col collector.AWSCollector, result *collector.AWSInventory) error {
 	clbs, err := col.CollectClassicLoadBalancers()
 	if err != nil {
 		fmt.Printf("Failed to gather classic load balancers: %v\n", err)
 		return err
 	}
-	allLbs = append(allLbs, clbs)
 	fmt.Printf("Gathered Classic Load Balancers across %d regions\n", len(clbs))
 
 	anlbs, err := col.CollectApplicationAndNetworkLoadBalancers()
@@ -169,10 +552,9 @@ func collectLoadBalancers(col collector.AWSCollector, result map[string]interfac
 		fmt.Printf("Failed to gather application and network load balancers: %v\n", err)
 		return err
 	}
-	allLbs = append(allLbs, anlbs)
 	fmt.Printf("Gathered Application and Network Load Balancers across %d regions\n", len(anlbs))
 
-	result["loadbalancer"] = allLbs
+	result.LoadBalancers = &collector.LoadBalancers{Classic: clbs, ApplicationNetwork: anlbs}
 	return nil
 }
 
@@ -0,0 +1,192 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+var azureSubscriptions []string
+
+// azureCmd represents the azure command
+var azureCmd = &cobra.Command{
+	Use:   "azure",
+	Short: "Dump Azure inventory. Currently supports VM/Disk/SQL/LoadBalancer/PublicIP/DNS",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		services := strings.Split(filter, ",")
+		for _, service := range services {
+			if !validateAzureFilter(service) {
+				fmt.Printf("Invalid filter selected, please select a supported Azure service")
+				return
+			}
+		}
+
+		col, err := collector.NewAzureCollector(nil)
+		if err != nil {
+			fmt.Printf("Failed to create Azure collector: %v\n", err)
+			return
+		}
+		if len(azureSubscriptions) > 0 {
+			err = col.Restrict(azureSubscriptions)
+			if err != nil {
+				fmt.Printf("Failed to select Azure subscriptions: %v\n", err)
+				return
+			}
+		}
+
+		result, err := collectAzureInventory(col, services)
+		if err != nil {
+			return
+		}
+		fmt.Printf("Dumping to %s\n", path)
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = ioutil.WriteFile(path, jsonBytes, 0644)
+		if err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+		}
+	},
+}
+
+// collectAzureInventory gathers the given services, VMs and SQL servers for an empty service
+func collectAzureInventory(col collector.AzureCollector, services []string) (collector.AzureInventory, error) {
+	var result collector.AzureInventory
+	result.Subscriptions = col.Subscriptions()
+	var err error
+
+	for _, service := range services {
+		switch service {
+		case "vm":
+			err = collectAzureVirtualMachines(col, &result)
+		case "disk":
+			err = collectAzureDisks(col, &result)
+		case "sql":
+			err = collectAzureSQL(col, &result)
+		case "loadbalancer":
+			err = collectAzureLoadBalancers(col, &result)
+		case "publicip":
+			err = collectAzurePublicIPs(col, &result)
+		case "dns":
+			err = collectAzureDNSZones(col, &result)
+		default:
+			err = collectAzureVirtualMachines(col, &result)
+			if err != nil {
+				return result, err
+			}
+			err = collectAzureSQL(col, &result)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func validateAzureFilter(filter string) bool {
+	validSlice := []string{
+		"vm",
+		"disk",
+		"sql",
+		"loadbalancer",
+		"publicip",
+		"dns",
+		"",
+	}
+	for _, v := range validSlice {
+		if filter == v {
+			return true
+		}
+	}
+	return false
+}
+
+func collectAzureVirtualMachines(col collector.AzureCollector, result *collector.AzureInventory) error {
+	vms, err := col.CollectVirtualMachines()
+	if err != nil {
+		fmt.Printf("Failed to gather VM Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered VMs across %d subscriptions\n", len(vms))
+	result.VirtualMachines = vms
+	return nil
+}
+
+func collectAzureDisks(col collector.AzureCollector, result *collector.AzureInventory) error {
+	disks, err := col.CollectDisks()
+	if err != nil {
+		fmt.Printf("Failed to gather Disk Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Disks across %d subscriptions\n", len(disks))
+	result.Disks = disks
+	return nil
+}
+
+func collectAzureSQL(col collector.AzureCollector, result *collector.AzureInventory) error {
+	servers, err := col.CollectSQL()
+	if err != nil {
+		fmt.Printf("Failed to gather SQL Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered SQL Servers across %d subscriptions\n", len(servers))
+	result.SQL = servers
+	return nil
+}
+
+func collectAzureLoadBalancers(col collector.AzureCollector, result *collector.AzureInventory) error {
+	lbs, err := col.CollectLoadBalancers()
+	if err != nil {
+		fmt.Printf("Failed to gather LoadBalancer Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered LoadBalancers across %d subscriptions\n", len(lbs))
+	result.LoadBalancers = lbs
+	return nil
+}
+
+func collectAzurePublicIPs(col collector.AzureCollector, result *collector.AzureInventory) error {
+	ips, err := col.CollectPublicIPAddresses()
+	if err != nil {
+		fmt.Printf("Failed to gather PublicIP Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered PublicIPs across %d subscriptions\n", len(ips))
+	result.PublicIPs = ips
+	return nil
+}
+
+func collectAzureDNSZones(col collector.AzureCollector, result *collector.AzureInventory) error {
+	zones, err := col.CollectDNSZones()
+	if err != nil {
+		fmt.Printf("Failed to gather DNS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered DNS Zones across %d subscriptions\n", len(zones))
+	result.DNSZones = zones
+	return nil
+}
+
+func init() {
+	azureCmd.Flags().StringSliceVarP(&azureSubscriptions, "subscriptions", "", nil, "limit dump to a comma separated list of subscription IDs or names")
+	dumpCmd.AddCommand(azureCmd)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+// digitaloceanCmd represents the digitalocean command
+var digitaloceanCmd = &cobra.Command{
+	Use:   "digitalocean",
+	Short: "Dump DigitalOcean inventory. Currently supports Droplets/Volumes/LoadBalancers/Domains",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		services := strings.Split(filter, ",")
+		for _, service := range services {
+			if !validateDigitalOceanFilter(service) {
+				fmt.Printf("Invalid filter selected, please select a supported DigitalOcean service")
+				return
+			}
+		}
+
+		col, err := collector.NewDigitalOceanCollector("")
+		if err != nil {
+			fmt.Printf("Failed to create DigitalOcean collector: %v\n", err)
+			return
+		}
+
+		result, err := collectDigitalOceanInventory(col, services)
+		if err != nil {
+			return
+		}
+		fmt.Printf("Dumping to %s\n", path)
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = ioutil.WriteFile(path, jsonBytes, 0644)
+		if err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+		}
+	},
+}
+
+// collectDigitalOceanInventory gathers the given services, droplets and volumes for an empty service
+func collectDigitalOceanInventory(col collector.DigitalOceanCollector, services []string) (collector.DigitalOceanInventory, error) {
+	var result collector.DigitalOceanInventory
+	var err error
+
+	for _, service := range services {
+		switch service {
+		case "droplet":
+			err = collectDigitalOceanDroplets(col, &result)
+		case "volume":
+			err = collectDigitalOceanVolumes(col, &result)
+		case "loadbalancer":
+			err = collectDigitalOceanLoadBalancers(col, &result)
+		case "domain":
+			err = collectDigitalOceanDomains(col, &result)
+		default:
+			err = collectDigitalOceanDroplets(col, &result)
+			if err != nil {
+				return result, err
+			}
+			err = collectDigitalOceanVolumes(col, &result)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func validateDigitalOceanFilter(filter string) bool {
+	validSlice := []string{
+		"droplet",
+		"volume",
+		"loadbalancer",
+		"domain",
+		"",
+	}
+	for _, v := range validSlice {
+		if filter == v {
+			return true
+		}
+	}
+	return false
+}
+
+func collectDigitalOceanDroplets(col collector.DigitalOceanCollector, result *collector.DigitalOceanInventory) error {
+	droplets, err := col.CollectDroplets()
+	if err != nil {
+		fmt.Printf("Failed to gather Droplet Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Droplets across %d regions\n", len(droplets))
+	result.Droplets = droplets
+	return nil
+}
+
+func collectDigitalOceanVolumes(col collector.DigitalOceanCollector, result *collector.DigitalOceanInventory) error {
+	volumes, err := col.CollectVolumes()
+	if err != nil {
+		fmt.Printf("Failed to gather Volume Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Volumes across %d regions\n", len(volumes))
+	result.Volumes = volumes
+	return nil
+}
+
+func collectDigitalOceanLoadBalancers(col collector.DigitalOceanCollector, result *collector.DigitalOceanInventory) error {
+	lbs, err := col.CollectLoadBalancers()
+	if err != nil {
+		fmt.Printf("Failed to gather LoadBalancer Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered LoadBalancers across %d regions\n", len(lbs))
+	result.LoadBalancers = lbs
+	return nil
+}
+
+func collectDigitalOceanDomains(col collector.DigitalOceanCollector, result *collector.DigitalOceanInventory) error {
+	domains, err := col.CollectDomains()
+	if err != nil {
+		fmt.Printf("Failed to gather Domain Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered %d Domains\n", len(domains))
+	result.Domains = domains
+	return nil
+}
+
+func init() {
+	dumpCmd.AddCommand(digitaloceanCmd)
+}
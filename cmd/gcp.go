@@ -0,0 +1,165 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+var gcpProjects []string
+
+// gcpCmd represents the gcp command
+var gcpCmd = &cobra.Command{
+	Use:   "gcp",
+	Short: "Dump Google Cloud inventory. Currently supports Compute/CloudSQL/LoadBalancing/CloudDNS",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		services := strings.Split(filter, ",")
+		for _, service := range services {
+			if !validateGCPFilter(service) {
+				fmt.Printf("Invalid filter selected, please select a supported Google Cloud service")
+				return
+			}
+		}
+
+		col, err := collector.NewGCPCollector(nil, gcpProjects)
+		if err != nil {
+			fmt.Printf("Failed to create GCP collector: %v\n", err)
+			return
+		}
+
+		result, err := collectGCPInventory(col, services)
+		if err != nil {
+			return
+		}
+		fmt.Printf("Dumping to %s\n", path)
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = ioutil.WriteFile(path, jsonBytes, 0644)
+		if err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+		}
+	},
+}
+
+// collectGCPInventory gathers the given services, Compute Engine and Cloud SQL instances for an empty service
+func collectGCPInventory(col collector.GCPCollector, services []string) (collector.GCPInventory, error) {
+	var result collector.GCPInventory
+	result.Projects = col.Projects()
+	var err error
+
+	for _, service := range services {
+		switch service {
+		case "compute":
+			err = collectGCPInstances(col, &result)
+		case "sql":
+			err = collectGCPSQL(col, &result)
+		case "loadbalancing":
+			err = collectGCPLoadBalancing(col, &result)
+		case "dns":
+			err = collectGCPDNS(col, &result)
+		default:
+			err = collectGCPInstances(col, &result)
+			if err != nil {
+				return result, err
+			}
+			err = collectGCPSQL(col, &result)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func validateGCPFilter(filter string) bool {
+	validSlice := []string{
+		"compute",
+		"sql",
+		"loadbalancing",
+		"dns",
+		"",
+	}
+	for _, v := range validSlice {
+		if filter == v {
+			return true
+		}
+	}
+	return false
+}
+
+func collectGCPInstances(col collector.GCPCollector, result *collector.GCPInventory) error {
+	instances, err := col.CollectInstances()
+	if err != nil {
+		fmt.Printf("Failed to gather Compute Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Compute Instances across %d projects\n", len(instances))
+	result.Instances = instances
+	return nil
+}
+
+func collectGCPSQL(col collector.GCPCollector, result *collector.GCPInventory) error {
+	instances, err := col.CollectSQLInstances()
+	if err != nil {
+		fmt.Printf("Failed to gather Cloud SQL Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Cloud SQL Instances across %d projects\n", len(instances))
+	result.SQL = instances
+	return nil
+}
+
+func collectGCPLoadBalancing(col collector.GCPCollector, result *collector.GCPInventory) error {
+	rules, err := col.CollectForwardingRules()
+	if err != nil {
+		fmt.Printf("Failed to gather Forwarding Rule Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Forwarding Rules across %d projects\n", len(rules))
+	result.ForwardingRules = rules
+
+	services, err := col.CollectBackendServices()
+	if err != nil {
+		fmt.Printf("Failed to gather Backend Service Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Backend Services across %d projects\n", len(services))
+	result.BackendServices = services
+	return nil
+}
+
+func collectGCPDNS(col collector.GCPCollector, result *collector.GCPInventory) error {
+	zones, err := col.CollectManagedZones()
+	if err != nil {
+		fmt.Printf("Failed to gather Cloud DNS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Cloud DNS Zones across %d projects\n", len(zones))
+	result.DNS = zones
+	return nil
+}
+
+func init() {
+	gcpCmd.Flags().StringSliceVarP(&gcpProjects, "projects", "", nil, "comma separated list of projects to dump, defaults to the project of the credentials")
+	dumpCmd.AddCommand(gcpCmd)
+}
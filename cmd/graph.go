@@ -0,0 +1,77 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/graph"
+	"github.com/spf13/cobra"
+)
+
+var graphFormat string
+var graphFrom string
+var graphReverse bool
+
+// graphCmd represents the graph command
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the resource relationship graph of a dumped AWS inventory as DOT/GraphML/JSON",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("inventory").Value.String()
+		output := cmd.Flag("output").Value.String()
+
+		inv, err := collector.LoadAWSInventory(path)
+		if err != nil {
+			fmt.Printf("Failed to load inventory: %v\n", err)
+			return
+		}
+		g := graph.Build(inv)
+
+		if graphFrom != "" {
+			start, ok := g.FindNode(graphFrom)
+			if !ok {
+				fmt.Printf("Could not find %s in the inventory\n", graphFrom)
+				return
+			}
+			g = g.Reachable(start, graphReverse)
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				fmt.Printf("Error creating file: %v\n", err)
+				return
+			}
+			defer f.Close()
+			w = f
+		}
+		err = g.Write(w, graphFormat)
+		if err != nil {
+			fmt.Printf("Error writing graph: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringP("inventory", "i", "cloudinventory.json", "inventory file previously created by dump aws")
+	graphCmd.Flags().StringP("output", "o", "", "file path to write the graph in, defaults to stdout")
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "", "dot", "graph format dot/graphml/json")
+	graphCmd.Flags().StringVarP(&graphFrom, "from", "", "", "only export the subgraph reachable from this hostname or node ID")
+	graphCmd.Flags().BoolVarP(&graphReverse, "reverse", "", false, "with --from, export everything that reaches the node instead")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+var kubeconfigs []string
+var k8sContexts []string
+var k8sAWSInventory string
+
+// k8sCmd represents the k8s command
+var k8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Dump Kubernetes inventory. Currently supports Nodes/Namespaces/Deployments/StatefulSets/LoadBalancerServices/Ingresses",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		services := strings.Split(filter, ",")
+		for _, service := range services {
+			if !validateK8sFilter(service) {
+				fmt.Printf("Invalid filter selected, please select a supported Kubernetes object kind")
+				return
+			}
+		}
+
+		col, err := collector.NewK8sCollector(kubeconfigs, k8sContexts)
+		if err != nil {
+			fmt.Printf("Failed to create Kubernetes collector: %v\n", err)
+			return
+		}
+
+		result, err := collectK8sInventory(col, services)
+		if err != nil {
+			return
+		}
+
+		if k8sAWSInventory != "" {
+			awsInv, err := collector.LoadAWSInventory(k8sAWSInventory)
+			if err != nil {
+				fmt.Printf("Failed to load AWS inventory: %v\n", err)
+				return
+			}
+			fmt.Printf("Linked %d objects to AWS resources\n", collector.LinkAWS(&result, awsInv))
+		}
+
+		fmt.Printf("Dumping to %s\n", path)
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = ioutil.WriteFile(path, jsonBytes, 0644)
+		if err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+		}
+	},
+}
+
+// collectK8sInventory gathers the given services, every object kind for an empty service
+func collectK8sInventory(col collector.K8sCollector, services []string) (collector.K8sInventory, error) {
+	var result collector.K8sInventory
+	result.Contexts = col.Contexts()
+	var err error
+
+	for _, service := range services {
+		switch service {
+		case "node":
+			err = collectK8sNodes(col, &result)
+		case "namespace":
+			err = collectK8sNamespaces(col, &result)
+		case "deployment":
+			err = collectK8sDeployments(col, &result)
+		case "statefulset":
+			err = collectK8sStatefulSets(col, &result)
+		case "service":
+			err = collectK8sServices(col, &result)
+		case "ingress":
+			err = collectK8sIngresses(col, &result)
+		default:
+			for _, collect := range []func(collector.K8sCollector, *collector.K8sInventory) error{
+				collectK8sNodes, collectK8sNamespaces, collectK8sDeployments, collectK8sStatefulSets, collectK8sServices, collectK8sIngresses,
+			} {
+				if err = collect(col, &result); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func validateK8sFilter(filter string) bool {
+	validSlice := []string{
+		"node",
+		"namespace",
+		"deployment",
+		"statefulset",
+		"service",
+		"ingress",
+		"",
+	}
+	for _, v := range validSlice {
+		if filter == v {
+			return true
+		}
+	}
+	return false
+}
+
+func collectK8sNodes(col collector.K8sCollector, result *collector.K8sInventory) error {
+	nodes, err := col.CollectNodes()
+	if err != nil {
+		fmt.Printf("Failed to gather Node Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Nodes across %d contexts\n", len(nodes))
+	result.Nodes = nodes
+	return nil
+}
+
+func collectK8sNamespaces(col collector.K8sCollector, result *collector.K8sInventory) error {
+	namespaces, err := col.CollectNamespaces()
+	if err != nil {
+		fmt.Printf("Failed to gather Namespace Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Namespaces across %d contexts\n", len(namespaces))
+	result.Namespaces = namespaces
+	return nil
+}
+
+func collectK8sDeployments(col collector.K8sCollector, result *collector.K8sInventory) error {
+	deployments, err := col.CollectDeployments()
+	if err != nil {
+		fmt.Printf("Failed to gather Deployment Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Deployments across %d contexts\n", len(deployments))
+	result.Deployments = deployments
+	return nil
+}
+
+func collectK8sStatefulSets(col collector.K8sCollector, result *collector.K8sInventory) error {
+	statefulSets, err := col.CollectStatefulSets()
+	if err != nil {
+		fmt.Printf("Failed to gather StatefulSet Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered StatefulSets across %d contexts\n", len(statefulSets))
+	result.StatefulSets = statefulSets
+	return nil
+}
+
+func collectK8sServices(col collector.K8sCollector, result *collector.K8sInventory) error {
+	services, err := col.CollectLoadBalancerServices()
+	if err != nil {
+		fmt.Printf("Failed to gather Service Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered LoadBalancer Services across %d contexts\n", len(services))
+	result.Services = services
+	return nil
+}
+
+func collectK8sIngresses(col collector.K8sCollector, result *collector.K8sInventory) error {
+	ingresses, err := col.CollectIngresses()
+	if err != nil {
+		fmt.Printf("Failed to gather Ingress Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Ingresses across %d contexts\n", len(ingresses))
+	result.Ingresses = ingresses
+	return nil
+}
+
+func init() {
+	k8sCmd.Flags().StringSliceVarP(&kubeconfigs, "kubeconfig", "", nil, "comma separated list of kubeconfig files, defaults to $KUBECONFIG or ~/.kube/config")
+	k8sCmd.Flags().StringSliceVarP(&k8sContexts, "contexts", "", nil, "comma separated list of kubeconfig contexts to dump, defaults to the current context")
+	k8sCmd.Flags().StringVarP(&k8sAWSInventory, "aws-inventory", "", "", "AWS inventory to link the nodes, Services and Ingresses to their EC2 instances and load balancers")
+	dumpCmd.AddCommand(k8sCmd)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+var openStackCloud string
+
+// openstackCmd represents the openstack command
+var openstackCmd = &cobra.Command{
+	Use:   "openstack",
+	Short: "Dump OpenStack inventory. Currently supports Nova/Cinder/FloatingIPs/Octavia/Designate",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("path").Value.String()
+		filter := cmd.Flag("filter").Value.String()
+		services := strings.Split(filter, ",")
+		for _, service := range services {
+			if !validateOpenStackFilter(service) {
+				fmt.Printf("Invalid filter selected, please select a supported OpenStack service")
+				return
+			}
+		}
+
+		col, err := collector.NewOpenStackCollector(openStackCloud)
+		if err != nil {
+			fmt.Printf("Failed to create OpenStack collector: %v\n", err)
+			return
+		}
+
+		result, err := collectOpenStackInventory(col, services)
+		if err != nil {
+			return
+		}
+		fmt.Printf("Dumping to %s\n", path)
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			fmt.Printf("Error Marshalling JSON: %v\n", err)
+		}
+		err = ioutil.WriteFile(path, jsonBytes, 0644)
+		if err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+		}
+	},
+}
+
+// collectOpenStackInventory gathers the given services, servers and volumes for an empty service
+func collectOpenStackInventory(col collector.OpenStackCollector, services []string) (collector.OpenStackInventory, error) {
+	var result collector.OpenStackInventory
+	result.Cloud = col.Cloud()
+	var err error
+
+	for _, service := range services {
+		switch service {
+		case "server":
+			err = collectOpenStackServers(col, &result)
+		case "volume":
+			err = collectOpenStackVolumes(col, &result)
+		case "floatingip":
+			err = collectOpenStackFloatingIPs(col, &result)
+		case "loadbalancer":
+			err = collectOpenStackLoadBalancers(col, &result)
+		case "dns":
+			err = collectOpenStackZones(col, &result)
+		default:
+			err = collectOpenStackServers(col, &result)
+			if err != nil {
+				return result, err
+			}
+			err = collectOpenStackVolumes(col, &result)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func validateOpenStackFilter(filter string) bool {
+	validSlice := []string{
+		"server",
+		"volume",
+		"floatingip",
+		"loadbalancer",
+		"dns",
+		"",
+	}
+	for _, v := range validSlice {
+		if filter == v {
+			return true
+		}
+	}
+	return false
+}
+
+func collectOpenStackServers(col collector.OpenStackCollector, result *collector.OpenStackInventory) error {
+	servers, err := col.CollectServers()
+	if err != nil {
+		fmt.Printf("Failed to gather Server Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Servers across %d regions\n", len(servers))
+	result.Servers = servers
+	return nil
+}
+
+func collectOpenStackVolumes(col collector.OpenStackCollector, result *collector.OpenStackInventory) error {
+	volumes, err := col.CollectVolumes()
+	if err != nil {
+		fmt.Printf("Failed to gather Volume Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Volumes across %d regions\n", len(volumes))
+	result.Volumes = volumes
+	return nil
+}
+
+func collectOpenStackFloatingIPs(col collector.OpenStackCollector, result *collector.OpenStackInventory) error {
+	ips, err := col.CollectFloatingIPs()
+	if err != nil {
+		fmt.Printf("Failed to gather Floating IP Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered Floating IPs across %d regions\n", len(ips))
+	result.FloatingIPs = ips
+	return nil
+}
+
+func collectOpenStackLoadBalancers(col collector.OpenStackCollector, result *collector.OpenStackInventory) error {
+	lbs, err := col.CollectLoadBalancers()
+	if err != nil {
+		fmt.Printf("Failed to gather LoadBalancer Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered LoadBalancers across %d regions\n", len(lbs))
+	result.LoadBalancers = lbs
+	return nil
+}
+
+func collectOpenStackZones(col collector.OpenStackCollector, result *collector.OpenStackInventory) error {
+	zones, err := col.CollectZones()
+	if err != nil {
+		fmt.Printf("Failed to gather DNS Data: %v\n", err)
+		return err
+	}
+	fmt.Printf("Gathered DNS Zones across %d regions\n", len(zones))
+	result.Zones = zones
+	return nil
+}
+
+func init() {
+	openstackCmd.Flags().StringVarP(&openStackCloud, "cloud", "", "", "cloud of clouds.yaml to dump, defaults to $OS_CLOUD")
+	dumpCmd.AddCommand(openstackCmd)
+}
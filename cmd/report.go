@@ -0,0 +1,46 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Builds reports out of dumped inventories",
+}
+
+// loadInventories reads every inventory given to a report
+func loadInventories(paths []string) ([]collector.AWSInventory, error) {
+	var inventories []collector.AWSInventory
+	for _, path := range paths {
+		inv, err := collector.LoadAWSInventory(path)
+		if err != nil {
+			fmt.Printf("Failed to load inventory: %v\n", err)
+			return nil, err
+		}
+		inventories = append(inventories, inv)
+	}
+	return inventories, nil
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.PersistentFlags().StringSliceP("inventory", "i", []string{"cloudinventory.json"}, "comma separated inventory files previously created by dump aws")
+	reportCmd.PersistentFlags().StringP("output", "o", "", "optional file path to write the JSON report in")
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/spf13/cobra"
+)
+
+// reportAPIsCmd represents the report apis command
+var reportAPIsCmd = &cobra.Command{
+	Use:   "apis",
+	Short: "Report API Gateway APIs with their endpoints, custom domains, certificates and Route53 names",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		apis := []audit.APISummary{}
+		for _, inv := range inventories {
+			apis = append(apis, audit.SummarizeAPIs(inv)...)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "API\tID\tACCOUNT\tREGION\tPROTOCOL\tENDPOINT TYPE\tENDPOINT\tSTAGES\tDOMAINS\tDNS NAMES")
+		for _, a := range apis {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a.Name, a.ID, a.Account, a.Region, a.Protocol, a.EndpointType,
+				a.Endpoint, strings.Join(a.Stages, ","), strings.Join(a.Domains, ","), strings.Join(a.DNSNames, ","))
+		}
+		w.Flush()
+
+		writeReport(output, apis)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportAPIsCmd)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/spf13/cobra"
+)
+
+var certificateDays int
+
+// reportCertificatesCmd represents the report certificates command
+var reportCertificatesCmd = &cobra.Command{
+	Use:   "certificates",
+	Short: "Report ACM certificates expiring within --days and the load balancers, distributions and Route53 names using them",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		expiring := []audit.ExpiringCertificate{}
+		now := time.Now()
+		for _, inv := range inventories {
+			expiring = append(expiring, audit.FindExpiringCertificates(inv, time.Duration(certificateDays)*24*time.Hour, now)...)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DOMAIN\tACCOUNT\tREGION\tEXPIRES\tDAYS LEFT\tRENEWAL\tUSED BY\tDNS NAMES")
+		for _, c := range expiring {
+			usedBy := append(append(append([]string{}, c.LoadBalancers...), c.Distributions...), c.APIDomains...)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", c.Domain, c.Account, c.Region, c.NotAfter.Format("2006-01-02"),
+				c.DaysLeft, c.RenewalEligibility, strings.Join(usedBy, ","), strings.Join(c.DNSNames, ","))
+		}
+		w.Flush()
+
+		writeReport(output, expiring)
+	},
+}
+
+func init() {
+	reportCertificatesCmd.Flags().IntVarP(&certificateDays, "days", "d", 30, "report certificates expiring within this number of days")
+	reportCmd.AddCommand(reportCertificatesCmd)
+}
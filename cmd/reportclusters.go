@@ -0,0 +1,72 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/spf13/cobra"
+)
+
+var minEKSVersion string
+
+// reportClustersCmd represents the report clusters command
+var reportClustersCmd = &cobra.Command{
+	Use:   "clusters",
+	Short: "Report EKS/ECS clusters, their Kubernetes version support and the EC2 instances acting as their nodes",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		if !awslib.ValidKubernetesVersion(minEKSVersion) {
+			fmt.Printf("Invalid minimum EKS version %q, please use a major.minor version such as %s\n", minEKSVersion, awslib.MinSupportedEKSVersion)
+			return
+		}
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		var report audit.ClusterReport
+		for _, inv := range inventories {
+			r := audit.FindClusterNodes(inv, minEKSVersion)
+			report.Clusters = append(report.Clusters, r.Clusters...)
+			report.Nodes = append(report.Nodes, r.Nodes...)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tACCOUNT\tREGION\tCLUSTER\tVERSION\tUNSUPPORTED\tNODES")
+		for _, c := range report.Clusters {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\t%d\n", c.Service, c.Account, c.Region, c.Name, c.Version, c.Unsupported, c.Nodes)
+		}
+		w.Flush()
+
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "INSTANCE\tACCOUNT\tREGION\tSERVICE\tCLUSTER\tNODE GROUP")
+		for _, n := range report.Nodes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", n.InstanceID, n.Account, n.Region, n.Service, n.Cluster, n.NodeGroup)
+		}
+		w.Flush()
+
+		writeReport(output, report)
+	},
+}
+
+func init() {
+	reportClustersCmd.Flags().StringVarP(&minEKSVersion, "min-eks-version", "", awslib.MinSupportedEKSVersion, "oldest Kubernetes version considered supported for EKS clusters")
+	reportCmd.AddCommand(reportClustersCmd)
+}
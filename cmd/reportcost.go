@@ -0,0 +1,161 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/cost"
+	"github.com/spf13/cobra"
+)
+
+var pricesPath string
+var costGroupBy string
+var ec2Offer string
+var rdsOffer string
+
+// reportCostCmd represents the report cost command
+var reportCostCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Estimate the monthly on-demand cost of EC2/RDS from a local price table",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		var prices *cost.PriceTable
+		var err error
+		if pricesPath == "" {
+			prices, err = cost.DefaultPriceTable()
+		} else {
+			prices, err = cost.LoadPriceTable(pricesPath)
+		}
+		if err != nil {
+			fmt.Printf("Failed to load price table: %v\n", err)
+			return
+		}
+
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		var estimates []cost.Estimate
+		for _, inv := range inventories {
+			estimates = append(estimates, prices.EstimateInventory(inv)...)
+		}
+		totals := cost.GroupBy(estimates, costGroupBy)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\tRESOURCES\tUNPRICED\tMONTHLY (USD)\n", strings.ToUpper(costGroupBy))
+		var sum float64
+		for _, t := range totals {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\n", t.Group, t.Resources, t.Unpriced, t.Monthly)
+			sum += t.Monthly
+		}
+		fmt.Fprintf(w, "TOTAL\t%d\t\t%.2f\n", len(estimates), sum)
+		w.Flush()
+
+		writeReport(output, struct {
+			Groups    []cost.GroupTotal `json:"groups"`
+			Resources []cost.Estimate   `json:"resources"`
+		}{totals, estimates})
+	},
+}
+
+// reportCostRefreshCmd represents the report cost refresh command
+var reportCostRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Refresh a price table from AWS Price List bulk offer files (path or URL)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if pricesPath == "" {
+			pricesPath = "prices.json"
+		}
+		prices, err := cost.LoadPriceTable(pricesPath)
+		if os.IsNotExist(err) {
+			prices, err = cost.DefaultPriceTable()
+		}
+		if err != nil {
+			fmt.Printf("Failed to load price table: %v\n", err)
+			return
+		}
+
+		if ec2Offer != "" {
+			err = readOffer(ec2Offer, func(r io.Reader) error {
+				ec2Prices, err := cost.ParseEC2Offer(r)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Read %d EC2 prices\n", len(ec2Prices))
+				return prices.MergeEC2(ec2Prices)
+			})
+			if err != nil {
+				fmt.Printf("Failed to read EC2 offer: %v\n", err)
+				return
+			}
+		}
+		if rdsOffer != "" {
+			err = readOffer(rdsOffer, func(r io.Reader) error {
+				rdsPrices, err := cost.ParseRDSOffer(r)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Read %d RDS prices\n", len(rdsPrices))
+				return prices.MergeRDS(rdsPrices)
+			})
+			if err != nil {
+				fmt.Printf("Failed to read RDS offer: %v\n", err)
+				return
+			}
+		}
+
+		fmt.Printf("Writing price table to %s\n", pricesPath)
+		err = prices.Save(pricesPath)
+		if err != nil {
+			fmt.Printf("Error writing file: %v\n", err)
+		}
+	},
+}
+
+// readOffer opens an offer file from a local path or an URL and hands it to parse
+func readOffer(location string, parse func(io.Reader) error) error {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		resp, err := http.Get(location)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("Unexpected status %s", resp.Status)
+		}
+		return parse(resp.Body)
+	}
+	f, err := os.Open(location)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return parse(f)
+}
+
+func init() {
+	reportCostCmd.PersistentFlags().StringVarP(&pricesPath, "prices", "", "", "price table to use, defaults to the bundled sample prices")
+	reportCostCmd.Flags().StringVarP(&costGroupBy, "group-by", "g", "service", "group costs by service/region/account/tag:<Key>")
+	reportCostRefreshCmd.Flags().StringVarP(&ec2Offer, "ec2-offer", "", "", "AmazonEC2 offer file, e.g https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/us-east-1/index.json")
+	reportCostRefreshCmd.Flags().StringVarP(&rdsOffer, "rds-offer", "", "", "AmazonRDS offer file, e.g https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current/us-east-1/index.json")
+	reportCostCmd.AddCommand(reportCostRefreshCmd)
+	reportCmd.AddCommand(reportCostCmd)
+}
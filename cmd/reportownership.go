@@ -0,0 +1,72 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/spf13/cobra"
+)
+
+var unmanagedOnly bool
+
+// reportOwnershipCmd represents the report ownership command
+var reportOwnershipCmd = &cobra.Command{
+	Use:   "ownership",
+	Short: "Report the CloudFormation stack owning every resource and the infrastructure as code coverage per service",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		report := audit.OwnershipReport{Resources: []audit.OwnedResource{}, Coverage: []audit.CoverageSummary{}}
+		for _, inv := range inventories {
+			r := audit.FindStackOwnership(inv)
+			for _, resource := range r.Resources {
+				if unmanagedOnly && resource.Stack != audit.Unmanaged {
+					continue
+				}
+				report.Resources = append(report.Resources, resource)
+			}
+			report.Coverage = append(report.Coverage, r.Coverage...)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tACCOUNT\tREGION\tRESOURCE\tSTACK\tSTACK STATUS\tDRIFT")
+		for _, r := range report.Resources {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Service, r.Account, r.Region, r.ID, r.Stack, r.StackStatus, r.DriftStatus)
+		}
+		w.Flush()
+
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ACCOUNT\tSERVICE\tMANAGED\tTOTAL\tPERCENTAGE")
+		for _, c := range report.Coverage {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f%%\n", c.Account, c.Service, c.Managed, c.Total, c.Percentage)
+		}
+		w.Flush()
+
+		writeReport(output, report)
+	},
+}
+
+func init() {
+	reportOwnershipCmd.Flags().BoolVarP(&unmanagedOnly, "unmanaged", "", false, "only list the resources owned by no stack")
+	reportCmd.AddCommand(reportOwnershipCmd)
+}
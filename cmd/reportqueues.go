@@ -0,0 +1,66 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/spf13/cobra"
+)
+
+var missingDLQOnly bool
+
+// reportQueuesCmd represents the report queues command
+var reportQueuesCmd = &cobra.Command{
+	Use:   "queues",
+	Short: "Report SQS queues per region with their dead-letter queue, encryption and retention",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		queues := []audit.QueueSummary{}
+		for _, inv := range inventories {
+			for _, q := range audit.SummarizeQueues(inv) {
+				if missingDLQOnly && !q.MissingDeadLetterQueue {
+					continue
+				}
+				queues = append(queues, q)
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "QUEUE\tACCOUNT\tREGION\tDEAD-LETTER QUEUE\tMISSING DLQ\tENCRYPTED\tRETENTION (S)")
+		for _, q := range queues {
+			dlq := q.DeadLetterQueue
+			if q.IsDeadLetterQueue {
+				dlq = "(is a dead-letter queue)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%t\t%d\n", q.Name, q.Account, q.Region, dlq, q.MissingDeadLetterQueue, q.Encrypted, q.RetentionSeconds)
+		}
+		w.Flush()
+
+		writeReport(output, queues)
+	},
+}
+
+func init() {
+	reportQueuesCmd.Flags().BoolVarP(&missingDLQOnly, "missing-dlq", "", false, "only list the queues without dead-letter queue")
+	reportCmd.AddCommand(reportQueuesCmd)
+}
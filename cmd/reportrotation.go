@@ -0,0 +1,71 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/spf13/cobra"
+)
+
+var rotationMaxAge int
+
+// reportRotationCmd represents the report rotation command
+var reportRotationCmd = &cobra.Command{
+	Use:   "rotation",
+	Short: "Report KMS keys pending deletion and secrets not rotated within --max-age days",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		report := audit.RotationReport{KeysPendingDeletion: []audit.PendingDeletionKey{}, StaleSecrets: []audit.StaleSecret{}}
+		maxAge := time.Duration(rotationMaxAge) * 24 * time.Hour
+		now := time.Now()
+		for _, inv := range inventories {
+			r := audit.FindRotationIssues(inv, maxAge, now)
+			report.KeysPendingDeletion = append(report.KeysPendingDeletion, r.KeysPendingDeletion...)
+			report.StaleSecrets = append(report.StaleSecrets, r.StaleSecrets...)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY PENDING DELETION\tACCOUNT\tREGION\tALIASES\tDELETION DATE")
+		for _, k := range report.KeysPendingDeletion {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", k.KeyID, k.Account, k.Region, strings.Join(k.Aliases, ","), formatDate(k.DeletionDate))
+		}
+		w.Flush()
+
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STALE SECRET\tACCOUNT\tREGION\tROTATION ENABLED\tLAST ROTATED\tLAST ACCESSED\tAGE (DAYS)")
+		for _, s := range report.StaleSecrets {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\t%d\n", s.Name, s.Account, s.Region, s.RotationEnabled, formatDate(s.LastRotated), formatDate(s.LastAccessed), s.AgeDays)
+		}
+		w.Flush()
+
+		writeReport(output, report)
+	},
+}
+
+func init() {
+	reportRotationCmd.Flags().IntVarP(&rotationMaxAge, "max-age", "", 90, "number of days after which a secret should have been rotated")
+	reportCmd.AddCommand(reportRotationCmd)
+}
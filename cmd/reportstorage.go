@@ -0,0 +1,57 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/adobe/cloudinventory/audit"
+	"github.com/spf13/cobra"
+)
+
+// reportStorageCmd represents the report storage command
+var reportStorageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "Report unattached/unencrypted volumes, orphaned snapshots and unused AMIs of inventories dumped with ec2,ebs,autoscaling",
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringSlice("inventory")
+		output := cmd.Flag("output").Value.String()
+
+		inventories, err := loadInventories(paths)
+		if err != nil {
+			return
+		}
+		var report audit.StorageReport
+		for _, inv := range inventories {
+			r := audit.FindOrphans(inv)
+			report.Findings = append(report.Findings, r.Findings...)
+			report.WastedGiB += r.WastedGiB
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tACCOUNT\tREGION\tRESOURCE\tSIZE (GiB)\tREASON")
+		for _, f := range report.Findings {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", f.Kind, f.Account, f.Region, f.ID, f.SizeGiB, f.Reason)
+		}
+		fmt.Fprintf(w, "WASTED\t\t\t\t%d\t\n", report.WastedGiB)
+		w.Flush()
+
+		writeReport(output, report)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportStorageCmd)
+}
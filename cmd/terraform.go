@@ -0,0 +1,74 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/adobe/cloudinventory/terraform"
+	"github.com/spf13/cobra"
+)
+
+var terraformStates []string
+
+// terraformCmd represents the terraform command
+var terraformCmd = &cobra.Command{
+	Use:   "terraform",
+	Short: "Generate Terraform import blocks for the resources of a dumped AWS inventory missing from Terraform states",
+	Long: `Generate Terraform import blocks and skeleton declarations for the EC2 instances, RDS instances, load balancers,
+Route53 zones and Route53 records of a dumped AWS inventory which are not managed by any of the given state files`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := cmd.Flag("inventory").Value.String()
+		output := cmd.Flag("output").Value.String()
+
+		inv, err := collector.LoadAWSInventory(path)
+		if err != nil {
+			fmt.Printf("Failed to load inventory: %v\n", err)
+			return
+		}
+		state := terraform.NewState()
+		for _, statePath := range terraformStates {
+			if err := state.Load(statePath); err != nil {
+				fmt.Printf("Failed to load Terraform state: %v\n", err)
+				return
+			}
+		}
+		resources := terraform.Imports(inv, state)
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				fmt.Printf("Error creating file: %v\n", err)
+				return
+			}
+			defer f.Close()
+			w = f
+			fmt.Printf("Writing %d import blocks to %s\n", len(resources), output)
+		}
+		err = terraform.Write(w, resources)
+		if err != nil {
+			fmt.Printf("Error writing import blocks: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	terraformCmd.Flags().StringP("inventory", "i", "cloudinventory.json", "inventory file previously created by dump aws")
+	terraformCmd.Flags().StringP("output", "o", "", "file path to write the import blocks in, defaults to stdout")
+	terraformCmd.Flags().StringSliceVarP(&terraformStates, "state", "s", []string{}, "comma separated list of Terraform state files whose resources are already managed")
+	rootCmd.AddCommand(terraformCmd)
+}
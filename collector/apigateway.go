@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectAPIGateway returns a concurrently collected API Gateway REST API and custom domain name inventory for all the regions
+func (col AWSCollector) CollectAPIGateway() (map[string]*awslib.APIGateway, error) {
+	gateways := make(map[string]*awslib.APIGateway)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectAPIGatewayPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no APIs nor domain names
+		if len(chunk.RestAPIs) == 0 && len(chunk.DomainNames) == 0 {
+			return nil
+		}
+		mu.Lock()
+		gateways[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather API Gateway Data: %v", err)
+	}
+	return gateways, nil
+}
+
+// CollectAPIGatewayPerSession returns an API Gateway REST API and custom domain name inventory for a given session
+func CollectAPIGatewayPerSession(sess *session.Session) (*awslib.APIGateway, error) {
+	gateway, err := awslib.GetAPIGateway(sess)
+	return gateway, err
+}
+
+// CollectAPIGatewayV2 returns a concurrently collected API Gateway HTTP and WebSocket API inventory for all the regions
+func (col AWSCollector) CollectAPIGatewayV2() (map[string]*awslib.APIGatewayV2, error) {
+	gateways := make(map[string]*awslib.APIGatewayV2)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectAPIGatewayV2PerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no APIs nor domain names
+		if len(chunk.APIs) == 0 && len(chunk.DomainNames) == 0 {
+			return nil
+		}
+		mu.Lock()
+		gateways[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather API Gateway V2 Data: %v", err)
+	}
+	return gateways, nil
+}
+
+// CollectAPIGatewayV2PerSession returns an API Gateway HTTP and WebSocket API inventory for a given session
+func CollectAPIGatewayV2PerSession(sess *session.Session) (*awslib.APIGatewayV2, error) {
+	gateway, err := awslib.GetAPIGatewayV2(sess)
+	return gateway, err
+}
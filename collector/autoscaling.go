@@ -0,0 +1,57 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectAutoScaling returns a concurrently collected Auto Scaling group, launch template and launch configuration inventory for all the regions
+func (col AWSCollector) CollectAutoScaling() (map[string]*awslib.AutoScaling, error) {
+	groups := make(map[string]*awslib.AutoScaling)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectAutoScalingPerSession(sess)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		groups[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Auto Scaling Data: %v", err)
+	}
+	return groups, nil
+}
+
+// CollectAutoScalingPerSession returns an Auto Scaling group, launch template and launch configuration inventory for a given session
+func CollectAutoScalingPerSession(sess *session.Session) (*awslib.AutoScaling, error) {
+	groups, err := awslib.GetAutoScaling(sess)
+	return groups, err
+}
+
+// AnnotateEC2 sets the Auto Scaling membership of the instances of every region from the groups collected there
+func AnnotateEC2(instances map[string][]*awslib.Instance, autoscaling map[string]*awslib.AutoScaling) {
+	for region, ii := range instances {
+		if a := autoscaling[region]; a != nil {
+			awslib.AnnotateInstances(ii, a.Groups)
+		}
+	}
+}
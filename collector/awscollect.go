@@ -22,9 +22,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/elb"
-	"github.com/aws/aws-sdk-go/service/elbv2"
-	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/jpillora/backoff"
 )
@@ -91,6 +88,38 @@ func (col AWSCollector) CheckCredentials() bool {
 	return true
 }
 
+// AccountID returns the ID of the AWS account the collector credentials belong to
+func (col AWSCollector) AccountID() (string, error) {
+	var stsSession *session.Session
+	for _, session := range col.sessions {
+		stsSession = session
+	}
+	return awslib.GetAccountID(stsSession)
+}
+
+// forEachRegion concurrently calls fn with every region session and returns the first error encountered
+func (col AWSCollector) forEachRegion(fn func(region string, sess *session.Session) error) error {
+	errChan := make(chan error, len(col.sessions))
+	var wg sync.WaitGroup
+
+	for region, sess := range col.sessions {
+		wg.Add(1)
+		go func(sess *session.Session, region string) {
+			defer wg.Done()
+			if err := fn(region, sess); err != nil {
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
+			}
+		}(sess, region)
+	}
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return <-errChan
+	}
+	return nil
+}
+
 // CollectEC2 returns a concurrently collected EC2 inventory for all the regions
 func (col AWSCollector) CollectEC2() (map[string][]*ec2.Instance, error) {
 	instances := make(map[string][]*ec2.Instance)
@@ -112,7 +141,7 @@ func (col AWSCollector) CollectEC2() (map[string][]*ec2.Instance, error) {
 			chunk, err := CollectEC2PerSession(sess)
 
 			if err != nil {
-				errChan <- fmt.Errorf(fmt.Sprintf("Error while gathering %s: %v", region, err))
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
 				return
 			}
 
@@ -128,7 +157,7 @@ func (col AWSCollector) CollectEC2() (map[string][]*ec2.Instance, error) {
 	close(errChan)
 
 	if len(errChan) > 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to gather EC2 Data: %v", <-errChan))
+		return nil, fmt.Errorf("Failed to gather EC2 Data: %v", <-errChan)
 	}
 
 	for regionChunk := range instancesChan {
@@ -180,56 +209,55 @@ func (col AWSCollector) CollectZones() ([]*route53.HostedZone, error) {
 
 // GetHostedZoneRecords returns the hostedzonesRecords for a particular hostedZoneId
 func (col AWSCollector) GetHostedZoneRecords(hostedZoneId string) ([]*route53.ResourceRecordSet, error) {
-	var nextPageExists = true
-
-	b := &backoff.Backoff{
-		//These are the defaults
-		Min:    10 * time.Millisecond,
-		Max:    1 * time.Second,
-		Factor: 2,
-		Jitter: false,
-	}
-
 	var route53Session *session.Session
 
 	for _, session := range col.sessions {
 		route53Session = session
 	}
-	r53 := route53.New(route53Session)
+	return awslib.GetHostedZoneRecords(route53Session, hostedZoneId)
+}
 
-	records := make([]*route53.ResourceRecordSet, 0)
-	request := &route53.ListResourceRecordSetsInput{
-		HostedZoneId: &hostedZoneId,
-	}
+// maxZoneRecordsWorkers bounds the hosted zones whose records are listed concurrently, Route53 allows 5 requests per second per account
+const maxZoneRecordsWorkers = 4
 
-	for nextPageExists {
+// CollectZoneRecords returns the given hosted zones along with all their record sets, listed concurrently
+func (col AWSCollector) CollectZoneRecords(zones []*route53.HostedZone) ([]*awslib.HostedZone, error) {
+	withRecords := make([]*awslib.HostedZone, len(zones))
+	errChan := make(chan error, len(zones))
+	sem := make(chan struct{}, maxZoneRecordsWorkers)
+	var wg sync.WaitGroup
 
-		response, err := r53.ListResourceRecordSets(request)
-		if err != nil {
-			time.Sleep(b.Duration())
-		} else {
-			records = append(records, response.ResourceRecordSets...)
-			if response.IsTruncated == nil || !*response.IsTruncated {
-				nextPageExists = false
-				break
+	for i, zone := range zones {
+		wg.Add(1)
+		go func(i int, zone *route53.HostedZone) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			records, err := col.GetHostedZoneRecords(*zone.Id)
+			if err != nil {
+				errChan <- fmt.Errorf("Failed to gather records of %s: %v", *zone.Name, err)
+				return
 			}
-			// Setting next page.
-			request.StartRecordName = response.NextRecordName
-			request.StartRecordIdentifier = response.NextRecordIdentifier
-			request.StartRecordType = response.NextRecordType
-		}
+			withRecords[i] = &awslib.HostedZone{HostedZone: zone, Records: records}
+		}(i, zone)
 	}
-	return records, nil
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return nil, <-errChan
+	}
+	return withRecords, nil
 }
 
 // CollectClassicLoadBalancers returns a concurrently collected LoadBalancers inventory for all the regions
-func (col AWSCollector) CollectClassicLoadBalancers() (map[string][]*elb.LoadBalancerDescription, error) {
-	instances := make(map[string][]*elb.LoadBalancerDescription)
+func (col AWSCollector) CollectClassicLoadBalancers() (map[string][]*awslib.ClassicLoadBalancer, error) {
+	instances := make(map[string][]*awslib.ClassicLoadBalancer)
 
 	// instanceRegion is a struct that holds all load balancers instances in a given region
 	type instanceRegion struct {
 		region    string
-		instances []*elb.LoadBalancerDescription
+		instances []*awslib.ClassicLoadBalancer
 	}
 
 	instancesChan := make(chan instanceRegion, len(col.sessions))
@@ -243,7 +271,7 @@ func (col AWSCollector) CollectClassicLoadBalancers() (map[string][]*elb.LoadBal
 			chunk, err := CollectClassicLoadBalancerPerSession(sess)
 
 			if err != nil {
-				errChan <- fmt.Errorf(fmt.Sprintf("Error while gathering %s: %v", region, err))
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
 				return
 			}
 
@@ -259,7 +287,7 @@ func (col AWSCollector) CollectClassicLoadBalancers() (map[string][]*elb.LoadBal
 	close(errChan)
 
 	if len(errChan) > 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to gather LoadBalancers Data: %v", <-errChan))
+		return nil, fmt.Errorf("Failed to gather LoadBalancers Data: %v", <-errChan)
 	}
 
 	for regionChunk := range instancesChan {
@@ -269,13 +297,13 @@ func (col AWSCollector) CollectClassicLoadBalancers() (map[string][]*elb.LoadBal
 }
 
 // CollectApplicationAndNetworkLoadBalancers returns a concurrently collected LoadBalancers inventory for all the regions
-func (col AWSCollector) CollectApplicationAndNetworkLoadBalancers() (map[string][]*elbv2.LoadBalancer, error) {
-	instances := make(map[string][]*elbv2.LoadBalancer)
+func (col AWSCollector) CollectApplicationAndNetworkLoadBalancers() (map[string][]*awslib.ApplicationNetworkLoadBalancer, error) {
+	instances := make(map[string][]*awslib.ApplicationNetworkLoadBalancer)
 
 	// instanceRegion is a struct that holds all load balancers instances in a given region
 	type instanceRegion struct {
 		region    string
-		instances []*elbv2.LoadBalancer
+		instances []*awslib.ApplicationNetworkLoadBalancer
 	}
 
 	instancesChan := make(chan instanceRegion, len(col.sessions))
@@ -289,7 +317,7 @@ func (col AWSCollector) CollectApplicationAndNetworkLoadBalancers() (map[string]
 			chunk, err := CollectApplicationNetworkLoadBalancerPerSession(sess)
 
 			if err != nil {
-				errChan <- fmt.Errorf(fmt.Sprintf("Error while gathering %s: %v", region, err))
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
 				return
 			}
 
@@ -305,7 +333,7 @@ func (col AWSCollector) CollectApplicationAndNetworkLoadBalancers() (map[string]
 	close(errChan)
 
 	if len(errChan) > 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to gather LoadBalancers Data: %v", <-errChan))
+		return nil, fmt.Errorf("Failed to gather LoadBalancers Data: %v", <-errChan)
 	}
 
 	for regionChunk := range instancesChan {
@@ -315,13 +343,13 @@ func (col AWSCollector) CollectApplicationAndNetworkLoadBalancers() (map[string]
 }
 
 // CollectRDS returns a concurrently collected RDS inventory for all the regions
-func (col AWSCollector) CollectRDS() (map[string][]*rds.DBInstance, error) {
-	instances := make(map[string][]*rds.DBInstance)
+func (col AWSCollector) CollectRDS() (map[string][]*awslib.DBInstance, error) {
+	instances := make(map[string][]*awslib.DBInstance)
 
 	// instanceRegion is a struct that holds all RDS instances in a given region
 	type instanceRegion struct {
 		region    string
-		instances []*rds.DBInstance
+		instances []*awslib.DBInstance
 	}
 
 	instancesChan := make(chan instanceRegion, len(col.sessions))
@@ -335,7 +363,7 @@ func (col AWSCollector) CollectRDS() (map[string][]*rds.DBInstance, error) {
 			chunk, err := CollectRDSPerSession(sess)
 
 			if err != nil {
-				errChan <- fmt.Errorf(fmt.Sprintf("Error while gathering %s: %v", region, err))
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
 				return
 			}
 
@@ -351,7 +379,7 @@ func (col AWSCollector) CollectRDS() (map[string][]*rds.DBInstance, error) {
 	close(errChan)
 
 	if len(errChan) > 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to gather RDS Data: %v", <-errChan))
+		return nil, fmt.Errorf("Failed to gather RDS Data: %v", <-errChan)
 	}
 
 	for regionChunk := range instancesChan {
@@ -361,10 +389,13 @@ func (col AWSCollector) CollectRDS() (map[string][]*rds.DBInstance, error) {
 
 }
 
-// CollectRDSPerSession returns an RDS inventory for a given session
-func CollectRDSPerSession(sess *session.Session) ([]*rds.DBInstance, error) {
+// CollectRDSPerSession returns a tagged RDS inventory for a given session
+func CollectRDSPerSession(sess *session.Session) ([]*awslib.DBInstance, error) {
 	instances, err := awslib.GetAllDBInstances(sess)
-	return instances, err
+	if err != nil {
+		return nil, err
+	}
+	return awslib.NewDBInstances(instances), nil
 }
 
 // CollectEC2PerSession returns an EC2 inventory for a given session
@@ -379,14 +410,30 @@ func CollectHostedZonePerSession(sess *session.Session) ([]*route53.HostedZone,
 	return instances, err
 }
 
-// CollectClassicLoadBalancerPerSession returns an LoadBalancer inventory for a given session
-func CollectClassicLoadBalancerPerSession(sess *session.Session) ([]*elb.LoadBalancerDescription, error) {
+// CollectClassicLoadBalancerPerSession returns a tagged LoadBalancer inventory with instance health for a given session
+func CollectClassicLoadBalancerPerSession(sess *session.Session) ([]*awslib.ClassicLoadBalancer, error) {
 	loadbalancers, err := awslib.GetAllCLB(sess)
-	return loadbalancers, err
+	if err != nil {
+		return nil, err
+	}
+	tagged, err := awslib.TagCLBs(sess, loadbalancers)
+	if err != nil {
+		return nil, err
+	}
+	err = awslib.DescribeCLBInstanceHealth(sess, tagged)
+	return tagged, err
 }
 
-// CollectApplicationNetworkLoadBalancerPerSession returns an LoadBalancer inventory for a given session
-func CollectApplicationNetworkLoadBalancerPerSession(sess *session.Session) ([]*elbv2.LoadBalancer, error) {
+// CollectApplicationNetworkLoadBalancerPerSession returns a tagged LoadBalancer inventory with listeners and target groups for a given session
+func CollectApplicationNetworkLoadBalancerPerSession(sess *session.Session) ([]*awslib.ApplicationNetworkLoadBalancer, error) {
 	loadbalancers, err := awslib.GetAllALBAndNLB(sess)
-	return loadbalancers, err
+	if err != nil {
+		return nil, err
+	}
+	tagged, err := awslib.TagALBAndNLBs(sess, loadbalancers)
+	if err != nil {
+		return nil, err
+	}
+	err = awslib.DescribeALBAndNLBTargets(sess, tagged)
+	return tagged, err
 }
@@ -13,11 +13,40 @@ governing permissions and limitations under the License.
 package collector
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
 )
 
+// newTestCollector returns a collector whose sessions for the given regions send every request to handler
+func newTestCollector(t *testing.T, handler http.HandlerFunc, regions ...string) (AWSCollector, func()) {
+	server := httptest.NewServer(handler)
+	col := AWSCollector{sessions: make(map[string]*session.Session)}
+	for _, region := range regions {
+		sess, err := session.NewSession(&aws.Config{
+			Region:      aws.String(region),
+			Endpoint:    aws.String(server.URL + "/" + region),
+			Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+			MaxRetries:  aws.Int(0),
+		})
+		if err != nil {
+			server.Close()
+			t.Fatalf("Unable to build session: %v", err)
+		}
+		col.sessions[region] = sess
+	}
+	return col, server.Close
+}
+
 // TestAWSCollectorCreation attempts to build a new collector with initialized sessions for the given partition. This test is also very credential dependent.
 func TestAWSCollectorCreation(t *testing.T) {
 	if testing.Short() {
@@ -87,6 +116,83 @@ func TestCollectRDS(t *testing.T) {
 	}
 }
 
+// TestCollectZoneRecords checks that the records of every zone are listed and kept in the order of the zones
+func TestCollectZoneRecords(t *testing.T) {
+	col, closeServer := newTestCollector(t, func(w http.ResponseWriter, r *http.Request) {
+		// Paths look like /us-east-1/2013-04-01/hostedzone/Z1/rrset
+		parts := strings.Split(r.URL.Path, "/")
+		zone := parts[len(parts)-2]
+		fmt.Fprintf(w, `<ListResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><ResourceRecordSets><ResourceRecordSet><Name>%s.example.com.</Name><Type>A</Type></ResourceRecordSet></ResourceRecordSets><IsTruncated>false</IsTruncated><MaxItems>100</MaxItems></ListResourceRecordSetsResponse>`, zone)
+	}, "us-east-1")
+	defer closeServer()
+
+	var zones []*route53.HostedZone
+	for i := 0; i < 10; i++ {
+		zones = append(zones, &route53.HostedZone{Id: aws.String(fmt.Sprintf("/hostedzone/Z%d", i)), Name: aws.String(fmt.Sprintf("z%d.example.com.", i))})
+	}
+	withRecords, err := col.CollectZoneRecords(zones)
+	if err != nil {
+		t.Fatalf("Failed to collect records: %v", err)
+	}
+	if len(withRecords) != len(zones) {
+		t.Fatalf("Expected %d zones, found %d", len(zones), len(withRecords))
+	}
+	for i, z := range withRecords {
+		want := fmt.Sprintf("Z%d.example.com.", i)
+		if z.HostedZone != zones[i] || len(z.Records) != 1 || aws.StringValue(z.Records[0].Name) != want {
+			t.Errorf("Unexpected records for %s: %v", aws.StringValue(zones[i].Id), z.Records)
+		}
+	}
+}
+
+// TestCollectZoneRecordsErrors checks that throttled zones are retried and other errors returned
+func TestCollectZoneRecordsErrors(t *testing.T) {
+	var throttled int32
+	col, closeServer := newTestCollector(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/hostedzone/Z0/") && atomic.CompareAndSwapInt32(&throttled, 0, 1) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`)
+			return
+		}
+		if strings.Contains(r.URL.Path, "/hostedzone/Z1/") {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><Error><Type>Sender</Type><Code>AccessDenied</Code><Message>denied</Message></Error></ErrorResponse>`)
+			return
+		}
+		fmt.Fprint(w, `<ListResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><ResourceRecordSets></ResourceRecordSets><IsTruncated>false</IsTruncated><MaxItems>100</MaxItems></ListResourceRecordSetsResponse>`)
+	}, "us-east-1")
+	defer closeServer()
+
+	z0 := &route53.HostedZone{Id: aws.String("/hostedzone/Z0"), Name: aws.String("z0.example.com.")}
+	z1 := &route53.HostedZone{Id: aws.String("/hostedzone/Z1"), Name: aws.String("z1.example.com.")}
+	if _, err := col.CollectZoneRecords([]*route53.HostedZone{z0}); err != nil {
+		t.Errorf("Expected the throttled zone to be retried, got %v", err)
+	}
+	if _, err := col.CollectZoneRecords([]*route53.HostedZone{z0, z1}); err == nil {
+		t.Errorf("Expected the denied zone to fail the collection")
+	}
+}
+
+// TestCollectLambda checks that regions without functions are left out of the inventory
+func TestCollectLambda(t *testing.T) {
+	col, closeServer := newTestCollector(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/us-east-1/") {
+			fmt.Fprint(w, `{"Functions": [{"FunctionName": "f", "FunctionArn": "arn:f", "Runtime": "go1.x"}], "Tags": {}}`)
+			return
+		}
+		fmt.Fprint(w, `{"Functions": []}`)
+	}, "us-east-1", "eu-west-1")
+	defer closeServer()
+
+	functions, err := col.CollectLambda()
+	if err != nil {
+		t.Fatalf("Failed to collect functions: %v", err)
+	}
+	if len(functions) != 1 || len(functions["us-east-1"]) != 1 {
+		t.Errorf("Expected a single function in us-east-1, found %v", functions)
+	}
+}
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -0,0 +1,206 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/azurelib"
+)
+
+// NewAzureCollector returns an AzureCollector for every enabled subscription the credentials have access to.
+// Uses the environment credentials if creds is nil
+func NewAzureCollector(creds azurelib.TokenSource) (AzureCollector, error) {
+	if creds == nil {
+		sp, err := azurelib.EnvironmentCredentials()
+		if err != nil {
+			return AzureCollector{}, fmt.Errorf("Error obtaining Azure Credentials: %v", err)
+		}
+		creds = sp
+	}
+	return NewAzureCollectorWithClient(azurelib.NewClient(creds))
+}
+
+// NewAzureCollectorWithClient returns an AzureCollector for every enabled subscription visible to the given client
+func NewAzureCollectorWithClient(client *azurelib.Client) (AzureCollector, error) {
+	col := AzureCollector{client: client}
+	subscriptions, err := azurelib.GetAllSubscriptions(client)
+	if err != nil {
+		return col, fmt.Errorf("Unable to list Azure Subscriptions: %v", err)
+	}
+	for _, s := range subscriptions {
+		if s.State == "Enabled" {
+			col.subscriptions = append(col.subscriptions, s)
+		}
+	}
+	if len(col.subscriptions) == 0 {
+		return col, fmt.Errorf("No enabled Azure Subscription found")
+	}
+	return col, nil
+}
+
+// AzureCollector is a concurrent inventory collection struct for Microsoft Azure.
+// Resources are collected concurrently per subscription and keyed by subscription ID and region
+type AzureCollector struct {
+	client        *azurelib.Client
+	subscriptions []*azurelib.Subscription
+}
+
+// Subscriptions returns the subscriptions the collector gathers
+func (col AzureCollector) Subscriptions() []*azurelib.Subscription {
+	return col.subscriptions
+}
+
+// Restrict limits the collector to the given subscription IDs or display names
+func (col *AzureCollector) Restrict(subscriptions []string) error {
+	var kept []*azurelib.Subscription
+	for _, wanted := range subscriptions {
+		found := false
+		for _, s := range col.subscriptions {
+			if s.SubscriptionID == wanted || s.DisplayName == wanted {
+				kept = append(kept, s)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Subscription %s not found", wanted)
+		}
+	}
+	col.subscriptions = kept
+	return nil
+}
+
+// forEachSubscription concurrently calls fn with every subscription ID and returns the first error encountered
+func (col AzureCollector) forEachSubscription(fn func(subscriptionID string) error) error {
+	errChan := make(chan error, len(col.subscriptions))
+	var wg sync.WaitGroup
+
+	for _, s := range col.subscriptions {
+		wg.Add(1)
+		go func(subscriptionID string) {
+			defer wg.Done()
+			if err := fn(subscriptionID); err != nil {
+				errChan <- fmt.Errorf("Error while gathering %s: %v", subscriptionID, err)
+			}
+		}(s.SubscriptionID)
+	}
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return <-errChan
+	}
+	return nil
+}
+
+// collectResources concurrently collects a resource type for all the subscriptions, keyed by subscription and region
+func (col AzureCollector) collectResources(get func(*azurelib.Client, string) ([]*azurelib.Resource, error)) (map[string]map[string][]*azurelib.Resource, error) {
+	resources := make(map[string]map[string][]*azurelib.Resource)
+	var mu sync.Mutex
+
+	err := col.forEachSubscription(func(subscriptionID string) error {
+		chunk, err := get(col.client, subscriptionID)
+		if err != nil {
+			return err
+		}
+		// Ignore subscriptions with no resources
+		if chunk == nil {
+			return nil
+		}
+		byRegion := make(map[string][]*azurelib.Resource)
+		for _, r := range chunk {
+			byRegion[r.Location] = append(byRegion[r.Location], r)
+		}
+		mu.Lock()
+		resources[subscriptionID] = byRegion
+		mu.Unlock()
+		return nil
+	})
+	return resources, err
+}
+
+// CollectVirtualMachines returns a concurrently collected virtual machine inventory for all the subscriptions
+func (col AzureCollector) CollectVirtualMachines() (map[string]map[string][]*azurelib.Resource, error) {
+	vms, err := col.collectResources(azurelib.GetAllVirtualMachines)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Virtual Machine Data: %v", err)
+	}
+	return vms, nil
+}
+
+// CollectDisks returns a concurrently collected managed disk inventory for all the subscriptions
+func (col AzureCollector) CollectDisks() (map[string]map[string][]*azurelib.Resource, error) {
+	disks, err := col.collectResources(azurelib.GetAllDisks)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Disk Data: %v", err)
+	}
+	return disks, nil
+}
+
+// CollectLoadBalancers returns a concurrently collected load balancer inventory for all the subscriptions
+func (col AzureCollector) CollectLoadBalancers() (map[string]map[string][]*azurelib.Resource, error) {
+	lbs, err := col.collectResources(azurelib.GetAllLoadBalancers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Load Balancer Data: %v", err)
+	}
+	return lbs, nil
+}
+
+// CollectPublicIPAddresses returns a concurrently collected public IP address inventory for all the subscriptions
+func (col AzureCollector) CollectPublicIPAddresses() (map[string]map[string][]*azurelib.Resource, error) {
+	ips, err := col.collectResources(azurelib.GetAllPublicIPAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Public IP Data: %v", err)
+	}
+	return ips, nil
+}
+
+// CollectDNSZones returns a concurrently collected DNS zone inventory for all the subscriptions
+func (col AzureCollector) CollectDNSZones() (map[string]map[string][]*azurelib.Resource, error) {
+	zones, err := col.collectResources(azurelib.GetAllDNSZones)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather DNS Zone Data: %v", err)
+	}
+	return zones, nil
+}
+
+// CollectSQL returns a concurrently collected SQL server and database inventory for all the subscriptions
+func (col AzureCollector) CollectSQL() (map[string]map[string][]*azurelib.SQLServer, error) {
+	servers := make(map[string]map[string][]*azurelib.SQLServer)
+	var mu sync.Mutex
+
+	err := col.forEachSubscription(func(subscriptionID string) error {
+		chunk, err := azurelib.GetAllSQLServers(col.client, subscriptionID)
+		if err != nil {
+			return err
+		}
+		// Ignore subscriptions with no servers
+		if chunk == nil {
+			return nil
+		}
+		byRegion := make(map[string][]*azurelib.SQLServer)
+		for _, s := range chunk {
+			byRegion[s.Location] = append(byRegion[s.Location], s)
+		}
+		mu.Lock()
+		servers[subscriptionID] = byRegion
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather SQL Data: %v", err)
+	}
+	return servers, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/cloudinventory/azurelib"
+)
+
+type staticToken string
+
+func (s staticToken) Token() (string, error) {
+	return string(s), nil
+}
+
+// armStandIn serves two enabled subscriptions and one disabled, with a virtual machine and a SQL server in s1
+var armStandIn = map[string]string{
+	"/subscriptions": `{"value": [
+		{"subscriptionId": "s1", "displayName": "prod", "state": "Enabled"},
+		{"subscriptionId": "s2", "displayName": "dev", "state": "Enabled"},
+		{"subscriptionId": "s3", "displayName": "old", "state": "Disabled"}
+	]}`,
+	"/subscriptions/s1/providers/Microsoft.Compute/virtualMachines": `{"value": [
+		{"id": "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/web", "name": "web", "location": "westeurope"},
+		{"id": "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/batch", "name": "batch", "location": "eastus"}
+	]}`,
+	"/subscriptions/s2/providers/Microsoft.Compute/virtualMachines": `{"value": []}`,
+	"/subscriptions/s1/providers/Microsoft.Sql/servers": `{"value": [
+		{"id": "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Sql/servers/orders", "name": "orders", "location": "westeurope"}
+	]}`,
+	"/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Sql/servers/orders/databases": `{"value": [
+		{"id": "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Sql/servers/orders/databases/master", "name": "master", "location": "westeurope"},
+		{"id": "/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Sql/servers/orders/databases/orders", "name": "orders", "location": "westeurope",
+			"sku": {"name": "GP_Gen5", "tier": "GeneralPurpose", "capacity": 2}}
+	]}`,
+	"/subscriptions/s2/providers/Microsoft.Sql/servers": `{"value": []}`,
+}
+
+func TestAzureCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		page, ok := armStandIn[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": "NotFound", "message": "not found"}}`)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	col, err := NewAzureCollectorWithClient(&azurelib.Client{Endpoint: server.URL, Credentials: staticToken("test"), HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("Failed to create Azure collector: %v", err)
+	}
+	if len(col.Subscriptions()) != 2 {
+		t.Errorf("Expected the 2 enabled subscriptions, got %+v", col.Subscriptions())
+	}
+
+	vms, err := col.CollectVirtualMachines()
+	if err != nil {
+		t.Fatalf("Failed to collect virtual machines: %v", err)
+	}
+	if len(vms["s1"]["westeurope"]) != 1 || len(vms["s1"]["eastus"]) != 1 || len(vms["s2"]) != 0 {
+		t.Errorf("Unexpected virtual machines %+v", vms)
+	}
+
+	sql, err := col.CollectSQL()
+	if err != nil {
+		t.Fatalf("Failed to collect SQL servers: %v", err)
+	}
+	servers := sql["s1"]["westeurope"]
+	if len(servers) != 1 || len(servers[0].Databases) != 1 || servers[0].Databases[0].SKU.Tier != "GeneralPurpose" {
+		t.Errorf("Unexpected SQL servers %+v", servers)
+	}
+
+	if err := col.Restrict([]string{"dev"}); err != nil || len(col.Subscriptions()) != 1 || col.Subscriptions()[0].SubscriptionID != "s2" {
+		t.Errorf("Expected to restrict to dev, got %v %+v", err, col.Subscriptions())
+	}
+	// Disks are not served by the stand-in
+	if _, err := col.CollectDisks(); err == nil {
+		t.Errorf("Expected an error collecting disks")
+	}
+}
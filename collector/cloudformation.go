@@ -0,0 +1,335 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// CollectCloudFormation returns a concurrently collected CloudFormation stack inventory for all the regions
+func (col AWSCollector) CollectCloudFormation() (map[string][]*awslib.Stack, error) {
+	stacks := make(map[string][]*awslib.Stack)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectCloudFormationPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no stacks
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		stacks[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather CloudFormation Data: %v", err)
+	}
+	return stacks, nil
+}
+
+// CollectCloudFormationPerSession returns a CloudFormation stack inventory for a given session
+func CollectCloudFormationPerSession(sess *session.Session) ([]*awslib.Stack, error) {
+	stacks, err := awslib.GetAllStacks(sess)
+	return stacks, err
+}
+
+// Unmanaged is the stack of the resources owned by no CloudFormation stack
+const Unmanaged = "unmanaged"
+
+// StackResource is a resource managed by a CloudFormation stack
+type StackResource struct {
+	Stack    *awslib.Stack
+	Resource *cloudformation.StackResourceSummary
+}
+
+// StackOwnership finds the stack owning a physical resource ID
+type StackOwnership struct {
+	byRegion map[[2]string]StackResource
+	// byID ignores the region, for global resources
+	byID map[string]StackResource
+}
+
+// NewStackOwnership indexes the resources of the stacks of an inventory dumped with cloudformation by physical ID
+func NewStackOwnership(inv AWSInventory) StackOwnership {
+	o := StackOwnership{byRegion: make(map[[2]string]StackResource), byID: make(map[string]StackResource)}
+	for region, stacks := range inv.CloudFormation {
+		for _, s := range stacks {
+			for _, r := range s.Resources {
+				id := aws.StringValue(r.PhysicalResourceId)
+				// Retained resources are no longer managed by the stack
+				switch aws.StringValue(r.ResourceStatus) {
+				case cloudformation.ResourceStatusDeleteComplete, cloudformation.ResourceStatusDeleteSkipped:
+					continue
+				}
+				if id == "" {
+					continue
+				}
+				o.byRegion[[2]string{region, id}] = StackResource{s, r}
+				o.byID[id] = StackResource{s, r}
+			}
+		}
+	}
+	return o
+}
+
+// Find returns the stack resource matching any of the physical IDs of a resource. The region is ignored for global
+// resources and S3 buckets, whose stack may live in any region
+func (o StackOwnership) Find(service, region string, ids []string) (StackResource, bool) {
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if sr, ok := o.byRegion[[2]string{region, id}]; ok {
+			return sr, true
+		}
+		if region == "" || service == "s3" {
+			if sr, ok := o.byID[id]; ok {
+				return sr, true
+			}
+		}
+	}
+	return StackResource{}, false
+}
+
+// OwnableResource is a collected resource a CloudFormation stack may own, known to stacks by its ID or any of
+// its PhysicalIDs. Stack points to the Stack field of the resources wrapped by awslib, and is nil for the others
+type OwnableResource struct {
+	Service     string
+	Region      string
+	ID          string
+	PhysicalIDs []string
+	Stack       *string
+}
+
+// OwnableResources lists the resources of an inventory a CloudFormation stack may own. Instances launched by an
+// Auto Scaling group are known through the group name
+func OwnableResources(inv AWSInventory) []OwnableResource {
+	var resources []OwnableResource
+	add := func(service, region, id string, stack *string, physicalIDs ...string) {
+		resources = append(resources, OwnableResource{service, region, id, physicalIDs, stack})
+	}
+
+	for region, instances := range inv.EC2 {
+		for _, i := range instances {
+			add("ec2", region, aws.StringValue(i.InstanceId), &i.Stack, i.AutoScalingGroupName)
+		}
+	}
+	for region, dbs := range inv.RDS {
+		for _, db := range dbs {
+			add("rds", region, aws.StringValue(db.DBInstanceIdentifier), &db.Stack, aws.StringValue(db.DBInstanceArn))
+		}
+	}
+	for region, topology := range inv.RDSTopology {
+		if topology == nil {
+			continue
+		}
+		for _, c := range topology.Clusters {
+			add("rdscluster", region, aws.StringValue(c.DBClusterIdentifier), &c.Stack, aws.StringValue(c.DBClusterArn))
+		}
+	}
+	if inv.LoadBalancers != nil {
+		for region, lbs := range inv.LoadBalancers.Classic {
+			for _, lb := range lbs {
+				add("loadbalancer", region, aws.StringValue(lb.LoadBalancerName), &lb.Stack)
+			}
+		}
+		for region, lbs := range inv.LoadBalancers.ApplicationNetwork {
+			for _, lb := range lbs {
+				add("loadbalancer", region, aws.StringValue(lb.LoadBalancerName), &lb.Stack, aws.StringValue(lb.LoadBalancerArn))
+			}
+		}
+	}
+	for region, buckets := range inv.S3 {
+		for _, b := range buckets {
+			add("s3", region, aws.StringValue(b.Name), &b.Stack)
+		}
+	}
+	for region, functions := range inv.Lambda {
+		for _, f := range functions {
+			add("lambda", region, aws.StringValue(f.FunctionName), &f.Stack, aws.StringValue(f.FunctionArn))
+		}
+	}
+	for region, network := range inv.Network {
+		if network == nil {
+			continue
+		}
+		for _, v := range network.Vpcs {
+			add("vpc", region, aws.StringValue(v.VpcId), nil)
+		}
+		for _, s := range network.Subnets {
+			add("subnet", region, aws.StringValue(s.SubnetId), nil)
+		}
+	}
+	for region, groups := range inv.SecurityGroups {
+		for _, sg := range groups {
+			// Default groups are created along with their VPC
+			if aws.StringValue(sg.GroupName) == "default" {
+				continue
+			}
+			add("securitygroup", region, aws.StringValue(sg.GroupId), &sg.Stack)
+		}
+	}
+	for region, ebs := range inv.EBS {
+		if ebs == nil {
+			continue
+		}
+		for _, v := range ebs.Volumes {
+			add("ebs", region, aws.StringValue(v.VolumeId), nil)
+		}
+	}
+	for region, clusters := range inv.EKS {
+		for _, c := range clusters {
+			add("eks", region, aws.StringValue(c.Name), &c.Stack, aws.StringValue(c.Arn))
+		}
+	}
+	for region, clusters := range inv.ECS {
+		for _, c := range clusters {
+			add("ecs", region, aws.StringValue(c.ClusterName), &c.Stack, aws.StringValue(c.ClusterArn))
+		}
+	}
+	for region, as := range inv.AutoScaling {
+		if as == nil {
+			continue
+		}
+		for _, g := range as.Groups {
+			add("autoscaling", region, aws.StringValue(g.AutoScalingGroupName), nil, aws.StringValue(g.AutoScalingGroupARN))
+		}
+	}
+	for region, tables := range inv.DynamoDB {
+		for _, t := range tables {
+			add("dynamodb", region, aws.StringValue(t.TableName), nil, aws.StringValue(t.TableArn))
+		}
+	}
+	for region, ec := range inv.ElastiCache {
+		if ec == nil {
+			continue
+		}
+		for _, g := range ec.ReplicationGroups {
+			add("elasticache", region, aws.StringValue(g.ReplicationGroupId), nil, aws.StringValue(g.ARN))
+		}
+		for _, c := range ec.CacheClusters {
+			// Members of a replication group are managed through the group
+			if aws.StringValue(c.ReplicationGroupId) != "" {
+				continue
+			}
+			add("elasticache", region, aws.StringValue(c.CacheClusterId), nil, aws.StringValue(c.ARN))
+		}
+	}
+	for region, domains := range inv.OpenSearch {
+		for _, d := range domains {
+			add("opensearch", region, aws.StringValue(d.DomainName), nil, aws.StringValue(d.ARN))
+		}
+	}
+	for region, queues := range inv.SQS {
+		for _, q := range queues {
+			add("sqs", region, q.URL, &q.Stack, aws.StringValue(q.Attributes["QueueArn"]))
+		}
+	}
+	for region, topics := range inv.SNS {
+		for _, t := range topics {
+			add("sns", region, t.TopicArn, &t.Stack)
+		}
+	}
+	for region, streams := range inv.Kinesis {
+		for _, s := range streams {
+			add("kinesis", region, aws.StringValue(s.StreamName), nil, aws.StringValue(s.StreamARN))
+		}
+	}
+	for region, buses := range inv.EventBridge {
+		for _, b := range buses {
+			if aws.StringValue(b.Name) == "default" {
+				continue
+			}
+			add("eventbridge", region, aws.StringValue(b.Name), &b.Stack, aws.StringValue(b.Arn))
+		}
+	}
+	for region, keys := range inv.KMS {
+		for _, k := range keys {
+			if k.KeyMetadata == nil {
+				continue
+			}
+			add("kms", region, aws.StringValue(k.KeyId), &k.Stack, aws.StringValue(k.Arn))
+		}
+	}
+	for region, secrets := range inv.Secrets {
+		for _, s := range secrets {
+			add("secretsmanager", region, aws.StringValue(s.Name), nil, aws.StringValue(s.ARN))
+		}
+	}
+	for region, gateway := range inv.APIGateway {
+		if gateway == nil {
+			continue
+		}
+		for _, api := range gateway.RestAPIs {
+			add("apigateway", region, aws.StringValue(api.Id), &api.Stack)
+		}
+	}
+	for region, gateway := range inv.APIGatewayV2 {
+		if gateway == nil {
+			continue
+		}
+		for _, api := range gateway.APIs {
+			add("apigateway", region, aws.StringValue(api.ApiId), &api.Stack)
+		}
+	}
+	for _, d := range inv.CloudFront {
+		add("cloudfront", "", aws.StringValue(d.Id), nil, aws.StringValue(d.ARN))
+	}
+	if inv.IAM != nil {
+		for _, u := range inv.IAM.Users {
+			add("iam", "", aws.StringValue(u.UserName), nil, aws.StringValue(u.Arn))
+		}
+		for _, r := range inv.IAM.Roles {
+			// Service-linked roles are created by AWS services
+			if strings.HasPrefix(aws.StringValue(r.Path), "/aws-service-role/") {
+				continue
+			}
+			add("iam", "", aws.StringValue(r.RoleName), nil, aws.StringValue(r.Arn))
+		}
+		for _, p := range inv.IAM.Policies {
+			add("iam", "", aws.StringValue(p.PolicyName), nil, aws.StringValue(p.Arn))
+		}
+	}
+	return resources
+}
+
+// AnnotateStacks sets the Stack of the resources of an inventory dumped with cloudformation which awslib wraps to
+// the stack owning them, or Unmanaged. The DynamoDB tables, ElastiCache clusters, OpenSearch domains, Kinesis streams,
+// secrets, EBS volumes, VPCs, subnets, Auto Scaling groups, CloudFront distributions and IAM entities are SDK types
+// without a Stack, and their owner is only listed by the ownership report
+func AnnotateStacks(inv AWSInventory) {
+	if inv.CloudFormation == nil {
+		return
+	}
+	o := NewStackOwnership(inv)
+	for _, r := range OwnableResources(inv) {
+		if r.Stack == nil {
+			continue
+		}
+		*r.Stack = Unmanaged
+		if sr, ok := o.Find(r.Service, r.Region, append([]string{r.ID}, r.PhysicalIDs...)); ok {
+			*r.Stack = aws.StringValue(sr.Stack.StackName)
+		}
+	}
+}
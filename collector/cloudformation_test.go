@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testStackInventory = `{
+	"cloudformation": {"us-east-1": [
+		{"StackName": "web", "Resources": [
+			{"PhysicalResourceId": "web-asg", "ResourceStatus": "CREATE_COMPLETE"},
+			{"PhysicalResourceId": "arn:aws:sqs:us-east-1:123456789012:jobs", "ResourceStatus": "CREATE_COMPLETE"},
+			{"PhysicalResourceId": "i-retained", "ResourceStatus": "DELETE_SKIPPED"}
+		]}
+	]},
+	"ec2": {"us-east-1": [{"InstanceId": "i-web", "AutoScalingGroupName": "web-asg"}, {"InstanceId": "i-retained"}]},
+	"rds": {"eu-west-1": [{"DBInstanceIdentifier": "orders"}]},
+	"sqs": {"us-east-1": [{"URL": "https://sqs.us-east-1.amazonaws.com/123456789012/jobs", "Attributes": {"QueueArn": "arn:aws:sqs:us-east-1:123456789012:jobs"}}]}
+}`
+
+// TestAnnotateStacks checks that wrapped resources get the stack owning them, or Unmanaged
+func TestAnnotateStacks(t *testing.T) {
+	var inv AWSInventory
+	if err := json.Unmarshal([]byte(testStackInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	AnnotateStacks(inv)
+	if s := inv.EC2["us-east-1"][0].Stack; s != "web" {
+		t.Errorf("Expected i-web to be annotated with web through its group, got %q", s)
+	}
+	if s := inv.EC2["us-east-1"][1].Stack; s != Unmanaged {
+		t.Errorf("Expected the retained i-retained to be unmanaged, got %q", s)
+	}
+	if s := inv.RDS["eu-west-1"][0].Stack; s != Unmanaged {
+		t.Errorf("Expected orders to be unmanaged, got %q", s)
+	}
+	if s := inv.SQS["us-east-1"][0].Stack; s != "web" {
+		t.Errorf("Expected the jobs queue to be annotated with web, got %q", s)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+)
+
+// CollectCloudFront returns the CloudFront distributions of the account. CloudFront being global, a single session is used
+func (col AWSCollector) CollectCloudFront() ([]*cloudfront.DistributionSummary, error) {
+	var cfSession *session.Session
+	for _, session := range col.sessions {
+		cfSession = session
+	}
+	distributions, err := awslib.GetAllDistributions(cfSession)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather CloudFront Data: %v", err)
+	}
+	return distributions, nil
+}
+
+// CollectACM returns a concurrently collected ACM certificate inventory for all the regions.
+// Certificates used by CloudFront live in us-east-1
+func (col AWSCollector) CollectACM() (map[string][]*acm.CertificateDetail, error) {
+	certificates := make(map[string][]*acm.CertificateDetail)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectACMPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no certificates
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		certificates[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather ACM Data: %v", err)
+	}
+	return certificates, nil
+}
+
+// CollectACMPerSession returns an ACM certificate inventory for a given session
+func CollectACMPerSession(sess *session.Session) ([]*acm.CertificateDetail, error) {
+	certificates, err := awslib.GetAllCertificates(sess)
+	return certificates, err
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectEKS returns a concurrently collected EKS cluster inventory for all the regions
+func (col AWSCollector) CollectEKS() (map[string][]*awslib.EKSCluster, error) {
+	clusters := make(map[string][]*awslib.EKSCluster)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectEKSPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no clusters
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		clusters[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather EKS Data: %v", err)
+	}
+	return clusters, nil
+}
+
+// CollectEKSPerSession returns an EKS cluster inventory for a given session
+func CollectEKSPerSession(sess *session.Session) ([]*awslib.EKSCluster, error) {
+	clusters, err := awslib.GetAllEKSClusters(sess)
+	return clusters, err
+}
+
+// CollectECS returns a concurrently collected ECS cluster inventory for all the regions
+func (col AWSCollector) CollectECS() (map[string][]*awslib.ECSCluster, error) {
+	clusters := make(map[string][]*awslib.ECSCluster)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectECSPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no clusters
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		clusters[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather ECS Data: %v", err)
+	}
+	return clusters, nil
+}
+
+// CollectECSPerSession returns an ECS cluster inventory for a given session
+func CollectECSPerSession(sess *session.Session) ([]*awslib.ECSCluster, error) {
+	clusters, err := awslib.GetAllECSClusters(sess)
+	return clusters, err
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/opensearchservice"
+)
+
+// CollectDynamoDB returns a concurrently collected DynamoDB table inventory for all the regions
+func (col AWSCollector) CollectDynamoDB() (map[string][]*dynamodb.TableDescription, error) {
+	tables := make(map[string][]*dynamodb.TableDescription)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectDynamoDBPerSession(sess)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		tables[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather DynamoDB Data: %v", err)
+	}
+	return tables, nil
+}
+
+// CollectDynamoDBPerSession returns a DynamoDB table inventory for a given session
+func CollectDynamoDBPerSession(sess *session.Session) ([]*dynamodb.TableDescription, error) {
+	tables, err := awslib.GetAllTables(sess)
+	return tables, err
+}
+
+// CollectElastiCache returns a concurrently collected ElastiCache cluster and replication group inventory for all the regions
+func (col AWSCollector) CollectElastiCache() (map[string]*awslib.ElastiCache, error) {
+	caches := make(map[string]*awslib.ElastiCache)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectElastiCachePerSession(sess)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		caches[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather ElastiCache Data: %v", err)
+	}
+	return caches, nil
+}
+
+// CollectElastiCachePerSession returns an ElastiCache cluster and replication group inventory for a given session
+func CollectElastiCachePerSession(sess *session.Session) (*awslib.ElastiCache, error) {
+	caches, err := awslib.GetElastiCache(sess)
+	return caches, err
+}
+
+// CollectOpenSearch returns a concurrently collected OpenSearch domain inventory for all the regions
+func (col AWSCollector) CollectOpenSearch() (map[string][]*opensearchservice.DomainStatus, error) {
+	domains := make(map[string][]*opensearchservice.DomainStatus)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectOpenSearchPerSession(sess)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		domains[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather OpenSearch Data: %v", err)
+	}
+	return domains, nil
+}
+
+// CollectOpenSearchPerSession returns an OpenSearch domain inventory for a given session
+func CollectOpenSearchPerSession(sess *session.Session) ([]*opensearchservice.DomainStatus, error) {
+	domains, err := awslib.GetAllDomains(sess)
+	return domains, err
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/adobe/cloudinventory/digitaloceanlib"
+)
+
+// NewDigitalOceanCollector returns a DigitalOceanCollector authenticated with the given token, or with DIGITALOCEAN_TOKEN if empty
+func NewDigitalOceanCollector(token string) (DigitalOceanCollector, error) {
+	client, err := digitaloceanlib.NewClient(token)
+	if err != nil {
+		return DigitalOceanCollector{}, fmt.Errorf("Error obtaining DigitalOcean Credentials: %v", err)
+	}
+	return NewDigitalOceanCollectorWithClient(client), nil
+}
+
+// NewDigitalOceanCollectorWithClient returns a DigitalOceanCollector using the given client
+func NewDigitalOceanCollectorWithClient(client *digitaloceanlib.Client) DigitalOceanCollector {
+	return DigitalOceanCollector{client: client}
+}
+
+// DigitalOceanCollector is an inventory collection struct for DigitalOcean.
+// The API lists the resources of every region at once, which are then keyed by region
+type DigitalOceanCollector struct {
+	client *digitaloceanlib.Client
+}
+
+// CollectDroplets returns the droplet inventory keyed by region
+func (col DigitalOceanCollector) CollectDroplets() (map[string][]*digitaloceanlib.Droplet, error) {
+	droplets, err := digitaloceanlib.GetAllDroplets(col.client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Droplet Data: %v", err)
+	}
+	byRegion := make(map[string][]*digitaloceanlib.Droplet)
+	for _, d := range droplets {
+		byRegion[d.Region.Slug] = append(byRegion[d.Region.Slug], d)
+	}
+	return byRegion, nil
+}
+
+// CollectVolumes returns the block storage volume inventory keyed by region
+func (col DigitalOceanCollector) CollectVolumes() (map[string][]*digitaloceanlib.Volume, error) {
+	volumes, err := digitaloceanlib.GetAllVolumes(col.client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Volume Data: %v", err)
+	}
+	byRegion := make(map[string][]*digitaloceanlib.Volume)
+	for _, v := range volumes {
+		byRegion[v.Region.Slug] = append(byRegion[v.Region.Slug], v)
+	}
+	return byRegion, nil
+}
+
+// CollectLoadBalancers returns the load balancer inventory keyed by region
+func (col DigitalOceanCollector) CollectLoadBalancers() (map[string][]*digitaloceanlib.LoadBalancer, error) {
+	lbs, err := digitaloceanlib.GetAllLoadBalancers(col.client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Load Balancer Data: %v", err)
+	}
+	byRegion := make(map[string][]*digitaloceanlib.LoadBalancer)
+	for _, lb := range lbs {
+		byRegion[lb.Region.Slug] = append(byRegion[lb.Region.Slug], lb)
+	}
+	return byRegion, nil
+}
+
+// CollectDomains returns the domain inventory with the records of every domain. Domains are global and not keyed by region
+func (col DigitalOceanCollector) CollectDomains() ([]*digitaloceanlib.Domain, error) {
+	domains, err := digitaloceanlib.GetAllDomains(col.client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Domain Data: %v", err)
+	}
+	return domains, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/cloudinventory/digitaloceanlib"
+)
+
+func TestDigitalOceanCollector(t *testing.T) {
+	pages := map[string]string{
+		"/v2/droplets": `{"droplets": [{"id": 1, "name": "edge-ams-1", "region": {"slug": "ams3"}},
+			{"id": 2, "name": "edge-ams-2", "region": {"slug": "ams3"}}, {"id": 3, "name": "edge-nyc", "region": {"slug": "nyc1"}}], "links": {}}`,
+		"/v2/load_balancers": `{"load_balancers": [{"id": "lb1", "name": "edge", "ip": "203.0.113.1", "region": {"slug": "ams3"}, "droplet_ids": [1, 2]}], "links": {}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"id": "forbidden", "message": "You do not have access for the attempted action."}`)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	col := NewDigitalOceanCollectorWithClient(&digitaloceanlib.Client{Endpoint: server.URL, Token: "secret", HTTPClient: server.Client()})
+	droplets, err := col.CollectDroplets()
+	if err != nil {
+		t.Fatalf("Failed to collect droplets: %v", err)
+	}
+	if len(droplets["ams3"]) != 2 || len(droplets["nyc1"]) != 1 {
+		t.Errorf("Unexpected droplets %+v", droplets)
+	}
+
+	lbs, err := col.CollectLoadBalancers()
+	if err != nil {
+		t.Fatalf("Failed to collect load balancers: %v", err)
+	}
+	if len(lbs["ams3"]) != 1 || len(lbs["ams3"][0].DropletIDs) != 2 {
+		t.Errorf("Unexpected load balancers %+v", lbs)
+	}
+
+	if _, err := col.CollectDomains(); err == nil {
+		t.Errorf("Expected an error collecting domains")
+	}
+}
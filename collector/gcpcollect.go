@@ -0,0 +1,190 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/gcplib"
+)
+
+// NewGCPCollector returns a GCPCollector for the given projects, or for the project of the credentials if none.
+// Uses the environment credentials if creds is nil
+func NewGCPCollector(creds gcplib.TokenSource, projects []string) (GCPCollector, error) {
+	if creds == nil {
+		sa, err := gcplib.EnvironmentCredentials()
+		if err != nil {
+			return GCPCollector{}, fmt.Errorf("Error obtaining Google Cloud Credentials: %v", err)
+		}
+		creds = sa
+		if len(projects) == 0 && sa.ProjectID != "" {
+			projects = []string{sa.ProjectID}
+		}
+	}
+	return NewGCPCollectorWithClient(gcplib.NewClient(creds), projects)
+}
+
+// NewGCPCollectorWithClient returns a GCPCollector for the given projects using the given client
+func NewGCPCollectorWithClient(client *gcplib.Client, projects []string) (GCPCollector, error) {
+	if len(projects) == 0 {
+		return GCPCollector{}, fmt.Errorf("No Google Cloud Project given")
+	}
+	return GCPCollector{client: client, projects: projects}, nil
+}
+
+// GCPCollector is a concurrent inventory collection struct for Google Cloud.
+// Resources are collected concurrently per project and keyed by project and zone or region
+type GCPCollector struct {
+	client   *gcplib.Client
+	projects []string
+}
+
+// Projects returns the projects the collector gathers
+func (col GCPCollector) Projects() []string {
+	return col.projects
+}
+
+// forEachProject concurrently calls fn with every project and returns the first error encountered
+func (col GCPCollector) forEachProject(fn func(project string) error) error {
+	errChan := make(chan error, len(col.projects))
+	var wg sync.WaitGroup
+
+	for _, p := range col.projects {
+		wg.Add(1)
+		go func(project string) {
+			defer wg.Done()
+			if err := fn(project); err != nil {
+				errChan <- fmt.Errorf("Error while gathering %s: %v", project, err)
+			}
+		}(p)
+	}
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return <-errChan
+	}
+	return nil
+}
+
+// CollectInstances returns a concurrently collected Compute Engine instance inventory keyed by project and zone
+func (col GCPCollector) CollectInstances() (map[string]map[string][]*gcplib.Instance, error) {
+	instances := make(map[string]map[string][]*gcplib.Instance)
+	var mu sync.Mutex
+
+	err := col.forEachProject(func(project string) error {
+		chunk, err := gcplib.GetAllInstances(col.client, project)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		instances[project] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Compute Data: %v", err)
+	}
+	return instances, nil
+}
+
+// CollectSQLInstances returns a concurrently collected Cloud SQL instance inventory keyed by project and region
+func (col GCPCollector) CollectSQLInstances() (map[string]map[string][]*gcplib.SQLInstance, error) {
+	instances := make(map[string]map[string][]*gcplib.SQLInstance)
+	var mu sync.Mutex
+
+	err := col.forEachProject(func(project string) error {
+		chunk, err := gcplib.GetAllSQLInstances(col.client, project)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		instances[project] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Cloud SQL Data: %v", err)
+	}
+	return instances, nil
+}
+
+// CollectForwardingRules returns a concurrently collected forwarding rule inventory keyed by project and region,
+// global forwarding rules being under gcplib.Global
+func (col GCPCollector) CollectForwardingRules() (map[string]map[string][]*gcplib.ForwardingRule, error) {
+	rules := make(map[string]map[string][]*gcplib.ForwardingRule)
+	var mu sync.Mutex
+
+	err := col.forEachProject(func(project string) error {
+		chunk, err := gcplib.GetAllForwardingRules(col.client, project)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		rules[project] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Forwarding Rule Data: %v", err)
+	}
+	return rules, nil
+}
+
+// CollectBackendServices returns a concurrently collected backend service inventory keyed by project and region,
+// global backend services being under gcplib.Global
+func (col GCPCollector) CollectBackendServices() (map[string]map[string][]*gcplib.BackendService, error) {
+	services := make(map[string]map[string][]*gcplib.BackendService)
+	var mu sync.Mutex
+
+	err := col.forEachProject(func(project string) error {
+		chunk, err := gcplib.GetAllBackendServices(col.client, project)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		services[project] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Backend Service Data: %v", err)
+	}
+	return services, nil
+}
+
+// CollectManagedZones returns a concurrently collected Cloud DNS managed zone inventory keyed by project
+func (col GCPCollector) CollectManagedZones() (map[string][]*gcplib.ManagedZone, error) {
+	zones := make(map[string][]*gcplib.ManagedZone)
+	var mu sync.Mutex
+
+	err := col.forEachProject(func(project string) error {
+		chunk, err := gcplib.GetAllManagedZones(col.client, project)
+		if err != nil {
+			return err
+		}
+		// Ignore projects with no zones
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		zones[project] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Cloud DNS Data: %v", err)
+	}
+	return zones, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/cloudinventory/gcplib"
+)
+
+// gcpStandIn serves the shop and data projects
+var gcpStandIn = map[string]string{
+	"/compute/v1/projects/shop/aggregated/instances": `{"items": {
+		"zones/us-central1-a": {"instances": [{"id": "1", "name": "web"}]},
+		"zones/us-east1-b": {"warning": {"code": "NO_RESULTS_ON_PAGE"}}}}`,
+	"/compute/v1/projects/data/aggregated/instances": `{"items": {
+		"zones/us-east1-b": {"instances": [{"id": "2", "name": "etl"}, {"id": "3", "name": "spark"}]}}}`,
+	"/v1/projects/shop/instances": `{"items": [{"name": "orders", "region": "us-central1", "databaseVersion": "POSTGRES_15",
+		"settings": {"tier": "db-custom-2-7680", "ipConfiguration": {"ipv4Enabled": true}}}]}`,
+	"/v1/projects/data/instances":        `{}`,
+	"/dns/v1/projects/shop/managedZones": `{"managedZones": [{"id": "42", "name": "shop", "dnsName": "shop.example.com.", "visibility": "public"}]}`,
+	"/dns/v1/projects/shop/managedZones/shop/rrsets": `{"rrsets": [
+		{"name": "shop.example.com.", "type": "NS", "ttl": 21600, "rrdatas": ["ns-cloud-a1.googledomains.com."]},
+		{"name": "www.shop.example.com.", "type": "A", "ttl": 300, "rrdatas": ["34.1.2.3"]}]}`,
+	"/dns/v1/projects/data/managedZones": `{}`,
+}
+
+func TestGCPCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		page, ok := gcpStandIn[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": 404, "message": "not found", "status": "NOT_FOUND"}}`)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	if _, err := NewGCPCollectorWithClient(&gcplib.Client{}, nil); err == nil {
+		t.Errorf("Expected an error without projects")
+	}
+	col, err := NewGCPCollectorWithClient(&gcplib.Client{Endpoint: server.URL, Credentials: staticToken("test"), HTTPClient: server.Client()},
+		[]string{"shop", "data"})
+	if err != nil {
+		t.Fatalf("Failed to create GCP collector: %v", err)
+	}
+
+	instances, err := col.CollectInstances()
+	if err != nil {
+		t.Fatalf("Failed to collect instances: %v", err)
+	}
+	if len(instances["shop"]["us-central1-a"]) != 1 || len(instances["shop"]) != 1 || len(instances["data"]["us-east1-b"]) != 2 {
+		t.Errorf("Unexpected instances %+v", instances)
+	}
+
+	sql, err := col.CollectSQLInstances()
+	if err != nil {
+		t.Fatalf("Failed to collect Cloud SQL instances: %v", err)
+	}
+	if len(sql["shop"]["us-central1"]) != 1 || sql["shop"]["us-central1"][0].Settings.Tier != "db-custom-2-7680" || len(sql["data"]) != 0 {
+		t.Errorf("Unexpected Cloud SQL instances %+v", sql)
+	}
+
+	zones, err := col.CollectManagedZones()
+	if err != nil {
+		t.Fatalf("Failed to collect managed zones: %v", err)
+	}
+	if len(zones) != 1 || len(zones["shop"]) != 1 || len(zones["shop"][0].Records) != 2 || zones["shop"][0].Records[1].Rrdatas[0] != "34.1.2.3" {
+		t.Errorf("Unexpected managed zones %+v", zones)
+	}
+
+	// Forwarding rules are not served by the stand-in
+	if _, err := col.CollectForwardingRules(); err == nil {
+		t.Errorf("Expected an error collecting forwarding rules")
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectIAM returns the IAM inventory of the account. IAM being global, a single session is used
+func (col AWSCollector) CollectIAM() (*awslib.IAM, error) {
+	var iamSession *session.Session
+	for _, session := range col.sessions {
+		iamSession = session
+	}
+	inventory, err := awslib.GetIAM(iamSession)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather IAM Data: %v", err)
+	}
+	return inventory, nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/azurelib"
+	"github.com/adobe/cloudinventory/digitaloceanlib"
+	"github.com/adobe/cloudinventory/gcplib"
+	"github.com/adobe/cloudinventory/k8slib"
+	"github.com/adobe/cloudinventory/openstacklib"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/opensearchservice"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// AWSInventory is the inventory dumped by the CLI, each service being keyed by region unless global
+type AWSInventory struct {
+	Account        string                                         `json:"account,omitempty"`
+	EC2            map[string][]*awslib.Instance                  `json:"ec2,omitempty"`
+	RDS            map[string][]*awslib.DBInstance                `json:"rds,omitempty"`
+	RDSTopology    map[string]*awslib.RDSTopology                 `json:"rdstopology,omitempty"`
+	HostedZones    []*awslib.HostedZone                           `json:"hostedzones,omitempty"`
+	LoadBalancers  *LoadBalancers                                 `json:"loadbalancer,omitempty"`
+	S3             map[string][]*awslib.Bucket                    `json:"s3,omitempty"`
+	Lambda         map[string][]*awslib.Function                  `json:"lambda,omitempty"`
+	Network        map[string]*awslib.Network                     `json:"network,omitempty"`
+	SecurityGroups map[string][]*awslib.SecurityGroup             `json:"securitygroup,omitempty"`
+	EBS            map[string]*awslib.EBS                         `json:"ebs,omitempty"`
+	EKS            map[string][]*awslib.EKSCluster                `json:"eks,omitempty"`
+	ECS            map[string][]*awslib.ECSCluster                `json:"ecs,omitempty"`
+	AutoScaling    map[string]*awslib.AutoScaling                 `json:"autoscaling,omitempty"`
+	DynamoDB       map[string][]*dynamodb.TableDescription        `json:"dynamodb,omitempty"`
+	ElastiCache    map[string]*awslib.ElastiCache                 `json:"elasticache,omitempty"`
+	OpenSearch     map[string][]*opensearchservice.DomainStatus   `json:"opensearch,omitempty"`
+	IAM            *awslib.IAM                                    `json:"iam,omitempty"`
+	CloudFront     []*cloudfront.DistributionSummary              `json:"cloudfront,omitempty"`
+	SQS            map[string][]*awslib.Queue                     `json:"sqs,omitempty"`
+	SNS            map[string][]*awslib.Topic                     `json:"sns,omitempty"`
+	Kinesis        map[string][]*kinesis.StreamDescriptionSummary `json:"kinesis,omitempty"`
+	EventBridge    map[string][]*awslib.EventBus                  `json:"eventbridge,omitempty"`
+	ACM            map[string][]*acm.CertificateDetail            `json:"acm,omitempty"`
+	KMS            map[string][]*awslib.Key                       `json:"kms,omitempty"`
+	Secrets        map[string][]*secretsmanager.SecretListEntry   `json:"secretsmanager,omitempty"`
+	APIGateway     map[string]*awslib.APIGateway                  `json:"apigateway,omitempty"`
+	APIGatewayV2   map[string]*awslib.APIGatewayV2                `json:"apigatewayv2,omitempty"`
+	CloudFormation map[string][]*awslib.Stack                     `json:"cloudformation,omitempty"`
+}
+
+// AzureInventory is the Azure inventory dumped by the CLI, each service being keyed by subscription ID and region
+type AzureInventory struct {
+	Subscriptions   []*azurelib.Subscription                    `json:"subscriptions,omitempty"`
+	VirtualMachines map[string]map[string][]*azurelib.Resource  `json:"vm,omitempty"`
+	Disks           map[string]map[string][]*azurelib.Resource  `json:"disk,omitempty"`
+	SQL             map[string]map[string][]*azurelib.SQLServer `json:"sql,omitempty"`
+	LoadBalancers   map[string]map[string][]*azurelib.Resource  `json:"loadbalancer,omitempty"`
+	PublicIPs       map[string]map[string][]*azurelib.Resource  `json:"publicip,omitempty"`
+	DNSZones        map[string]map[string][]*azurelib.Resource  `json:"dns,omitempty"`
+}
+
+// GCPInventory is the Google Cloud inventory dumped by the CLI, each service being keyed by project and then
+// zone or region unless global
+type GCPInventory struct {
+	Projects        []string                                       `json:"projects,omitempty"`
+	Instances       map[string]map[string][]*gcplib.Instance       `json:"compute,omitempty"`
+	SQL             map[string]map[string][]*gcplib.SQLInstance    `json:"sql,omitempty"`
+	ForwardingRules map[string]map[string][]*gcplib.ForwardingRule `json:"forwardingrule,omitempty"`
+	BackendServices map[string]map[string][]*gcplib.BackendService `json:"backendservice,omitempty"`
+	DNS             map[string][]*gcplib.ManagedZone               `json:"dns,omitempty"`
+}
+
+// K8sInventory is the Kubernetes inventory dumped by the CLI, each object kind being keyed by kubeconfig context
+type K8sInventory struct {
+	Contexts     []string                       `json:"contexts,omitempty"`
+	Nodes        map[string][]*k8slib.Node      `json:"node,omitempty"`
+	Namespaces   map[string][]*k8slib.Namespace `json:"namespace,omitempty"`
+	Deployments  map[string][]*k8slib.Workload  `json:"deployment,omitempty"`
+	StatefulSets map[string][]*k8slib.Workload  `json:"statefulset,omitempty"`
+	Services     map[string][]*k8slib.Service   `json:"service,omitempty"`
+	Ingresses    map[string][]*k8slib.Ingress   `json:"ingress,omitempty"`
+}
+
+// OpenStackInventory is the OpenStack inventory dumped by the CLI, each service being keyed by region
+type OpenStackInventory struct {
+	Cloud         string                                  `json:"cloud,omitempty"`
+	Servers       map[string][]*openstacklib.Server       `json:"server,omitempty"`
+	Volumes       map[string][]*openstacklib.Volume       `json:"volume,omitempty"`
+	FloatingIPs   map[string][]*openstacklib.FloatingIP   `json:"floatingip,omitempty"`
+	LoadBalancers map[string][]*openstacklib.LoadBalancer `json:"loadbalancer,omitempty"`
+	Zones         map[string][]*openstacklib.Zone         `json:"dns,omitempty"`
+}
+
+// DigitalOceanInventory is the DigitalOcean inventory dumped by the CLI, each service being keyed by region unless global
+type DigitalOceanInventory struct {
+	Droplets      map[string][]*digitaloceanlib.Droplet      `json:"droplet,omitempty"`
+	Volumes       map[string][]*digitaloceanlib.Volume       `json:"volume,omitempty"`
+	LoadBalancers map[string][]*digitaloceanlib.LoadBalancer `json:"loadbalancer,omitempty"`
+	Domains       []*digitaloceanlib.Domain                  `json:"domain,omitempty"`
+}
+
+// MultiCloudInventory is the inventory of every cloud dumped at once by the CLI
+type MultiCloudInventory struct {
+	AWS          *AWSInventory          `json:"aws,omitempty"`
+	Azure        *AzureInventory        `json:"azure,omitempty"`
+	GCP          *GCPInventory          `json:"gcp,omitempty"`
+	K8s          *K8sInventory          `json:"k8s,omitempty"`
+	OpenStack    *OpenStackInventory    `json:"openstack,omitempty"`
+	DigitalOcean *DigitalOceanInventory `json:"digitalocean,omitempty"`
+}
+
+// LoadBalancers holds both load balancer generations.
+// It is dumped as a [classic, application and network] JSON array
+type LoadBalancers struct {
+	Classic            map[string][]*awslib.ClassicLoadBalancer
+	ApplicationNetwork map[string][]*awslib.ApplicationNetworkLoadBalancer
+}
+
+// MarshalJSON encodes the load balancers as a [classic, application and network] array
+func (lbs LoadBalancers) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{lbs.Classic, lbs.ApplicationNetwork})
+}
+
+// UnmarshalJSON decodes a [classic, application and network] array
+func (lbs *LoadBalancers) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("Expected classic and application/network load balancers, found %d entries", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &lbs.Classic); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[1], &lbs.ApplicationNetwork)
+}
+
+// LoadAWSInventory reads an inventory previously dumped by the CLI, with either dump aws or dump all.
+// A dump all file without AWS inventory is an error
+func LoadAWSInventory(path string) (AWSInventory, error) {
+	var inv AWSInventory
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return inv, err
+	}
+	// dump all nests the AWS inventory under the aws key of a MultiCloudInventory
+	var multi struct {
+		AWS          json.RawMessage `json:"aws"`
+		Azure        json.RawMessage `json:"azure"`
+		GCP          json.RawMessage `json:"gcp"`
+		K8s          json.RawMessage `json:"k8s"`
+		OpenStack    json.RawMessage `json:"openstack"`
+		DigitalOcean json.RawMessage `json:"digitalocean"`
+	}
+	if json.Unmarshal(data, &multi) == nil {
+		if len(multi.AWS) > 0 {
+			data = multi.AWS
+		} else if len(multi.Azure) > 0 || len(multi.GCP) > 0 || len(multi.K8s) > 0 || len(multi.OpenStack) > 0 || len(multi.DigitalOcean) > 0 {
+			return inv, fmt.Errorf("Invalid inventory %s: no AWS inventory in the dump", path)
+		}
+	}
+	err = json.Unmarshal(data, &inv)
+	if err != nil {
+		return inv, fmt.Errorf("Invalid inventory %s: %v", path, err)
+	}
+	return inv, nil
+}
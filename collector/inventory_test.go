@@ -0,0 +1,101 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// TestLoadAWSInventory checks that a dumped inventory can be read back
+func TestLoadAWSInventory(t *testing.T) {
+	inv := AWSInventory{
+		Account: "123456789012",
+		LoadBalancers: &LoadBalancers{
+			Classic: map[string][]*awslib.ClassicLoadBalancer{"us-east-1": {
+				{LoadBalancerDescription: &elb.LoadBalancerDescription{LoadBalancerName: aws.String("clb")}},
+			}},
+			ApplicationNetwork: map[string][]*awslib.ApplicationNetworkLoadBalancer{"eu-west-1": {
+				{LoadBalancer: &elbv2.LoadBalancer{LoadBalancerName: aws.String("alb")}},
+			}},
+		},
+	}
+	data, err := json.Marshal(inv)
+	if err != nil {
+		t.Fatalf("Failed to marshal inventory: %v", err)
+	}
+	f, err := ioutil.TempFile("", "inventory")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	f.Write(data)
+	f.Close()
+
+	loaded, err := LoadAWSInventory(f.Name())
+	if err != nil {
+		t.Fatalf("Failed to load inventory: %v", err)
+	}
+	if loaded.Account != inv.Account || loaded.EC2 != nil {
+		t.Errorf("Unexpected inventory: %+v", loaded)
+	}
+	if aws.StringValue(loaded.LoadBalancers.Classic["us-east-1"][0].LoadBalancerName) != "clb" ||
+		aws.StringValue(loaded.LoadBalancers.ApplicationNetwork["eu-west-1"][0].LoadBalancerName) != "alb" {
+		t.Errorf("Unexpected load balancers: %s", data)
+	}
+}
+
+// TestLoadMultiCloudInventory checks that the AWS inventory of a dump all file is read back
+func TestLoadMultiCloudInventory(t *testing.T) {
+	multi := MultiCloudInventory{
+		AWS: &AWSInventory{Account: "123456789012", RDS: map[string][]*awslib.DBInstance{"us-east-1": {
+			{DBInstance: &rds.DBInstance{DBInstanceIdentifier: aws.String("orders")}},
+		}}},
+		Azure: &AzureInventory{},
+	}
+	data, err := json.Marshal(multi)
+	if err != nil {
+		t.Fatalf("Failed to marshal inventory: %v", err)
+	}
+	f, err := ioutil.TempFile("", "inventory")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	f.Write(data)
+	f.Close()
+
+	loaded, err := LoadAWSInventory(f.Name())
+	if err != nil {
+		t.Fatalf("Failed to load inventory: %v", err)
+	}
+	if loaded.Account != "123456789012" || aws.StringValue(loaded.RDS["us-east-1"][0].DBInstanceIdentifier) != "orders" {
+		t.Errorf("Unexpected inventory from %s: %+v", data, loaded)
+	}
+
+	// A dump all file whose AWS dump was skipped has no AWS inventory to read
+	if err := ioutil.WriteFile(f.Name(), []byte(`{"azure": {}}`), 0644); err != nil {
+		t.Fatalf("Failed to write inventory: %v", err)
+	}
+	if _, err := LoadAWSInventory(f.Name()); err == nil {
+		t.Errorf("Expected an error for a dump all file without AWS inventory")
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/adobe/cloudinventory/k8slib"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// NewK8sCollector returns a K8sCollector for the given contexts of the kubeconfig files, or for their current context
+// if none. Uses the KUBECONFIG files, or ~/.kube/config, if no file is given
+func NewK8sCollector(kubeconfigs []string, contexts []string) (K8sCollector, error) {
+	if len(kubeconfigs) == 0 {
+		kubeconfigs = k8slib.DefaultKubeconfig()
+	}
+	kc, err := k8slib.LoadKubeconfig(kubeconfigs...)
+	if err != nil {
+		return K8sCollector{}, fmt.Errorf("Error loading kubeconfig: %v", err)
+	}
+	if len(contexts) == 0 {
+		if kc.CurrentContext == "" {
+			return K8sCollector{}, fmt.Errorf("No context given and no current context in kubeconfig")
+		}
+		contexts = []string{kc.CurrentContext}
+	}
+	clients := make(map[string]*k8slib.Client)
+	for _, context := range contexts {
+		clients[context], err = kc.Client(context)
+		if err != nil {
+			return K8sCollector{}, err
+		}
+	}
+	return NewK8sCollectorWithClients(clients), nil
+}
+
+// NewK8sCollectorWithClients returns a K8sCollector using the given clients keyed by context
+func NewK8sCollectorWithClients(clients map[string]*k8slib.Client) K8sCollector {
+	return K8sCollector{clients: clients}
+}
+
+// K8sCollector is a concurrent inventory collection struct for Kubernetes.
+// Objects are collected concurrently per kubeconfig context and keyed by context
+type K8sCollector struct {
+	clients map[string]*k8slib.Client
+}
+
+// Contexts returns the kubeconfig contexts the collector gathers
+func (col K8sCollector) Contexts() []string {
+	var contexts []string
+	for context := range col.clients {
+		contexts = append(contexts, context)
+	}
+	sort.Strings(contexts)
+	return contexts
+}
+
+// forEachContext concurrently calls fn with every context and its client and returns the first error encountered
+func (col K8sCollector) forEachContext(fn func(context string, c *k8slib.Client) error) error {
+	errChan := make(chan error, len(col.clients))
+	var wg sync.WaitGroup
+
+	for context, c := range col.clients {
+		wg.Add(1)
+		go func(context string, c *k8slib.Client) {
+			defer wg.Done()
+			if err := fn(context, c); err != nil {
+				errChan <- fmt.Errorf("Error while gathering %s: %v", context, err)
+			}
+		}(context, c)
+	}
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return <-errChan
+	}
+	return nil
+}
+
+// CollectNodes returns a concurrently collected node inventory keyed by context
+func (col K8sCollector) CollectNodes() (map[string][]*k8slib.Node, error) {
+	nodes := make(map[string][]*k8slib.Node)
+	var mu sync.Mutex
+
+	err := col.forEachContext(func(context string, c *k8slib.Client) error {
+		chunk, err := k8slib.GetAllNodes(c)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		nodes[context] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Node Data: %v", err)
+	}
+	return nodes, nil
+}
+
+// CollectNamespaces returns a concurrently collected namespace inventory keyed by context
+func (col K8sCollector) CollectNamespaces() (map[string][]*k8slib.Namespace, error) {
+	namespaces := make(map[string][]*k8slib.Namespace)
+	var mu sync.Mutex
+
+	err := col.forEachContext(func(context string, c *k8slib.Client) error {
+		chunk, err := k8slib.GetAllNamespaces(c)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		namespaces[context] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Namespace Data: %v", err)
+	}
+	return namespaces, nil
+}
+
+// CollectDeployments returns a concurrently collected Deployment inventory keyed by context
+func (col K8sCollector) CollectDeployments() (map[string][]*k8slib.Workload, error) {
+	deployments := make(map[string][]*k8slib.Workload)
+	var mu sync.Mutex
+
+	err := col.forEachContext(func(context string, c *k8slib.Client) error {
+		chunk, err := k8slib.GetAllDeployments(c)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		deployments[context] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Deployment Data: %v", err)
+	}
+	return deployments, nil
+}
+
+// CollectStatefulSets returns a concurrently collected StatefulSet inventory keyed by context
+func (col K8sCollector) CollectStatefulSets() (map[string][]*k8slib.Workload, error) {
+	statefulSets := make(map[string][]*k8slib.Workload)
+	var mu sync.Mutex
+
+	err := col.forEachContext(func(context string, c *k8slib.Client) error {
+		chunk, err := k8slib.GetAllStatefulSets(c)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		statefulSets[context] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather StatefulSet Data: %v", err)
+	}
+	return statefulSets, nil
+}
+
+// CollectLoadBalancerServices returns a concurrently collected inventory of the Services of type LoadBalancer keyed by context
+func (col K8sCollector) CollectLoadBalancerServices() (map[string][]*k8slib.Service, error) {
+	services := make(map[string][]*k8slib.Service)
+	var mu sync.Mutex
+
+	err := col.forEachContext(func(context string, c *k8slib.Client) error {
+		chunk, err := k8slib.GetAllLoadBalancerServices(c)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		services[context] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Service Data: %v", err)
+	}
+	return services, nil
+}
+
+// CollectIngresses returns a concurrently collected Ingress inventory keyed by context
+func (col K8sCollector) CollectIngresses() (map[string][]*k8slib.Ingress, error) {
+	ingresses := make(map[string][]*k8slib.Ingress)
+	var mu sync.Mutex
+
+	err := col.forEachContext(func(context string, c *k8slib.Client) error {
+		chunk, err := k8slib.GetAllIngresses(c)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		ingresses[context] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Ingress Data: %v", err)
+	}
+	return ingresses, nil
+}
+
+// LinkAWS links the nodes to the EC2 instances and the Services and Ingresses to the load balancers of an AWS inventory.
+// It returns the number of links made
+func LinkAWS(inv *K8sInventory, awsInv AWSInventory) int {
+	instances := make(map[string]*k8slib.AWSResource)
+	for region, ii := range awsInv.EC2 {
+		for _, i := range ii {
+			id := aws.StringValue(i.InstanceId)
+			instances[id] = &k8slib.AWSResource{Region: region, ID: id}
+		}
+	}
+	lbs := make(map[string]*k8slib.AWSResource)
+	if awsInv.LoadBalancers != nil {
+		for region, ll := range awsInv.LoadBalancers.Classic {
+			for _, lb := range ll {
+				lbs[strings.ToLower(aws.StringValue(lb.DNSName))] = &k8slib.AWSResource{Region: region, ID: aws.StringValue(lb.LoadBalancerName)}
+			}
+		}
+		for region, ll := range awsInv.LoadBalancers.ApplicationNetwork {
+			for _, lb := range ll {
+				lbs[strings.ToLower(aws.StringValue(lb.DNSName))] = &k8slib.AWSResource{Region: region, ID: aws.StringValue(lb.LoadBalancerName)}
+			}
+		}
+	}
+	findLB := func(status k8slib.LoadBalancerStatus) *k8slib.AWSResource {
+		for _, hostname := range status.Hostnames() {
+			if lb, ok := lbs[strings.TrimPrefix(strings.ToLower(hostname), "dualstack.")]; ok {
+				return lb
+			}
+		}
+		return nil
+	}
+
+	links := 0
+	for _, nodes := range inv.Nodes {
+		for _, n := range nodes {
+			if n.EC2 = instances[k8slib.InstanceID(n.Spec.ProviderID)]; n.EC2 != nil {
+				links++
+			}
+		}
+	}
+	for _, services := range inv.Services {
+		for _, s := range services {
+			if s.ELB = findLB(s.Status.LoadBalancer); s.ELB != nil {
+				links++
+			}
+		}
+	}
+	for _, ingresses := range inv.Ingresses {
+		for _, i := range ingresses {
+			if i.ELB = findLB(i.Status.LoadBalancer); i.ELB != nil {
+				links++
+			}
+		}
+	}
+	return links
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adobe/cloudinventory/k8slib"
+)
+
+// k8sAWSInventory holds the EC2 instance and load balancers backing the prod cluster
+const k8sAWSInventory = `{
+	"ec2": {"us-east-1": [{"InstanceId": "i-0123456789abcdef0"}]},
+	"loadbalancer": [
+		{"us-east-1": [{"LoadBalancerName": "a1b2c3", "DNSName": "a1b2c3-123.us-east-1.elb.amazonaws.com"}]},
+		{"us-east-1": [{"LoadBalancerName": "k8s-shop-web", "DNSName": "k8s-shop-web-456.us-east-1.elb.amazonaws.com"}]}
+	]
+}`
+
+func newFakeAPIServer(pages map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind": "Status", "reason": "NotFound", "message": "the server could not find the requested resource"}`)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+}
+
+func TestK8sCollector(t *testing.T) {
+	prod := newFakeAPIServer(map[string]string{
+		"/api/v1/nodes": `{"metadata": {}, "items": [
+			{"metadata": {"name": "ip-10-0-1-10.ec2.internal"}, "spec": {"providerID": "aws:///us-east-1a/i-0123456789abcdef0"}},
+			{"metadata": {"name": "ip-10-0-1-11.ec2.internal"}, "spec": {"providerID": "aws:///us-east-1b/i-0fedcba9876543210"}}]}`,
+		"/api/v1/services": `{"metadata": {}, "items": [
+			{"metadata": {"name": "web", "namespace": "shop"}, "spec": {"type": "LoadBalancer"},
+				"status": {"loadBalancer": {"ingress": [{"hostname": "a1b2c3-123.us-east-1.elb.amazonaws.com"}]}}}]}`,
+		"/apis/networking.k8s.io/v1/ingresses": `{"metadata": {}, "items": [
+			{"metadata": {"name": "web", "namespace": "shop"}, "spec": {"rules": [{"host": "shop.example.com"}]},
+				"status": {"loadBalancer": {"ingress": [{"hostname": "dualstack.K8S-shop-web-456.us-east-1.elb.amazonaws.com"}]}}}]}`,
+	})
+	defer prod.Close()
+	staging := newFakeAPIServer(map[string]string{
+		"/api/v1/nodes":    `{"metadata": {}, "items": [{"metadata": {"name": "kind-control-plane"}, "spec": {"providerID": "kind://docker/kind/kind-control-plane"}}]}`,
+		"/api/v1/services": `{"metadata": {}, "items": []}`,
+	})
+	defer staging.Close()
+
+	col := NewK8sCollectorWithClients(map[string]*k8slib.Client{
+		"prod":    {Server: prod.URL, HTTPClient: prod.Client()},
+		"staging": {Server: staging.URL, HTTPClient: staging.Client()},
+	})
+	if contexts := col.Contexts(); len(contexts) != 2 || contexts[0] != "prod" {
+		t.Errorf("Unexpected contexts %v", contexts)
+	}
+
+	var inv K8sInventory
+	var err error
+	inv.Nodes, err = col.CollectNodes()
+	if err != nil {
+		t.Fatalf("Failed to collect nodes: %v", err)
+	}
+	if len(inv.Nodes["prod"]) != 2 || len(inv.Nodes["staging"]) != 1 {
+		t.Errorf("Unexpected nodes %+v", inv.Nodes)
+	}
+	inv.Services, err = col.CollectLoadBalancerServices()
+	if err != nil {
+		t.Fatalf("Failed to collect services: %v", err)
+	}
+	if _, err := col.CollectIngresses(); err == nil {
+		t.Errorf("Expected an error collecting ingresses from staging")
+	}
+	inv.Ingresses, err = NewK8sCollectorWithClients(map[string]*k8slib.Client{"prod": {Server: prod.URL, HTTPClient: prod.Client()}}).CollectIngresses()
+	if err != nil {
+		t.Fatalf("Failed to collect ingresses: %v", err)
+	}
+
+	var awsInv AWSInventory
+	if err := json.Unmarshal([]byte(k8sAWSInventory), &awsInv); err != nil {
+		t.Fatalf("Invalid AWS inventory: %v", err)
+	}
+	if links := LinkAWS(&inv, awsInv); links != 3 {
+		t.Errorf("Expected 3 links, got %d", links)
+	}
+	if ec2 := inv.Nodes["prod"][0].EC2; ec2 == nil || ec2.Region != "us-east-1" || ec2.ID != "i-0123456789abcdef0" {
+		t.Errorf("Expected the node to be linked to its instance, got %+v", ec2)
+	}
+	if inv.Nodes["prod"][1].EC2 != nil || inv.Nodes["staging"][0].EC2 != nil {
+		t.Errorf("Expected nodes missing from the AWS inventory not to be linked")
+	}
+	if elb := inv.Services["prod"][0].ELB; elb == nil || elb.ID != "a1b2c3" {
+		t.Errorf("Expected the service to be linked to its classic load balancer, got %+v", elb)
+	}
+	if elb := inv.Ingresses["prod"][0].ELB; elb == nil || elb.ID != "k8s-shop-web" {
+		t.Errorf("Expected the ingress to be linked to its application load balancer, got %+v", elb)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// CollectKMS returns a concurrently collected customer managed KMS key inventory for all the regions
+func (col AWSCollector) CollectKMS() (map[string][]*awslib.Key, error) {
+	keys := make(map[string][]*awslib.Key)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectKMSPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no keys
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		keys[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather KMS Data: %v", err)
+	}
+	return keys, nil
+}
+
+// CollectKMSPerSession returns a customer managed KMS key inventory for a given session
+func CollectKMSPerSession(sess *session.Session) ([]*awslib.Key, error) {
+	keys, err := awslib.GetAllKeys(sess)
+	return keys, err
+}
+
+// CollectSecrets returns a concurrently collected Secrets Manager inventory for all the regions.
+// Only the metadata of the secrets is collected, never their value
+func (col AWSCollector) CollectSecrets() (map[string][]*secretsmanager.SecretListEntry, error) {
+	secrets := make(map[string][]*secretsmanager.SecretListEntry)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectSecretsPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no secrets
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		secrets[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Secrets Manager Data: %v", err)
+	}
+	return secrets, nil
+}
+
+// CollectSecretsPerSession returns a Secrets Manager inventory for a given session
+func CollectSecretsPerSession(sess *session.Session) ([]*secretsmanager.SecretListEntry, error) {
+	secrets, err := awslib.GetAllSecrets(sess)
+	return secrets, err
+}
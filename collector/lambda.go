@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectLambda returns a concurrently collected Lambda inventory for all the regions
+func (col AWSCollector) CollectLambda() (map[string][]*awslib.Function, error) {
+	functions := make(map[string][]*awslib.Function)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectLambdaPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no functions
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		functions[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Lambda Data: %v", err)
+	}
+	return functions, nil
+}
+
+// CollectLambdaPerSession returns a Lambda inventory for a given session
+func CollectLambdaPerSession(sess *session.Session) ([]*awslib.Function, error) {
+	functions, err := awslib.GetAllFunctions(sess)
+	return functions, err
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+)
+
+// CollectSQS returns a concurrently collected SQS queue inventory for all the regions
+func (col AWSCollector) CollectSQS() (map[string][]*awslib.Queue, error) {
+	queues := make(map[string][]*awslib.Queue)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectSQSPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no queues
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		queues[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather SQS Data: %v", err)
+	}
+	return queues, nil
+}
+
+// CollectSQSPerSession returns an SQS queue inventory for a given session
+func CollectSQSPerSession(sess *session.Session) ([]*awslib.Queue, error) {
+	queues, err := awslib.GetAllQueues(sess)
+	return queues, err
+}
+
+// CollectSNS returns a concurrently collected SNS topic inventory for all the regions
+func (col AWSCollector) CollectSNS() (map[string][]*awslib.Topic, error) {
+	topics := make(map[string][]*awslib.Topic)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectSNSPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no topics
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		topics[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather SNS Data: %v", err)
+	}
+	return topics, nil
+}
+
+// CollectSNSPerSession returns an SNS topic inventory for a given session
+func CollectSNSPerSession(sess *session.Session) ([]*awslib.Topic, error) {
+	topics, err := awslib.GetAllTopics(sess)
+	return topics, err
+}
+
+// CollectKinesis returns a concurrently collected Kinesis stream inventory for all the regions
+func (col AWSCollector) CollectKinesis() (map[string][]*kinesis.StreamDescriptionSummary, error) {
+	streams := make(map[string][]*kinesis.StreamDescriptionSummary)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectKinesisPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no streams
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		streams[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Kinesis Data: %v", err)
+	}
+	return streams, nil
+}
+
+// CollectKinesisPerSession returns a Kinesis stream inventory for a given session
+func CollectKinesisPerSession(sess *session.Session) ([]*kinesis.StreamDescriptionSummary, error) {
+	streams, err := awslib.GetAllStreams(sess)
+	return streams, err
+}
+
+// CollectEventBridge returns a concurrently collected EventBridge event bus inventory for all the regions
+func (col AWSCollector) CollectEventBridge() (map[string][]*awslib.EventBus, error) {
+	buses := make(map[string][]*awslib.EventBus)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectEventBridgePerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no buses
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		buses[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather EventBridge Data: %v", err)
+	}
+	return buses, nil
+}
+
+// CollectEventBridgePerSession returns an EventBridge event bus inventory for a given session
+func CollectEventBridgePerSession(sess *session.Session) ([]*awslib.EventBus, error) {
+	buses, err := awslib.GetAllEventBuses(sess)
+	return buses, err
+}
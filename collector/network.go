@@ -0,0 +1,48 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectNetwork returns a concurrently collected VPC networking inventory for all the regions
+func (col AWSCollector) CollectNetwork() (map[string]*awslib.Network, error) {
+	networks := make(map[string]*awslib.Network)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		network, err := CollectNetworkPerSession(sess)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		networks[region] = network
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Network Data: %v", err)
+	}
+	return networks, nil
+}
+
+// CollectNetworkPerSession returns a VPC networking inventory for a given session
+func CollectNetworkPerSession(sess *session.Session) (*awslib.Network, error) {
+	network, err := awslib.GetNetwork(sess)
+	return network, err
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/adobe/cloudinventory/openstacklib"
+)
+
+// NewOpenStackCollector returns an OpenStackCollector for the named cloud of clouds.yaml, or the cloud named by OS_CLOUD if empty
+func NewOpenStackCollector(cloud string) (OpenStackCollector, error) {
+	if cloud == "" {
+		cloud = os.Getenv("OS_CLOUD")
+	}
+	c, err := openstacklib.LoadCloud(cloud)
+	if err != nil {
+		return OpenStackCollector{}, fmt.Errorf("Error obtaining OpenStack Credentials: %v", err)
+	}
+	client, err := openstacklib.NewClient(c)
+	if err != nil {
+		return OpenStackCollector{}, err
+	}
+	col, err := NewOpenStackCollectorWithClient(client)
+	col.cloud = cloud
+	return col, err
+}
+
+// NewOpenStackCollectorWithClient returns an OpenStackCollector for the regions of an authenticated client
+func NewOpenStackCollectorWithClient(client *openstacklib.Client) (OpenStackCollector, error) {
+	regions := client.Regions()
+	if len(regions) == 0 {
+		return OpenStackCollector{}, fmt.Errorf("No OpenStack region offering compute found in the catalog")
+	}
+	return OpenStackCollector{client: client, regions: regions}, nil
+}
+
+// OpenStackCollector is a concurrent inventory collection struct for OpenStack.
+// Resources are collected concurrently per region and keyed by region
+type OpenStackCollector struct {
+	cloud   string
+	client  *openstacklib.Client
+	regions []string
+}
+
+// Cloud returns the name of the cloud in clouds.yaml, empty when created with a client
+func (col OpenStackCollector) Cloud() string {
+	return col.cloud
+}
+
+// Regions returns the regions the collector gathers
+func (col OpenStackCollector) Regions() []string {
+	return col.regions
+}
+
+// forEachRegion concurrently calls fn with every region and returns the first error encountered
+func (col OpenStackCollector) forEachRegion(fn func(region string) error) error {
+	errChan := make(chan error, len(col.regions))
+	var wg sync.WaitGroup
+
+	for _, r := range col.regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			if err := fn(region); err != nil {
+				errChan <- fmt.Errorf("Error while gathering %s: %v", region, err)
+			}
+		}(r)
+	}
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return <-errChan
+	}
+	return nil
+}
+
+// CollectServers returns a concurrently collected Nova server inventory for all the regions
+func (col OpenStackCollector) CollectServers() (map[string][]*openstacklib.Server, error) {
+	servers := make(map[string][]*openstacklib.Server)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string) error {
+		chunk, err := openstacklib.GetAllServers(col.client, region)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no Nova servers
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		servers[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Server Data: %v", err)
+	}
+	return servers, nil
+}
+
+// CollectVolumes returns a concurrently collected Cinder volume inventory for all the regions
+func (col OpenStackCollector) CollectVolumes() (map[string][]*openstacklib.Volume, error) {
+	volumes := make(map[string][]*openstacklib.Volume)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string) error {
+		chunk, err := openstacklib.GetAllVolumes(col.client, region)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no Cinder volumes
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		volumes[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Volume Data: %v", err)
+	}
+	return volumes, nil
+}
+
+// CollectFloatingIPs returns a concurrently collected Neutron floating IP inventory for all the regions
+func (col OpenStackCollector) CollectFloatingIPs() (map[string][]*openstacklib.FloatingIP, error) {
+	ips := make(map[string][]*openstacklib.FloatingIP)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string) error {
+		chunk, err := openstacklib.GetAllFloatingIPs(col.client, region)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no Neutron floating IPs
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		ips[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Floating IP Data: %v", err)
+	}
+	return ips, nil
+}
+
+// CollectLoadBalancers returns a concurrently collected Octavia load balancer inventory for all the regions
+func (col OpenStackCollector) CollectLoadBalancers() (map[string][]*openstacklib.LoadBalancer, error) {
+	lbs := make(map[string][]*openstacklib.LoadBalancer)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string) error {
+		chunk, err := openstacklib.GetAllLoadBalancers(col.client, region)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no Octavia load balancers
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		lbs[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Load Balancer Data: %v", err)
+	}
+	return lbs, nil
+}
+
+// CollectZones returns a concurrently collected Designate zone inventory for all the regions
+func (col OpenStackCollector) CollectZones() (map[string][]*openstacklib.Zone, error) {
+	zones := make(map[string][]*openstacklib.Zone)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string) error {
+		chunk, err := openstacklib.GetAllZones(col.client, region)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no Designate zones
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		zones[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather DNS Zone Data: %v", err)
+	}
+	return zones, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adobe/cloudinventory/openstacklib"
+)
+
+// openStackCatalog offers compute and block storage in RegionOne and RegionTwo, and load balancing in RegionOne only
+const openStackCatalog = `{"token": {"expires_at": "2099-01-01T00:00:00Z", "catalog": [
+	{"type": "compute", "endpoints": [
+		{"interface": "public", "region_id": "RegionOne", "url": "{server}/one/compute"},
+		{"interface": "public", "region_id": "RegionTwo", "url": "{server}/two/compute"}]},
+	{"type": "volumev3", "endpoints": [
+		{"interface": "public", "region_id": "RegionOne", "url": "{server}/one/volume"},
+		{"interface": "public", "region_id": "RegionTwo", "url": "{server}/two/volume"}]},
+	{"type": "load-balancer", "endpoints": [{"interface": "public", "region_id": "RegionOne", "url": "{server}/one/lb"}]}]}}`
+
+func TestOpenStackCollector(t *testing.T) {
+	pages := map[string]string{
+		"/v3/auth/tokens":                openStackCatalog,
+		"/one/compute/servers/detail":    `{"servers": [{"id": "s1", "name": "edge-1"}, {"id": "s2", "name": "edge-2"}]}`,
+		"/two/compute/servers/detail":    `{"servers": [{"id": "s3", "name": "lab-1"}]}`,
+		"/one/lb/v2/lbaas/loadbalancers": `{"loadbalancers": [{"id": "lb1", "name": "edge", "vip_address": "10.0.0.5"}]}`,
+		"/one/volume/volumes/detail":     `{"volumes": [{"id": "v1", "size": 20, "attachments": [{"server_id": "s1", "device": "/dev/vdb"}]}]}`,
+	}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message": "volume service unavailable"}`)
+			return
+		}
+		if r.URL.Path == "/v3/auth/tokens" {
+			w.Header().Set("X-Subject-Token", "token")
+			w.WriteHeader(http.StatusCreated)
+		}
+		fmt.Fprint(w, strings.Replace(page, "{server}", server.URL, -1))
+	}))
+	defer server.Close()
+
+	cloud := &openstacklib.Cloud{}
+	cloud.Auth.AuthURL = server.URL
+	cloud.Auth.ApplicationCredentialID = "inventory"
+	client, err := openstacklib.NewClient(cloud)
+	if err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	col, err := NewOpenStackCollectorWithClient(client)
+	if err != nil {
+		t.Fatalf("Failed to create OpenStack collector: %v", err)
+	}
+	if len(col.Regions()) != 2 {
+		t.Errorf("Expected 2 regions, got %v", col.Regions())
+	}
+
+	servers, err := col.CollectServers()
+	if err != nil {
+		t.Fatalf("Failed to collect servers: %v", err)
+	}
+	if len(servers["RegionOne"]) != 2 || len(servers["RegionTwo"]) != 1 {
+		t.Errorf("Unexpected servers %+v", servers)
+	}
+
+	lbs, err := col.CollectLoadBalancers()
+	if err != nil {
+		t.Fatalf("Failed to collect load balancers: %v", err)
+	}
+	if _, ok := lbs["RegionTwo"]; len(lbs["RegionOne"]) != 1 || ok {
+		t.Errorf("Expected load balancers in RegionOne only, got %+v", lbs)
+	}
+
+	_, err = col.CollectVolumes()
+	if err == nil || !strings.Contains(err.Error(), "RegionTwo: volume service unavailable") {
+		t.Errorf("Expected the RegionTwo error, got %v", err)
+	}
+}
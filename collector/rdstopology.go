@@ -0,0 +1,48 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectRDSTopology returns a concurrently collected DB cluster, global database and manual snapshot inventory for all the regions
+func (col AWSCollector) CollectRDSTopology() (map[string]*awslib.RDSTopology, error) {
+	topologies := make(map[string]*awslib.RDSTopology)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectRDSTopologyPerSession(sess)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		topologies[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather RDS Topology Data: %v", err)
+	}
+	return topologies, nil
+}
+
+// CollectRDSTopologyPerSession returns a DB cluster, global database and manual snapshot inventory for a given session
+func CollectRDSTopologyPerSession(sess *session.Session) (*awslib.RDSTopology, error) {
+	topology, err := awslib.GetRDSTopology(sess)
+	return topology, err
+}
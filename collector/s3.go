@@ -0,0 +1,78 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// sessionFor returns the collector session of a region, or a copy of any session moved to that region
+func (col AWSCollector) sessionFor(region string) *session.Session {
+	if sess, ok := col.sessions[region]; ok {
+		return sess
+	}
+	var fallback *session.Session
+	for _, sess := range col.sessions {
+		fallback = sess
+	}
+	return fallback.Copy(&aws.Config{Region: aws.String(region)})
+}
+
+// CollectS3 returns the buckets of the partition keyed by the region they live in.
+// Buckets are listed once, then described concurrently per region
+func (col AWSCollector) CollectS3() (map[string][]*awslib.Bucket, error) {
+	var s3Session *session.Session
+	for _, session := range col.sessions {
+		s3Session = session
+	}
+	buckets, err := awslib.GetAllBuckets(s3Session)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list S3 Buckets: %v", err)
+	}
+
+	byRegion := make(map[string][]*awslib.Bucket)
+	for _, b := range buckets {
+		region, err := awslib.GetBucketRegion(s3Session, aws.StringValue(b.Name))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get region of %s: %v", aws.StringValue(b.Name), err)
+		}
+		byRegion[region] = append(byRegion[region], &awslib.Bucket{Bucket: b, Region: region})
+	}
+
+	errChan := make(chan error, len(byRegion))
+	var wg sync.WaitGroup
+	for region, regionBuckets := range byRegion {
+		wg.Add(1)
+		go func(sess *session.Session, region string, regionBuckets []*awslib.Bucket) {
+			defer wg.Done()
+			for _, b := range regionBuckets {
+				if err := awslib.DescribeBucket(sess, b); err != nil {
+					errChan <- fmt.Errorf("Error while gathering %s: %v", aws.StringValue(b.Name), err)
+					return
+				}
+			}
+		}(col.sessionFor(region), region, regionBuckets)
+	}
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return nil, fmt.Errorf("Failed to gather S3 Data: %v", <-errChan)
+	}
+	return byRegion, nil
+}
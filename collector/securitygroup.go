@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CollectSecurityGroups returns a concurrently collected security group inventory for all the regions
+func (col AWSCollector) CollectSecurityGroups() (map[string][]*awslib.SecurityGroup, error) {
+	groups := make(map[string][]*awslib.SecurityGroup)
+	var mu sync.Mutex
+
+	err := col.forEachRegion(func(region string, sess *session.Session) error {
+		chunk, err := CollectSecurityGroupsPerSession(sess)
+		if err != nil {
+			return err
+		}
+		// Ignore regions with no security groups
+		if chunk == nil {
+			return nil
+		}
+		mu.Lock()
+		groups[region] = chunk
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather Security Group Data: %v", err)
+	}
+	return groups, nil
+}
+
+// CollectSecurityGroupsPerSession returns a security group inventory, with the network interfaces using every group, for a given session
+func CollectSecurityGroupsPerSession(sess *session.Session) ([]*awslib.SecurityGroup, error) {
+	groups, err := awslib.GetAllSecurityGroups(sess)
+	return groups, err
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cost
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+const testOffer = `{
+	"products": {
+		"A": {"sku": "A", "productFamily": "Compute Instance", "attributes": {"regionCode": "us-east-1", "instanceType": "m5.large", "operatingSystem": "Linux", "tenancy": "Shared", "preInstalledSw": "NA", "capacitystatus": "Used"}},
+		"B": {"sku": "B", "productFamily": "Compute Instance", "attributes": {"location": "EU (Ireland)", "instanceType": "m5.large", "operatingSystem": "Linux", "tenancy": "Shared", "preInstalledSw": "NA"}},
+		"C": {"sku": "C", "productFamily": "Compute Instance", "attributes": {"regionCode": "us-east-1", "instanceType": "m5.large", "operatingSystem": "Linux", "tenancy": "Shared", "preInstalledSw": "SQL Web", "capacitystatus": "Used"}},
+		"D": {"sku": "D", "productFamily": "Storage", "attributes": {"regionCode": "us-east-1"}}
+	},
+	"terms": {"OnDemand": {
+		"A": {"A.1": {"priceDimensions": {"A.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "0.0960000000"}}}}},
+		"B": {"B.1": {"priceDimensions": {"B.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "0.1070000000"}}}}},
+		"C": {"C.1": {"priceDimensions": {"C.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "0.5000000000"}}}}}
+	}}
+}`
+
+// TestParseEC2Offer checks the extraction of on-demand prices from a bulk offer file
+func TestParseEC2Offer(t *testing.T) {
+	prices, err := ParseEC2Offer(strings.NewReader(testOffer))
+	if err != nil {
+		t.Fatalf("Failed to parse offer: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("Expected 2 prices, found %v", prices)
+	}
+	var table PriceTable
+	if err := table.MergeEC2(prices); err != nil {
+		t.Fatalf("Failed to merge prices: %v", err)
+	}
+	for _, testCase := range []struct {
+		region string
+		hourly float64
+	}{
+		{"us-east-1", 0.096},
+		{"eu-west-1", 0.107},
+	} {
+		if hourly, ok := table.EC2Hourly(testCase.region, "m5.large", "Linux", "Shared"); !ok || hourly != testCase.hourly {
+			t.Errorf("%s\tWant:%f\tHave:%f", testCase.region, testCase.hourly, hourly)
+		}
+	}
+}
+
+const testRDSOffer = `{
+	"products": {
+		"A": {"sku": "A", "productFamily": "Database Instance", "attributes": {"regionCode": "us-east-1", "instanceType": "db.m5.large", "databaseEngine": "Oracle", "databaseEdition": "Enterprise", "licenseModel": "Bring your own license", "deploymentOption": "Single-AZ"}},
+		"B": {"sku": "B", "productFamily": "Database Instance", "attributes": {"regionCode": "us-east-1", "instanceType": "db.m5.large", "databaseEngine": "Oracle", "databaseEdition": "Standard Two", "licenseModel": "License included", "deploymentOption": "Single-AZ"}},
+		"C": {"sku": "C", "productFamily": "Database Instance", "attributes": {"regionCode": "us-east-1", "instanceType": "db.m5.large", "databaseEngine": "Oracle", "databaseEdition": "Standard Two", "licenseModel": "Bring your own license", "deploymentOption": "Single-AZ"}}
+	},
+	"terms": {"OnDemand": {
+		"A": {"A.1": {"priceDimensions": {"A.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "0.1710000000"}}}}},
+		"B": {"B.1": {"priceDimensions": {"B.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "0.3680000000"}}}}},
+		"C": {"C.1": {"priceDimensions": {"C.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "0.1710000000"}}}}}
+	}}
+}`
+
+// TestParseRDSOffer checks that RDS prices are told apart by edition and license model
+func TestParseRDSOffer(t *testing.T) {
+	prices, err := ParseRDSOffer(strings.NewReader(testRDSOffer))
+	if err != nil {
+		t.Fatalf("Failed to parse offer: %v", err)
+	}
+	var table PriceTable
+	if err := table.MergeRDS(prices); err != nil {
+		t.Fatalf("Failed to merge prices: %v", err)
+	}
+	for _, testCase := range []struct {
+		edition string
+		license string
+		hourly  float64
+	}{
+		{"Enterprise", "Bring your own license", 0.171},
+		{"Standard Two", "License included", 0.368},
+		{"Standard Two", "Bring your own license", 0.171},
+	} {
+		if hourly, ok := table.RDSHourly("us-east-1", "db.m5.large", "Oracle", testCase.edition, testCase.license, "Single-AZ"); !ok || hourly != testCase.hourly {
+			t.Errorf("%s/%s\tWant:%f\tHave:%f", testCase.edition, testCase.license, testCase.hourly, hourly)
+		}
+	}
+	if _, ok := table.RDSHourly("us-east-1", "db.m5.large", "Oracle", "Enterprise", "License included", "Single-AZ"); ok {
+		t.Errorf("Expected no price for a license included Enterprise edition")
+	}
+}
+
+// TestAmbiguousPrices checks that prices sharing a key with different values are rejected rather than picked from
+func TestAmbiguousPrices(t *testing.T) {
+	var table PriceTable
+	err := table.MergeEC2([]EC2Price{
+		{"us-east-1", "m5.large", "Linux", "Shared", 0.096},
+		{"us-east-1", "m5.large", "Linux", "Shared", 0.1},
+	})
+	if err == nil {
+		t.Errorf("Expected an error for ambiguous EC2 prices")
+	}
+	err = table.MergeRDS([]RDSPrice{
+		{"us-east-1", "db.m5.large", "MySQL", "", "", "Single-AZ", 0.171},
+		{"us-east-1", "db.m5.large", "MySQL", "", noLicense, "Single-AZ", 0.2},
+	})
+	if err == nil {
+		t.Errorf("Expected an error for ambiguous RDS prices")
+	}
+}
+
+// TestEstimateInventory checks estimates and their grouping
+func TestEstimateInventory(t *testing.T) {
+	table, err := DefaultPriceTable()
+	if err != nil {
+		t.Fatalf("Failed to load the default price table: %v", err)
+	}
+	running := &ec2.InstanceState{Name: aws.String("running")}
+	inv := collector.AWSInventory{
+		Account: "1",
+		EC2: map[string][]*awslib.Instance{"us-east-1": {
+			{Instance: &ec2.Instance{InstanceId: aws.String("i-1"), InstanceType: aws.String("m5.large"), State: running, Tags: []*ec2.Tag{{Key: aws.String("Team"), Value: aws.String("a")}}}},
+			{Instance: &ec2.Instance{InstanceId: aws.String("i-2"), InstanceType: aws.String("m5.large"), State: running, Platform: aws.String("windows")}},
+			{Instance: &ec2.Instance{InstanceId: aws.String("i-3"), InstanceType: aws.String("m5.large"), State: &ec2.InstanceState{Name: aws.String("stopped")}}},
+			{Instance: &ec2.Instance{InstanceId: aws.String("i-4"), InstanceType: aws.String("x9.huge"), State: running}},
+			{Instance: &ec2.Instance{InstanceId: aws.String("i-5"), InstanceType: aws.String("m5.large"), State: running, PlatformDetails: aws.String("Red Hat Enterprise Linux")}},
+		}},
+		RDS: map[string][]*awslib.DBInstance{"us-east-1": {
+			{DBInstance: &rds.DBInstance{DBInstanceIdentifier: aws.String("db"), DBInstanceClass: aws.String("db.m5.large"), Engine: aws.String("postgres"), MultiAZ: aws.Bool(true),
+				TagList: []*rds.Tag{{Key: aws.String("Team"), Value: aws.String("a")}}}},
+		}},
+	}
+	estimates := table.EstimateInventory(inv)
+	if len(estimates) != 5 {
+		t.Fatalf("Expected 5 estimates, found %d", len(estimates))
+	}
+	for _, e := range estimates {
+		// The default price table has no RHEL price, which must not fall back to the Linux one
+		if e.ID == "i-5" && e.Priced {
+			t.Errorf("Expected a RHEL instance to be unpriced, found %f", e.Hourly)
+		}
+	}
+	totals := GroupBy(estimates, "tag:Team")
+	if len(totals) != 2 || totals[0].Group != "a" || totals[0].Resources != 2 {
+		t.Fatalf("Unexpected totals: %+v", totals)
+	}
+	if want := (0.096 + 0.356) * HoursPerMonth; totals[0].Monthly != want {
+		t.Errorf("Want:%f\tHave:%f", want, totals[0].Monthly)
+	}
+	if totals[1].Group != "(none)" || totals[1].Unpriced != 2 {
+		t.Errorf("Unexpected totals: %+v", totals[1])
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cost
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// HoursPerMonth is the number of hours used to turn hourly prices into monthly ones
+const HoursPerMonth = 730
+
+// Estimate is the estimated on-demand cost of a single resource
+type Estimate struct {
+	Service string            `json:"service"`
+	Account string            `json:"account"`
+	Region  string            `json:"region"`
+	ID      string            `json:"id"`
+	Type    string            `json:"type"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	Priced  bool              `json:"priced"`
+	Hourly  float64           `json:"hourly"`
+	Monthly float64           `json:"monthly"`
+}
+
+// GroupTotal is the estimated monthly cost of a group of resources
+type GroupTotal struct {
+	Group     string  `json:"group"`
+	Resources int     `json:"resources"`
+	Unpriced  int     `json:"unpriced"`
+	Monthly   float64 `json:"monthly"`
+}
+
+// ec2Tenancy maps EC2 placement tenancies to the price list ones
+var ec2Tenancy = map[string]string{
+	"":          "Shared",
+	"default":   "Shared",
+	"dedicated": "Dedicated",
+	"host":      "Host",
+}
+
+// ec2OperatingSystems maps the EC2 platform details to the price list operating systems.
+// Platforms with pre-installed software, such as SQL Server, are left out and end up unpriced
+var ec2OperatingSystems = map[string]string{
+	"Linux/UNIX":                       "Linux",
+	"Red Hat BYOL Linux":               "Linux",
+	"Red Hat Enterprise Linux":         "RHEL",
+	"Red Hat Enterprise Linux with HA": "Red Hat Enterprise Linux with HA",
+	"SUSE Linux":                       "SUSE",
+	"Ubuntu Pro":                       "Ubuntu Pro",
+	"Windows":                          "Windows",
+	"Windows BYOL":                     "Linux",
+}
+
+// ec2OperatingSystem returns the price list operating system of an instance.
+// Inventories dumped without platform details only tell Windows apart from Linux
+func ec2OperatingSystem(i *awslib.Instance) string {
+	if details := aws.StringValue(i.PlatformDetails); details != "" {
+		return ec2OperatingSystems[details]
+	}
+	if strings.EqualFold(aws.StringValue(i.Platform), "windows") {
+		return "Windows"
+	}
+	return "Linux"
+}
+
+// rdsEditions maps the editions of commercial RDS engines to the price list ones
+var rdsEditions = map[string]string{
+	"oracle-ee":      "Enterprise",
+	"oracle-ee-cdb":  "Enterprise",
+	"oracle-se":      "Standard",
+	"oracle-se1":     "Standard One",
+	"oracle-se2":     "Standard Two",
+	"oracle-se2-cdb": "Standard Two",
+	"sqlserver-ee":   "Enterprise",
+	"sqlserver-se":   "Standard",
+	"sqlserver-ex":   "Express",
+	"sqlserver-web":  "Web",
+}
+
+// rdsLicenseModel returns the price list license model of an RDS license model
+func rdsLicenseModel(licenseModel string) string {
+	switch licenseModel {
+	case "license-included":
+		return "License included"
+	case "bring-your-own-license":
+		return byol
+	}
+	return ""
+}
+
+// rdsEngine returns the price list database engine of an RDS engine name
+func rdsEngine(engine string) string {
+	switch {
+	case engine == "mysql":
+		return "MySQL"
+	case engine == "mariadb":
+		return "MariaDB"
+	case engine == "postgres":
+		return "PostgreSQL"
+	case engine == "aurora" || engine == "aurora-mysql":
+		return "Aurora MySQL"
+	case engine == "aurora-postgresql":
+		return "Aurora PostgreSQL"
+	case strings.HasPrefix(engine, "oracle"):
+		return "Oracle"
+	case strings.HasPrefix(engine, "sqlserver"):
+		return "SQL Server"
+	}
+	return engine
+}
+
+func (e *Estimate) price(hourly float64, ok bool) {
+	e.Priced = ok
+	e.Hourly = hourly
+	e.Monthly = hourly * HoursPerMonth
+}
+
+// EstimateInventory estimates the monthly cost of every running EC2 and available RDS instance of an inventory.
+// Resources missing from the price table are returned unpriced
+func (p *PriceTable) EstimateInventory(inv collector.AWSInventory) []Estimate {
+	var estimates []Estimate
+	for region, instances := range inv.EC2 {
+		for _, i := range instances {
+			if i.State != nil && aws.StringValue(i.State.Name) != "running" {
+				continue
+			}
+			os := ec2OperatingSystem(i)
+			var tenancy string
+			if i.Placement != nil {
+				tenancy = aws.StringValue(i.Placement.Tenancy)
+			}
+			e := Estimate{Service: "ec2", Account: inv.Account, Region: region, ID: aws.StringValue(i.InstanceId), Type: aws.StringValue(i.InstanceType), Tags: make(map[string]string)}
+			for _, t := range i.Tags {
+				e.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			e.price(p.EC2Hourly(region, e.Type, os, ec2Tenancy[tenancy]))
+			estimates = append(estimates, e)
+		}
+	}
+	for region, instances := range inv.RDS {
+		for _, i := range instances {
+			if aws.StringValue(i.DBInstanceStatus) == "stopped" {
+				continue
+			}
+			deployment := "Single-AZ"
+			if aws.BoolValue(i.MultiAZ) {
+				deployment = "Multi-AZ"
+			}
+			e := Estimate{Service: "rds", Account: inv.Account, Region: region, ID: aws.StringValue(i.DBInstanceIdentifier), Type: aws.StringValue(i.DBInstanceClass), Tags: make(map[string]string)}
+			for _, t := range i.TagList {
+				e.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			engine := aws.StringValue(i.Engine)
+			e.price(p.RDSHourly(region, e.Type, rdsEngine(engine), rdsEditions[engine], rdsLicenseModel(aws.StringValue(i.LicenseModel)), deployment))
+			estimates = append(estimates, e)
+		}
+	}
+	return estimates
+}
+
+// GroupBy sums estimates by region, account, service or tag:<Key>, most expensive group first
+func GroupBy(estimates []Estimate, by string) []GroupTotal {
+	groups := make(map[string]*GroupTotal)
+	for _, e := range estimates {
+		var group string
+		switch {
+		case by == "region":
+			group = e.Region
+		case by == "account":
+			group = e.Account
+		case by == "service":
+			group = e.Service
+		case strings.HasPrefix(by, "tag:"):
+			group = e.Tags[strings.TrimPrefix(by, "tag:")]
+		}
+		if group == "" {
+			group = "(none)"
+		}
+		g, ok := groups[group]
+		if !ok {
+			g = &GroupTotal{Group: group}
+			groups[group] = g
+		}
+		g.Resources++
+		if !e.Priced {
+			g.Unpriced++
+		}
+		g.Monthly += e.Monthly
+	}
+
+	var totals []GroupTotal
+	for _, g := range groups {
+		totals = append(totals, *g)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Monthly != totals[j].Monthly {
+			return totals[i].Monthly > totals[j].Monthly
+		}
+		return totals[i].Group < totals[j].Group
+	})
+	return totals
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package cost
+
+import (
+	_ "embed" // the default price table is bundled in the binary
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// defaultPrices is a small sample of on-demand prices, refresh it from the AWS Price List for accurate estimates
+//
+//go:embed prices.json
+var defaultPrices []byte
+
+// EC2Price is the hourly on-demand price of an EC2 instance type.
+// OperatingSystem is the price list one, e.g Linux, RHEL, SUSE or Windows
+type EC2Price struct {
+	Region          string  `json:"region"`
+	InstanceType    string  `json:"instance_type"`
+	OperatingSystem string  `json:"operating_system"`
+	Tenancy         string  `json:"tenancy"`
+	Hourly          float64 `json:"hourly"`
+}
+
+// RDSPrice is the hourly on-demand price of an RDS instance class.
+// An empty LicenseModel stands for "No license required", Edition is only set for commercial engines
+type RDSPrice struct {
+	Region        string  `json:"region"`
+	InstanceClass string  `json:"instance_class"`
+	Engine        string  `json:"engine"`
+	Edition       string  `json:"edition,omitempty"`
+	LicenseModel  string  `json:"license_model,omitempty"`
+	Deployment    string  `json:"deployment"`
+	Hourly        float64 `json:"hourly"`
+}
+
+// PriceTable holds the on-demand prices used for estimations
+type PriceTable struct {
+	EC2 []EC2Price `json:"ec2"`
+	RDS []RDSPrice `json:"rds"`
+
+	ec2 map[string]float64
+	rds map[string]float64
+}
+
+func ec2Key(region, instanceType, os, tenancy string) string {
+	return region + "/" + instanceType + "/" + os + "/" + tenancy
+}
+
+func rdsKey(region, instanceClass, engine, edition, licenseModel, deployment string) string {
+	if licenseModel == "" {
+		licenseModel = noLicense
+	}
+	return region + "/" + instanceClass + "/" + engine + "/" + edition + "/" + licenseModel + "/" + deployment
+}
+
+func (price RDSPrice) key() string {
+	return rdsKey(price.Region, price.InstanceClass, price.Engine, price.Edition, price.LicenseModel, price.Deployment)
+}
+
+func (price EC2Price) key() string {
+	return ec2Key(price.Region, price.InstanceType, price.OperatingSystem, price.Tenancy)
+}
+
+// index builds the lookup maps. Several prices sharing a key make the table ambiguous and are an error
+func (p *PriceTable) index() error {
+	p.ec2 = make(map[string]float64)
+	for _, price := range p.EC2 {
+		key := price.key()
+		if current, ok := p.ec2[key]; ok && current != price.Hourly {
+			return fmt.Errorf("Ambiguous EC2 price for %s: %v and %v", key, current, price.Hourly)
+		}
+		p.ec2[key] = price.Hourly
+	}
+	p.rds = make(map[string]float64)
+	for _, price := range p.RDS {
+		key := price.key()
+		if current, ok := p.rds[key]; ok && current != price.Hourly {
+			return fmt.Errorf("Ambiguous RDS price for %s: %v and %v", key, current, price.Hourly)
+		}
+		p.rds[key] = price.Hourly
+	}
+	return nil
+}
+
+// EC2Hourly returns the hourly price of an EC2 instance type, if known
+func (p *PriceTable) EC2Hourly(region, instanceType, os, tenancy string) (float64, bool) {
+	price, ok := p.ec2[ec2Key(region, instanceType, os, tenancy)]
+	return price, ok
+}
+
+// RDSHourly returns the hourly price of an RDS instance class, if known
+func (p *PriceTable) RDSHourly(region, instanceClass, engine, edition, licenseModel, deployment string) (float64, bool) {
+	price, ok := p.rds[rdsKey(region, instanceClass, engine, edition, licenseModel, deployment)]
+	return price, ok
+}
+
+// DefaultPriceTable returns the price table bundled with cloudinventory
+func DefaultPriceTable() (*PriceTable, error) {
+	return parsePriceTable(defaultPrices)
+}
+
+// LoadPriceTable reads a price table from the given path
+func LoadPriceTable(path string) (*PriceTable, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parsePriceTable(data)
+}
+
+func parsePriceTable(data []byte) (*PriceTable, error) {
+	var p PriceTable
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("Invalid price table: %v", err)
+	}
+	if err := p.index(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Save writes the price table to the given path
+func (p *PriceTable) Save(path string) error {
+	sort.Slice(p.EC2, func(i, j int) bool { return p.EC2[i].key() < p.EC2[j].key() })
+	sort.Slice(p.RDS, func(i, j int) bool { return p.RDS[i].key() < p.RDS[j].key() })
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// MergeEC2 replaces the EC2 prices of the regions present in prices
+func (p *PriceTable) MergeEC2(prices []EC2Price) error {
+	regions := make(map[string]bool)
+	for _, price := range prices {
+		regions[price.Region] = true
+	}
+	var kept []EC2Price
+	for _, price := range p.EC2 {
+		if !regions[price.Region] {
+			kept = append(kept, price)
+		}
+	}
+	p.EC2 = append(kept, prices...)
+	return p.index()
+}
+
+// MergeRDS replaces the RDS prices of the regions present in prices
+func (p *PriceTable) MergeRDS(prices []RDSPrice) error {
+	regions := make(map[string]bool)
+	for _, price := range prices {
+		regions[price.Region] = true
+	}
+	var kept []RDSPrice
+	for _, price := range p.RDS {
+		if !regions[price.Region] {
+			kept = append(kept, price)
+		}
+	}
+	p.RDS = append(kept, prices...)
+	return p.index()
+}
+
+// byol is the license model of products whose price excludes the license
+const byol = "Bring your own license"
+
+// noLicense is the license model of products running open source software
+const noLicense = "No license required"
+
+// offer is the subset of an AWS Price List bulk offer file needed to extract on-demand prices
+type offer struct {
+	Products map[string]struct {
+		ProductFamily string            `json:"productFamily"`
+		Attributes    map[string]string `json:"attributes"`
+	} `json:"products"`
+	Terms struct {
+		OnDemand map[string]map[string]struct {
+			PriceDimensions map[string]struct {
+				Unit         string            `json:"unit"`
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// hourly returns the hourly USD on-demand price of a product
+func (o *offer) hourly(sku string) (float64, bool) {
+	for _, term := range o.Terms.OnDemand[sku] {
+		for _, dimension := range term.PriceDimensions {
+			if dimension.Unit != "Hrs" {
+				continue
+			}
+			price, err := strconv.ParseFloat(dimension.PricePerUnit["USD"], 64)
+			if err != nil || price == 0 {
+				continue
+			}
+			return price, true
+		}
+	}
+	return 0, false
+}
+
+// regionCode returns the region of a product, older offer files only carrying the region description
+func regionCode(attributes map[string]string) string {
+	if code := attributes["regionCode"]; code != "" {
+		return code
+	}
+	// Older offer files name European regions "EU (...)" rather than "Europe (...)"
+	location := strings.Replace(attributes["location"], "EU (", "Europe (", 1)
+	for _, partition := range []endpoints.Partition{endpoints.AwsPartition(), endpoints.AwsCnPartition()} {
+		for id, region := range partition.Regions() {
+			if strings.Replace(region.Description(), "EU (", "Europe (", 1) == location {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+func parseOffer(r io.Reader) (*offer, error) {
+	var o offer
+	if err := json.NewDecoder(r).Decode(&o); err != nil {
+		return nil, fmt.Errorf("Invalid offer file: %v", err)
+	}
+	return &o, nil
+}
+
+// ParseEC2Offer extracts the hourly on-demand prices of shared and dedicated instances without
+// pre-installed software from an AmazonEC2 bulk offer file
+func ParseEC2Offer(r io.Reader) ([]EC2Price, error) {
+	o, err := parseOffer(r)
+	if err != nil {
+		return nil, err
+	}
+	var prices []EC2Price
+	for sku, product := range o.Products {
+		a := product.Attributes
+		if product.ProductFamily != "Compute Instance" || a["preInstalledSw"] != "NA" || a["licenseModel"] == byol {
+			continue
+		}
+		if status, ok := a["capacitystatus"]; ok && status != "Used" {
+			continue
+		}
+		region := regionCode(a)
+		hourly, ok := o.hourly(sku)
+		if region == "" || !ok {
+			continue
+		}
+		prices = append(prices, EC2Price{region, a["instanceType"], a["operatingSystem"], a["tenancy"], hourly})
+	}
+	return prices, nil
+}
+
+// ParseRDSOffer extracts the hourly on-demand prices of DB instances, per edition and license model,
+// from an AmazonRDS bulk offer file
+func ParseRDSOffer(r io.Reader) ([]RDSPrice, error) {
+	o, err := parseOffer(r)
+	if err != nil {
+		return nil, err
+	}
+	var prices []RDSPrice
+	for sku, product := range o.Products {
+		a := product.Attributes
+		if product.ProductFamily != "Database Instance" {
+			continue
+		}
+		region := regionCode(a)
+		hourly, ok := o.hourly(sku)
+		if region == "" || !ok {
+			continue
+		}
+		license := a["licenseModel"]
+		if license == noLicense {
+			license = ""
+		}
+		prices = append(prices, RDSPrice{region, a["instanceType"], a["databaseEngine"], a["databaseEdition"], license, a["deploymentOption"], hourly})
+	}
+	return prices, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package digitaloceanlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/cloudinventory/httplib"
+)
+
+// DefaultEndpoint is the DigitalOcean API endpoint
+const DefaultEndpoint = "https://api.digitalocean.com"
+
+// perPage is the number of objects requested per page, the maximum allowed by the API
+const perPage = 200
+
+// Client is a DigitalOcean API client authenticated with a personal access token
+type Client struct {
+	// Endpoint defaults to DefaultEndpoint
+	Endpoint   string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client authenticated with the given token, or with DIGITALOCEAN_TOKEN if empty
+func NewClient(token string) (*Client, error) {
+	if token == "" {
+		token = os.Getenv("DIGITALOCEAN_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("DIGITALOCEAN_TOKEN must be set")
+	}
+	return &Client{Endpoint: DefaultEndpoint, Token: token, HTTPClient: http.DefaultClient}, nil
+}
+
+// get decodes the JSON response of a GET request. Throttled requests are retried by httplib.Get once the rate
+// limit resets, or with backoff
+func (c *Client) get(rawURL string, out interface{}) error {
+	body, err := httplib.Get(c.HTTPClient, rawURL, func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		return nil
+	})
+	if serr, ok := err.(*httplib.StatusError); ok {
+		var apiErr struct {
+			ID      string
+			Message string
+		}
+		if json.Unmarshal(serr.Body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s: %s", apiErr.ID, apiErr.Message)
+		}
+		return serr
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// list appends every object of a collection to items, following links.pages.next
+func (c *Client) list(path, key string, items interface{}) error {
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	var all []json.RawMessage
+	next := strings.TrimSuffix(endpoint, "/") + path + "?" + url.Values{"per_page": {strconv.Itoa(perPage)}}.Encode()
+	for next != "" {
+		var page map[string]json.RawMessage
+		if err := c.get(next, &page); err != nil {
+			return err
+		}
+		var chunk []json.RawMessage
+		if err := json.Unmarshal(page[key], &chunk); err != nil {
+			return fmt.Errorf("Invalid %s page: %v", key, err)
+		}
+		all = append(all, chunk...)
+
+		var links struct {
+			Pages struct{ Next string }
+		}
+		next = ""
+		if json.Unmarshal(page["links"], &links) == nil {
+			next = links.Pages.Next
+		}
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, items)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package digitaloceanlib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	pages := map[string]string{
+		"/v2/droplets?per_page=200": `{"droplets": [{"id": 1, "name": "edge-ams", "status": "active", "region": {"slug": "ams3"}}],
+			"links": {"pages": {"next": "{server}/v2/droplets?page=2&per_page=200"}}, "meta": {"total": 2}}`,
+		"/v2/droplets?page=2&per_page=200": `{"droplets": [{"id": 2, "name": "edge-nyc", "status": "off", "region": {"slug": "nyc1"},
+			"networks": {"v4": [{"ip_address": "203.0.113.10", "type": "public"}]}}], "links": {}, "meta": {"total": 2}}`,
+		"/v2/domains?per_page=200":                          `{"domains": [{"name": "edge.example.com", "ttl": 1800}], "links": {}}`,
+		"/v2/domains/edge.example.com/records?per_page=200": `{"domain_records": [{"id": 7, "type": "A", "name": "www", "data": "203.0.113.10", "ttl": 300}], "links": {}}`,
+	}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"id": "Unauthorized", "message": "Unable to authenticate you"}`)
+			return
+		}
+		page, ok := pages[r.URL.String()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"id": "not_found", "message": "The resource you requested could not be found."}`)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(page, "{server}", server.URL, -1))
+	}))
+	defer server.Close()
+
+	c := &Client{Endpoint: server.URL, Token: "secret", HTTPClient: server.Client()}
+	droplets, err := GetAllDroplets(c)
+	if err != nil {
+		t.Fatalf("Failed to list droplets: %v", err)
+	}
+	if len(droplets) != 2 || droplets[0].Region.Slug != "ams3" || droplets[1].Networks.V4[0].IPAddress != "203.0.113.10" {
+		t.Errorf("Unexpected droplets %+v", droplets)
+	}
+
+	domains, err := GetAllDomains(c)
+	if err != nil {
+		t.Fatalf("Failed to list domains: %v", err)
+	}
+	if len(domains) != 1 || len(domains[0].Records) != 1 || domains[0].Records[0].Data != "203.0.113.10" {
+		t.Errorf("Unexpected domains %+v", domains)
+	}
+
+	_, err = GetAllVolumes(c)
+	if err == nil || err.Error() != "not_found: The resource you requested could not be found." {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+
+	c.Token = "wrong"
+	if _, err := GetAllLoadBalancers(c); err == nil || !strings.Contains(err.Error(), "Unable to authenticate you") {
+		t.Errorf("Expected the authentication error, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package digitaloceanlib
+
+import (
+	"net/url"
+)
+
+// Region is the region a resource lives in
+type Region struct {
+	Slug string `json:"slug"`
+	Name string `json:"name,omitempty"`
+}
+
+// Droplet is a DigitalOcean virtual machine
+type Droplet struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Status    string   `json:"status"`
+	Memory    int      `json:"memory"`
+	Vcpus     int      `json:"vcpus"`
+	Disk      int      `json:"disk"`
+	SizeSlug  string   `json:"size_slug"`
+	Region    Region   `json:"region"`
+	CreatedAt string   `json:"created_at"`
+	Tags      []string `json:"tags,omitempty"`
+	VolumeIDs []string `json:"volume_ids,omitempty"`
+	VPCUUID   string   `json:"vpc_uuid,omitempty"`
+	Image     struct {
+		Slug         string `json:"slug,omitempty"`
+		Distribution string `json:"distribution"`
+		Name         string `json:"name"`
+	} `json:"image"`
+	Networks struct {
+		V4 []struct {
+			IPAddress string `json:"ip_address"`
+			Type      string `json:"type"`
+		} `json:"v4,omitempty"`
+	} `json:"networks"`
+}
+
+// Volume is a DigitalOcean block storage volume
+type Volume struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	SizeGigabytes  int      `json:"size_gigabytes"`
+	FilesystemType string   `json:"filesystem_type,omitempty"`
+	Region         Region   `json:"region"`
+	DropletIDs     []int    `json:"droplet_ids,omitempty"`
+	CreatedAt      string   `json:"created_at"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+// LoadBalancer is a DigitalOcean load balancer
+type LoadBalancer struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	IP              string `json:"ip"`
+	Status          string `json:"status"`
+	Region          Region `json:"region"`
+	DropletIDs      []int  `json:"droplet_ids,omitempty"`
+	Tag             string `json:"tag,omitempty"`
+	ForwardingRules []struct {
+		EntryProtocol  string `json:"entry_protocol"`
+		EntryPort      int    `json:"entry_port"`
+		TargetProtocol string `json:"target_protocol"`
+		TargetPort     int    `json:"target_port"`
+		CertificateID  string `json:"certificate_id,omitempty"`
+	} `json:"forwarding_rules,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Domain is a DigitalOcean DNS domain along with its records
+type Domain struct {
+	Name    string          `json:"name"`
+	TTL     int             `json:"ttl"`
+	Records []*DomainRecord `json:"records,omitempty"`
+}
+
+// DomainRecord is a DNS record of a domain
+type DomainRecord struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+	TTL  int    `json:"ttl"`
+}
+
+// GetAllDroplets returns a complete list of the droplets of the account
+func GetAllDroplets(c *Client) ([]*Droplet, error) {
+	var droplets []*Droplet
+	err := c.list("/v2/droplets", "droplets", &droplets)
+	return droplets, err
+}
+
+// GetAllVolumes returns a complete list of the block storage volumes of the account
+func GetAllVolumes(c *Client) ([]*Volume, error) {
+	var volumes []*Volume
+	err := c.list("/v2/volumes", "volumes", &volumes)
+	return volumes, err
+}
+
+// GetAllLoadBalancers returns a complete list of the load balancers of the account
+func GetAllLoadBalancers(c *Client) ([]*LoadBalancer, error) {
+	var lbs []*LoadBalancer
+	err := c.list("/v2/load_balancers", "load_balancers", &lbs)
+	return lbs, err
+}
+
+// GetAllDomains returns a complete list of the domains of the account with their records
+func GetAllDomains(c *Client) ([]*Domain, error) {
+	var domains []*Domain
+	if err := c.list("/v2/domains", "domains", &domains); err != nil {
+		return nil, err
+	}
+	for _, d := range domains {
+		if err := c.list("/v2/domains/"+url.PathEscape(d.Name)+"/records", "domain_records", &d.Records); err != nil {
+			return domains, err
+		}
+	}
+	return domains, nil
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package gcplib
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobe/cloudinventory/httplib"
+)
+
+// DefaultTokenURI is the Google OAuth 2.0 token endpoint
+const DefaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// readOnlyScope is the scope requested for the inventory, which only reads resources
+const readOnlyScope = "https://www.googleapis.com/auth/cloud-platform.read-only"
+
+// TokenSource provides bearer tokens for the Google Cloud APIs
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// ServiceAccount obtains tokens by signing a JWT assertion with the private key of a service account.
+// Tokens are cached until shortly before they expire
+type ServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	// TokenURI defaults to DefaultTokenURI
+	TokenURI string `json:"token_uri"`
+
+	tokens httplib.TokenCache
+}
+
+// LoadServiceAccount reads a service account JSON key file
+func LoadServiceAccount(path string) (*ServiceAccount, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sa ServiceAccount
+	if err := json.Unmarshal(data, &sa); err != nil {
+		return nil, fmt.Errorf("Invalid service account key %s: %v", path, err)
+	}
+	if sa.ClientEmail == "" || sa.PrivateKey == "" {
+		return nil, fmt.Errorf("Service account key %s has no client_email or private_key", path)
+	}
+	return &sa, nil
+}
+
+// EnvironmentCredentials returns the service account whose key file is named by GOOGLE_APPLICATION_CREDENTIALS
+func EnvironmentCredentials() (*ServiceAccount, error) {
+	path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		return nil, fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS must be set")
+	}
+	return LoadServiceAccount(path)
+}
+
+// Token returns a cached token, or requests a new one with a signed JWT assertion
+func (sa *ServiceAccount) Token() (string, error) {
+	return sa.tokens.Token(sa.requestToken)
+}
+
+// requestToken exchanges a signed JWT assertion for a token
+func (sa *ServiceAccount) requestToken() (string, time.Time, error) {
+	tokenURI := sa.TokenURI
+	if tokenURI == "" {
+		tokenURI = DefaultTokenURI
+	}
+	assertion, err := sa.assertion(tokenURI, time.Now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	resp, err := http.PostForm(tokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", time.Time{}, fmt.Errorf("Invalid token response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("Failed to get Google Cloud token: %s %s", result.Error, result.ErrorDescription)
+	}
+	return result.AccessToken, time.Now().Add(time.Duration(result.ExpiresIn) * time.Second), nil
+}
+
+// assertion returns the RS256 signed JWT exchanged for an access token
+func (sa *ServiceAccount) assertion(audience string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return "", fmt.Errorf("Invalid private key for %s", sa.ClientEmail)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("Invalid private key for %s: %v", sa.ClientEmail, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("Private key for %s is not an RSA key", sa.ClientEmail)
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   sa.ClientEmail,
+		"scope": readOnlyScope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Client is a Google Cloud REST API client
+type Client struct {
+	// Endpoint replaces https://<service>.googleapis.com for every service when set, e.g for private endpoints
+	Endpoint    string
+	Credentials TokenSource
+	HTTPClient  *http.Client
+}
+
+// NewClient returns a client of the public Google Cloud APIs
+func NewClient(creds TokenSource) *Client {
+	return &Client{Credentials: creds, HTTPClient: http.DefaultClient}
+}
+
+// url returns the URL of a path of the given service
+func (c *Client) url(service, path string) string {
+	if c.Endpoint != "" {
+		return strings.TrimSuffix(c.Endpoint, "/") + path
+	}
+	return "https://" + service + ".googleapis.com" + path
+}
+
+// get decodes the JSON response of a GET request, throttled requests being retried by httplib.Get
+func (c *Client) get(rawURL string, out interface{}) error {
+	body, err := httplib.Get(c.HTTPClient, rawURL, func(req *http.Request) error {
+		token, err := c.Credentials.Token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+	if serr, ok := err.(*httplib.StatusError); ok {
+		var apiErr struct {
+			Error struct {
+				Status  string
+				Message string
+			}
+		}
+		if json.Unmarshal(serr.Body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("%s: %s", apiErr.Error.Status, apiErr.Error.Message)
+		}
+		return serr
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// list calls page with every page of a collection, following its nextPageToken
+func (c *Client) list(service, path string, page func(data json.RawMessage) error) error {
+	u, err := url.Parse(c.url(service, path))
+	if err != nil {
+		return err
+	}
+	query := u.Query()
+	for {
+		u.RawQuery = query.Encode()
+		var data json.RawMessage
+		if err := c.get(u.String(), &data); err != nil {
+			return err
+		}
+		if err := page(data); err != nil {
+			return err
+		}
+		var next struct {
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(data, &next); err != nil {
+			return err
+		}
+		if next.NextPageToken == "" {
+			return nil
+		}
+		query.Set("pageToken", next.NextPageToken)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package gcplib
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newStandIn returns a stand-in for the token endpoint and the Google Cloud APIs, serving the given paths.
+// The token endpoint checks the assertion signature. Retries and token caching are left to the httplib tests
+func newStandIn(t *testing.T, key *rsa.PublicKey, pages map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			r.ParseForm()
+			parts := strings.Split(r.Form.Get("assertion"), ".")
+			signature, _ := base64.RawURLEncoding.DecodeString(parts[len(parts)-1])
+			digest := sha256.Sum256([]byte(strings.Join(parts[:len(parts)-1], ".")))
+			if len(parts) != 3 || rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "invalid_grant", "error_description": "Invalid JWT Signature."}`)
+				return
+			}
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Missing bearer token on %s", r.URL)
+		}
+		page, ok := pages[r.URL.String()]
+		if !ok {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": {"code": 403, "message": "Cloud SQL Admin API has not been used", "status": "PERMISSION_DENIED"}}`)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+}
+
+func newServiceAccount(t *testing.T) (*ServiceAccount, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return &ServiceAccount{ProjectID: "shop", ClientEmail: "inventory@shop.iam.gserviceaccount.com", PrivateKey: string(pemKey)}, key
+}
+
+func TestAggregatedList(t *testing.T) {
+	sa, key := newServiceAccount(t)
+	server := newStandIn(t, &key.PublicKey, map[string]string{
+		"/compute/v1/projects/shop/aggregated/instances": `{"items": {
+			"zones/us-central1-a": {"instances": [{"id": "1", "name": "web", "zone": "https://www.googleapis.com/compute/v1/projects/shop/zones/us-central1-a",
+				"status": "RUNNING", "labels": {"env": "prod"}}]},
+			"zones/europe-west1-b": {"warning": {"code": "NO_RESULTS_ON_PAGE"}}},
+			"nextPageToken": "p2"}`,
+		"/compute/v1/projects/shop/aggregated/instances?pageToken=p2": `{"items": {
+			"zones/us-central1-a": {"instances": [{"id": "2", "name": "batch"}]},
+			"zones/europe-west1-b": {"instances": [{"id": "3", "name": "eu"}]}}}`,
+		"/compute/v1/projects/shop/aggregated/forwardingRules": `{"items": {
+			"global": {"forwardingRules": [{"name": "https", "IPAddress": "34.1.2.3", "target": "targetHttpsProxies/shop"}]}}}`,
+	})
+	defer server.Close()
+
+	sa.TokenURI = server.URL + "/token"
+	c := &Client{Endpoint: server.URL, Credentials: sa, HTTPClient: server.Client()}
+	instances, err := GetAllInstances(c, "shop")
+	if err != nil {
+		t.Fatalf("Failed to list instances: %v", err)
+	}
+	if len(instances) != 2 || len(instances["us-central1-a"]) != 2 || instances["us-central1-a"][0].Labels["env"] != "prod" ||
+		len(instances["europe-west1-b"]) != 1 {
+		t.Errorf("Unexpected instances %+v", instances)
+	}
+
+	rules, err := GetAllForwardingRules(c, "shop")
+	if err != nil {
+		t.Fatalf("Failed to list forwarding rules: %v", err)
+	}
+	if len(rules[Global]) != 1 || rules[Global][0].IPAddress != "34.1.2.3" {
+		t.Errorf("Unexpected forwarding rules %+v", rules)
+	}
+	_, err = GetAllSQLInstances(c, "shop")
+	if err == nil || err.Error() != "PERMISSION_DENIED: Cloud SQL Admin API has not been used" {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+}
+
+func TestServiceAccountError(t *testing.T) {
+	sa, _ := newServiceAccount(t)
+	_, other := newServiceAccount(t)
+	server := newStandIn(t, &other.PublicKey, nil)
+	defer server.Close()
+
+	sa.TokenURI = server.URL + "/token"
+	if _, err := sa.Token(); err == nil || !strings.Contains(err.Error(), "Invalid JWT Signature") {
+		t.Errorf("Expected the token error, got %v", err)
+	}
+}
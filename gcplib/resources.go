@@ -0,0 +1,264 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package gcplib
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Global is the location of the resources which do not belong to a region or zone
+const Global = "global"
+
+// Instance is a Compute Engine virtual machine
+type Instance struct {
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	Zone              string             `json:"zone"`
+	MachineType       string             `json:"machineType"`
+	Status            string             `json:"status"`
+	CreationTimestamp string             `json:"creationTimestamp"`
+	Labels            map[string]string  `json:"labels,omitempty"`
+	NetworkInterfaces []NetworkInterface `json:"networkInterfaces,omitempty"`
+	Disks             []AttachedDisk     `json:"disks,omitempty"`
+	SelfLink          string             `json:"selfLink"`
+}
+
+// NetworkInterface is a network interface of an instance, with its external access configurations
+type NetworkInterface struct {
+	Network       string `json:"network"`
+	Subnetwork    string `json:"subnetwork"`
+	NetworkIP     string `json:"networkIP"`
+	AccessConfigs []struct {
+		Name  string `json:"name"`
+		NatIP string `json:"natIP,omitempty"`
+	} `json:"accessConfigs,omitempty"`
+}
+
+// AttachedDisk is a persistent disk attached to an instance
+type AttachedDisk struct {
+	DeviceName string `json:"deviceName"`
+	Source     string `json:"source"`
+	Boot       bool   `json:"boot"`
+	DiskSizeGb string `json:"diskSizeGb,omitempty"`
+}
+
+// ForwardingRule is the frontend of a load balancer, global or regional
+type ForwardingRule struct {
+	ID                  string            `json:"id"`
+	Name                string            `json:"name"`
+	Region              string            `json:"region,omitempty"`
+	IPAddress           string            `json:"IPAddress"`
+	IPProtocol          string            `json:"IPProtocol"`
+	PortRange           string            `json:"portRange,omitempty"`
+	Ports               []string          `json:"ports,omitempty"`
+	Target              string            `json:"target,omitempty"`
+	BackendService      string            `json:"backendService,omitempty"`
+	LoadBalancingScheme string            `json:"loadBalancingScheme"`
+	Network             string            `json:"network,omitempty"`
+	Labels              map[string]string `json:"labels,omitempty"`
+	SelfLink            string            `json:"selfLink"`
+}
+
+// BackendService is the backend of a load balancer, global or regional
+type BackendService struct {
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	Region              string   `json:"region,omitempty"`
+	Protocol            string   `json:"protocol"`
+	LoadBalancingScheme string   `json:"loadBalancingScheme"`
+	HealthChecks        []string `json:"healthChecks,omitempty"`
+	Backends            []struct {
+		Group         string `json:"group"`
+		BalancingMode string `json:"balancingMode,omitempty"`
+	} `json:"backends,omitempty"`
+	SelfLink string `json:"selfLink"`
+}
+
+// SQLInstance is a Cloud SQL instance
+type SQLInstance struct {
+	Name            string `json:"name"`
+	DatabaseVersion string `json:"databaseVersion"`
+	Region          string `json:"region"`
+	GceZone         string `json:"gceZone,omitempty"`
+	State           string `json:"state"`
+	InstanceType    string `json:"instanceType"`
+	ConnectionName  string `json:"connectionName"`
+	Settings        struct {
+		Tier             string            `json:"tier"`
+		AvailabilityType string            `json:"availabilityType,omitempty"`
+		DataDiskSizeGb   string            `json:"dataDiskSizeGb,omitempty"`
+		UserLabels       map[string]string `json:"userLabels,omitempty"`
+		IPConfiguration  struct {
+			IPv4Enabled        bool   `json:"ipv4Enabled"`
+			PrivateNetwork     string `json:"privateNetwork,omitempty"`
+			AuthorizedNetworks []struct {
+				Name  string `json:"name,omitempty"`
+				Value string `json:"value"`
+			} `json:"authorizedNetworks,omitempty"`
+		} `json:"ipConfiguration"`
+		BackupConfiguration struct {
+			Enabled bool `json:"enabled"`
+		} `json:"backupConfiguration"`
+	} `json:"settings"`
+	IPAddresses []struct {
+		Type      string `json:"type"`
+		IPAddress string `json:"ipAddress"`
+	} `json:"ipAddresses,omitempty"`
+	SelfLink string `json:"selfLink"`
+}
+
+// ManagedZone is a Cloud DNS managed zone along with its record sets
+type ManagedZone struct {
+	ID           string               `json:"id"`
+	Name         string               `json:"name"`
+	DNSName      string               `json:"dnsName"`
+	Description  string               `json:"description,omitempty"`
+	Visibility   string               `json:"visibility"`
+	CreationTime string               `json:"creationTime"`
+	Labels       map[string]string    `json:"labels,omitempty"`
+	Records      []*ResourceRecordSet `json:"records,omitempty"`
+}
+
+// ResourceRecordSet is a Cloud DNS record set
+type ResourceRecordSet struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	Rrdatas []string `json:"rrdatas"`
+}
+
+// lastSegment returns the name at the end of a resource URL such as .../zones/us-central1-a
+func lastSegment(link string) string {
+	return link[strings.LastIndex(link, "/")+1:]
+}
+
+// aggregatedList calls add with the location and the raw items of every scope of a Compute Engine aggregated list.
+// Scopes are named zones/<zone>, regions/<region> or global
+func aggregatedList(c *Client, project, collection, field string, add func(location string, items json.RawMessage) error) error {
+	return c.list("compute", "/compute/v1/projects/"+url.PathEscape(project)+"/aggregated/"+collection, func(data json.RawMessage) error {
+		var page struct {
+			Items map[string]map[string]json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for scope, list := range page.Items {
+			// Scopes without resources only hold a warning
+			items, ok := list[field]
+			if !ok {
+				continue
+			}
+			if err := add(lastSegment(scope), items); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetAllInstances returns the Compute Engine instances of a given project keyed by zone
+func GetAllInstances(c *Client, project string) (map[string][]*Instance, error) {
+	instances := make(map[string][]*Instance)
+	err := aggregatedList(c, project, "instances", "instances", func(zone string, items json.RawMessage) error {
+		var chunk []*Instance
+		if err := json.Unmarshal(items, &chunk); err != nil {
+			return err
+		}
+		instances[zone] = append(instances[zone], chunk...)
+		return nil
+	})
+	return instances, err
+}
+
+// GetAllForwardingRules returns the forwarding rules of a given project keyed by region, or Global
+func GetAllForwardingRules(c *Client, project string) (map[string][]*ForwardingRule, error) {
+	rules := make(map[string][]*ForwardingRule)
+	err := aggregatedList(c, project, "forwardingRules", "forwardingRules", func(region string, items json.RawMessage) error {
+		var chunk []*ForwardingRule
+		if err := json.Unmarshal(items, &chunk); err != nil {
+			return err
+		}
+		rules[region] = append(rules[region], chunk...)
+		return nil
+	})
+	return rules, err
+}
+
+// GetAllBackendServices returns the backend services of a given project keyed by region, or Global
+func GetAllBackendServices(c *Client, project string) (map[string][]*BackendService, error) {
+	services := make(map[string][]*BackendService)
+	err := aggregatedList(c, project, "backendServices", "backendServices", func(region string, items json.RawMessage) error {
+		var chunk []*BackendService
+		if err := json.Unmarshal(items, &chunk); err != nil {
+			return err
+		}
+		services[region] = append(services[region], chunk...)
+		return nil
+	})
+	return services, err
+}
+
+// GetAllSQLInstances returns the Cloud SQL instances of a given project keyed by region
+func GetAllSQLInstances(c *Client, project string) (map[string][]*SQLInstance, error) {
+	instances := make(map[string][]*SQLInstance)
+	err := c.list("sqladmin", "/v1/projects/"+url.PathEscape(project)+"/instances", func(data json.RawMessage) error {
+		var page struct {
+			Items []*SQLInstance `json:"items"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, i := range page.Items {
+			instances[i.Region] = append(instances[i.Region], i)
+		}
+		return nil
+	})
+	return instances, err
+}
+
+// GetAllManagedZones returns the Cloud DNS managed zones of a given project with their record sets
+func GetAllManagedZones(c *Client, project string) ([]*ManagedZone, error) {
+	var zones []*ManagedZone
+	base := "/dns/v1/projects/" + url.PathEscape(project) + "/managedZones"
+	err := c.list("dns", base, func(data json.RawMessage) error {
+		var page struct {
+			ManagedZones []*ManagedZone `json:"managedZones"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		zones = append(zones, page.ManagedZones...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, z := range zones {
+		err := c.list("dns", base+"/"+url.PathEscape(z.Name)+"/rrsets", func(data json.RawMessage) error {
+			var page struct {
+				Rrsets []*ResourceRecordSet `json:"rrsets"`
+			}
+			if err := json.Unmarshal(data, &page); err != nil {
+				return err
+			}
+			z.Records = append(z.Records, page.Rrsets...)
+			return nil
+		})
+		if err != nil {
+			return zones, err
+		}
+	}
+	return zones, nil
+}
@@ -1,7 +1,7 @@
 module github.com/adobe/cloudinventory
 
 require (
-	github.com/aws/aws-sdk-go v1.17.8
+	github.com/aws/aws-sdk-go v1.55.8
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/jpillora/backoff v0.0.0-20180909062703-3050d21c67d7
@@ -10,4 +10,5 @@ require (
 	github.com/stretchr/testify v1.3.0 // indirect
 	golang.org/x/net v0.0.0-20190213061140-3a22650c66bd // indirect
 	golang.org/x/text v0.3.0 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 )
@@ -0,0 +1,444 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package graph
+
+import (
+	"strings"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// builder keeps the lookup tables needed to link DNS records to the resources they point at
+type builder struct {
+	g *Graph
+	// byDNS maps fully qualified DNS names of resources to their node ID
+	byDNS map[string]string
+	// byIP maps IP addresses of instances to their node ID
+	byIP map[string]string
+}
+
+// Build returns the relationship graph implied by an AWS inventory
+func Build(inv collector.AWSInventory) *Graph {
+	b := builder{g: New(), byDNS: make(map[string]string), byIP: make(map[string]string)}
+	b.addEC2(inv.EC2)
+	b.addRDS(inv)
+	b.addRDSTopology(inv)
+	b.addLoadBalancers(inv.LoadBalancers)
+	b.addAutoScaling(inv)
+	b.addAPIGateway(inv)
+	b.addCloudFront(inv)
+	b.addHostedZones(inv)
+	return b.g
+}
+
+func (b *builder) addNetwork(from, region, vpcID string, subnetIDs []string, securityGroups map[string]string) {
+	if vpcID != "" {
+		b.g.AddNode("vpc:"+vpcID, TypeVPC, vpcID, region)
+		b.g.AddEdge(from, "vpc:"+vpcID, EdgeInVPC)
+	}
+	for _, subnetID := range subnetIDs {
+		if subnetID == "" {
+			continue
+		}
+		b.g.AddNode("subnet:"+subnetID, TypeSubnet, subnetID, region)
+		b.g.AddEdge(from, "subnet:"+subnetID, EdgeInSubnet)
+		if vpcID != "" {
+			b.g.AddEdge("subnet:"+subnetID, "vpc:"+vpcID, EdgeInVPC)
+		}
+	}
+	for sgID, sgName := range securityGroups {
+		b.g.AddNode("sg:"+sgID, TypeSecurityGroup, sgName, region)
+		b.g.AddEdge(from, "sg:"+sgID, EdgeUsesSecurityGroup)
+		if vpcID != "" {
+			b.g.AddEdge("sg:"+sgID, "vpc:"+vpcID, EdgeInVPC)
+		}
+	}
+}
+
+func (b *builder) addEC2(instances map[string][]*awslib.Instance) {
+	for region, ii := range instances {
+		for _, i := range ii {
+			id := "ec2:" + aws.StringValue(i.InstanceId)
+			label := aws.StringValue(i.InstanceId)
+			for _, t := range i.Tags {
+				if aws.StringValue(t.Key) == "Name" && aws.StringValue(t.Value) != "" {
+					label = aws.StringValue(t.Value)
+				}
+			}
+			b.g.AddNode(id, TypeEC2Instance, label, region)
+
+			for _, name := range []*string{i.PublicDnsName, i.PrivateDnsName} {
+				if aws.StringValue(name) != "" {
+					b.byDNS[normalizeDNS(*name)] = id
+				}
+			}
+			for _, ip := range []*string{i.PublicIpAddress, i.PrivateIpAddress} {
+				if aws.StringValue(ip) != "" {
+					b.byIP[*ip] = id
+				}
+			}
+
+			groups := make(map[string]string)
+			for _, sg := range i.SecurityGroups {
+				groups[aws.StringValue(sg.GroupId)] = aws.StringValue(sg.GroupName)
+			}
+			b.addNetwork(id, region, aws.StringValue(i.VpcId), []string{aws.StringValue(i.SubnetId)}, groups)
+		}
+	}
+}
+
+func (b *builder) addRDS(inv collector.AWSInventory) {
+	for region, dbs := range inv.RDS {
+		for _, db := range dbs {
+			id := "rds:" + region + "/" + aws.StringValue(db.DBInstanceIdentifier)
+			b.g.AddNode(id, TypeRDSInstance, aws.StringValue(db.DBInstanceIdentifier), region)
+			if db.Endpoint != nil && aws.StringValue(db.Endpoint.Address) != "" {
+				b.byDNS[normalizeDNS(*db.Endpoint.Address)] = id
+			}
+
+			groups := make(map[string]string)
+			for _, sg := range db.VpcSecurityGroups {
+				groups[aws.StringValue(sg.VpcSecurityGroupId)] = aws.StringValue(sg.VpcSecurityGroupId)
+			}
+			var vpcID string
+			if db.DBSubnetGroup != nil {
+				vpcID = aws.StringValue(db.DBSubnetGroup.VpcId)
+			}
+			b.addNetwork(id, region, vpcID, nil, groups)
+		}
+	}
+}
+
+// rdsNode returns the node ID of an RDS instance or cluster ARN
+func rdsNode(arn string) (string, bool) {
+	// arn:partition:rds:region:account:db|cluster:identifier
+	parts := strings.SplitN(arn, ":", 7)
+	if len(parts) != 7 || parts[2] != "rds" {
+		return "", false
+	}
+	switch parts[5] {
+	case "db":
+		return "rds:" + parts[3] + "/" + parts[6], true
+	case "cluster":
+		return "rdscluster:" + parts[3] + "/" + parts[6], true
+	}
+	return "", false
+}
+
+// addRDSTopology links DB clusters to their member instances and global databases, and replicas to their source
+func (b *builder) addRDSTopology(inv collector.AWSInventory) {
+	for region, dbs := range inv.RDS {
+		for _, db := range dbs {
+			source := aws.StringValue(db.ReadReplicaSourceDBInstanceIdentifier)
+			if source == "" {
+				continue
+			}
+			sourceID, ok := rdsNode(source)
+			if !ok {
+				sourceID = "rds:" + region + "/" + source
+			}
+			b.g.AddEdge("rds:"+region+"/"+aws.StringValue(db.DBInstanceIdentifier), sourceID, EdgeReplicaOf)
+		}
+	}
+	for region, topology := range inv.RDSTopology {
+		if topology == nil {
+			continue
+		}
+		for _, c := range topology.Clusters {
+			id := "rdscluster:" + region + "/" + aws.StringValue(c.DBClusterIdentifier)
+			b.g.AddNode(id, TypeRDSCluster, aws.StringValue(c.DBClusterIdentifier), region)
+			for _, endpoint := range []*string{c.Endpoint, c.ReaderEndpoint} {
+				if aws.StringValue(endpoint) != "" {
+					b.byDNS[normalizeDNS(*endpoint)] = id
+				}
+			}
+			for _, e := range c.CustomEndpoints {
+				if aws.StringValue(e.Endpoint) != "" {
+					b.byDNS[normalizeDNS(*e.Endpoint)] = id
+				}
+			}
+			for _, m := range c.DBClusterMembers {
+				b.g.AddEdge(id, "rds:"+region+"/"+aws.StringValue(m.DBInstanceIdentifier), EdgeContains)
+			}
+		}
+	}
+	// Replication sources and global databases may span regions, link them once every cluster exists
+	for region, topology := range inv.RDSTopology {
+		if topology == nil {
+			continue
+		}
+		for _, c := range topology.Clusters {
+			if source, ok := rdsNode(aws.StringValue(c.ReplicationSourceIdentifier)); ok {
+				b.g.AddEdge("rdscluster:"+region+"/"+aws.StringValue(c.DBClusterIdentifier), source, EdgeReplicaOf)
+			}
+		}
+		for _, g := range topology.GlobalClusters {
+			id := "global:" + aws.StringValue(g.GlobalClusterIdentifier)
+			b.g.AddNode(id, TypeGlobalDatabase, aws.StringValue(g.GlobalClusterIdentifier), "")
+			for _, m := range g.GlobalClusterMembers {
+				if member, ok := rdsNode(aws.StringValue(m.DBClusterArn)); ok {
+					b.g.AddEdge(id, member, EdgeContains)
+				}
+			}
+		}
+	}
+}
+
+func (b *builder) addLoadBalancers(lbs *collector.LoadBalancers) {
+	if lbs == nil {
+		return
+	}
+	for region, ll := range lbs.Classic {
+		for _, lb := range ll {
+			id := "elb:" + region + "/" + aws.StringValue(lb.LoadBalancerName)
+			b.g.AddNode(id, TypeLoadBalancer, aws.StringValue(lb.LoadBalancerName), region)
+			if aws.StringValue(lb.DNSName) != "" {
+				b.byDNS[normalizeDNS(*lb.DNSName)] = id
+			}
+			for _, i := range lb.Instances {
+				b.g.AddEdge(id, "ec2:"+aws.StringValue(i.InstanceId), EdgeRoutesTo)
+			}
+			groups := make(map[string]string)
+			for _, sg := range lb.SecurityGroups {
+				groups[aws.StringValue(sg)] = aws.StringValue(sg)
+			}
+			b.addNetwork(id, region, aws.StringValue(lb.VPCId), aws.StringValueSlice(lb.Subnets), groups)
+		}
+	}
+	for region, ll := range lbs.ApplicationNetwork {
+		for _, lb := range ll {
+			id := "elbv2:" + region + "/" + aws.StringValue(lb.LoadBalancerName)
+			b.g.AddNode(id, TypeLoadBalancer, aws.StringValue(lb.LoadBalancerName), region)
+			if aws.StringValue(lb.DNSName) != "" {
+				b.byDNS[normalizeDNS(*lb.DNSName)] = id
+			}
+			for _, tg := range lb.TargetGroups {
+				tgID := "tg:" + aws.StringValue(tg.TargetGroupArn)
+				b.g.AddNode(tgID, TypeTargetGroup, aws.StringValue(tg.TargetGroupName), region)
+				b.g.AddEdge(id, tgID, EdgeRoutesTo)
+				for _, th := range tg.TargetHealth {
+					if th.Target == nil {
+						continue
+					}
+					if target := aws.StringValue(th.Target.Id); strings.HasPrefix(target, "i-") {
+						b.g.AddEdge(tgID, "ec2:"+target, EdgeRoutesTo)
+					} else if node, ok := b.byIP[target]; ok {
+						b.g.AddEdge(tgID, node, EdgeRoutesTo)
+					}
+				}
+			}
+			var subnets []string
+			for _, az := range lb.AvailabilityZones {
+				subnets = append(subnets, aws.StringValue(az.SubnetId))
+			}
+			groups := make(map[string]string)
+			for _, sg := range lb.SecurityGroups {
+				groups[aws.StringValue(sg)] = aws.StringValue(sg)
+			}
+			b.addNetwork(id, region, aws.StringValue(lb.VpcId), subnets, groups)
+		}
+	}
+}
+
+// addAutoScaling links Auto Scaling groups to their instances and to the target groups and Classic Load Balancers
+// they register with
+func (b *builder) addAutoScaling(inv collector.AWSInventory) {
+	for region, as := range inv.AutoScaling {
+		if as == nil {
+			continue
+		}
+		for _, group := range as.Groups {
+			name := aws.StringValue(group.AutoScalingGroupName)
+			id := "asg:" + region + "/" + name
+			b.g.AddNode(id, TypeAutoScalingGroup, name, region)
+			for _, i := range group.Instances {
+				b.g.AddEdge(id, "ec2:"+aws.StringValue(i.InstanceId), EdgeContains)
+			}
+			for _, arn := range group.TargetGroupARNs {
+				b.g.AddEdge("tg:"+aws.StringValue(arn), id, EdgeRoutesTo)
+			}
+			for _, lb := range group.LoadBalancerNames {
+				b.g.AddEdge("elb:"+region+"/"+aws.StringValue(lb), id, EdgeRoutesTo)
+			}
+		}
+	}
+	// Instances annotated with their group link to it even when the groups were not dumped
+	for region, instances := range inv.EC2 {
+		for _, i := range instances {
+			if i.AutoScalingGroupName == "" {
+				continue
+			}
+			id := "asg:" + region + "/" + i.AutoScalingGroupName
+			b.g.AddNode(id, TypeAutoScalingGroup, i.AutoScalingGroupName, region)
+			b.g.AddEdge(id, "ec2:"+aws.StringValue(i.InstanceId), EdgeContains)
+		}
+	}
+}
+
+// addCloudFront links CloudFront distributions to the load balancers and instances they use as origins
+func (b *builder) addCloudFront(inv collector.AWSInventory) {
+	for _, d := range inv.CloudFront {
+		id := "cloudfront:" + aws.StringValue(d.Id)
+		b.g.AddNode(id, TypeCloudFront, aws.StringValue(d.DomainName), "")
+		if aws.StringValue(d.DomainName) != "" {
+			b.byDNS[normalizeDNS(*d.DomainName)] = id
+		}
+		if d.Origins == nil {
+			continue
+		}
+		for _, o := range d.Origins.Items {
+			if target, ok := b.resolve(aws.StringValue(o.DomainName)); ok {
+				b.g.AddEdge(id, target, EdgeRoutesTo)
+			}
+		}
+	}
+}
+
+// addAPIGateway links API Gateway custom domain names to the APIs mapped to them, and HTTP APIs to the load balancers
+// they integrate with. APIs and domain names are registered under their execute-api and API Gateway DNS names
+func (b *builder) addAPIGateway(inv collector.AWSInventory) {
+	for region, gateway := range inv.APIGateway {
+		if gateway == nil {
+			continue
+		}
+		for _, api := range gateway.RestAPIs {
+			id := "api:" + region + "/" + aws.StringValue(api.Id)
+			b.g.AddNode(id, TypeAPI, aws.StringValue(api.Name), region)
+			b.byDNS[normalizeDNS(aws.StringValue(api.Id)+".execute-api."+region+".amazonaws.com")] = id
+		}
+		for _, d := range gateway.DomainNames {
+			if d.DomainName == nil {
+				continue
+			}
+			id := "apidomain:" + region + "/" + aws.StringValue(d.DomainName.DomainName)
+			b.g.AddNode(id, TypeAPIDomain, aws.StringValue(d.DomainName.DomainName), region)
+			for _, name := range []*string{d.RegionalDomainName, d.DistributionDomainName} {
+				if aws.StringValue(name) != "" {
+					b.byDNS[normalizeDNS(*name)] = id
+				}
+			}
+			for _, m := range d.BasePathMappings {
+				b.g.AddEdge(id, "api:"+region+"/"+aws.StringValue(m.RestApiId), EdgeRoutesTo)
+			}
+		}
+	}
+	for region, gateway := range inv.APIGatewayV2 {
+		if gateway == nil {
+			continue
+		}
+		for _, api := range gateway.APIs {
+			id := "api:" + region + "/" + aws.StringValue(api.ApiId)
+			b.g.AddNode(id, TypeAPI, aws.StringValue(api.Name), region)
+			if endpoint := hostOf(aws.StringValue(api.ApiEndpoint)); endpoint != "" {
+				b.byDNS[normalizeDNS(endpoint)] = id
+			}
+			for _, i := range api.Integrations {
+				uri := aws.StringValue(i.IntegrationUri)
+				if target, ok := listenerLoadBalancer(uri); ok {
+					b.g.AddEdge(id, target, EdgeRoutesTo)
+				} else if target, ok := b.resolve(hostOf(uri)); ok {
+					b.g.AddEdge(id, target, EdgeRoutesTo)
+				}
+			}
+		}
+		for _, d := range gateway.DomainNames {
+			if d.DomainName == nil {
+				continue
+			}
+			id := "apidomain:" + region + "/" + aws.StringValue(d.DomainName.DomainName)
+			b.g.AddNode(id, TypeAPIDomain, aws.StringValue(d.DomainName.DomainName), region)
+			for _, c := range d.DomainNameConfigurations {
+				if aws.StringValue(c.ApiGatewayDomainName) != "" {
+					b.byDNS[normalizeDNS(*c.ApiGatewayDomainName)] = id
+				}
+			}
+			for _, m := range d.APIMappings {
+				b.g.AddEdge(id, "api:"+region+"/"+aws.StringValue(m.ApiId), EdgeRoutesTo)
+			}
+		}
+	}
+}
+
+// hostOf returns the host of a URL such as https://abc.execute-api.us-east-1.amazonaws.com/prod, or an empty string
+func hostOf(uri string) string {
+	i := strings.Index(uri, "://")
+	if i < 0 {
+		return ""
+	}
+	host := uri[i+3:]
+	if end := strings.IndexAny(host, "/:"); end >= 0 {
+		host = host[:end]
+	}
+	return host
+}
+
+// listenerLoadBalancer returns the load balancer node of an Application or Network Load Balancer listener ARN
+func listenerLoadBalancer(arn string) (string, bool) {
+	// arn:partition:elasticloadbalancing:region:account:listener/app|net/name/id/listener-id
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[2] != "elasticloadbalancing" {
+		return "", false
+	}
+	resource := strings.Split(parts[5], "/")
+	if len(resource) != 5 || resource[0] != "listener" {
+		return "", false
+	}
+	return "elbv2:" + parts[3] + "/" + resource[2], true
+}
+
+// resolve returns the node a DNS name or IP address points at, if known
+func (b *builder) resolve(value string) (string, bool) {
+	if id, ok := b.byIP[value]; ok {
+		return id, true
+	}
+	name := normalizeDNS(value)
+	// Alias targets of load balancers are prefixed with dualstack
+	name = strings.TrimPrefix(name, "dualstack.")
+	if id, ok := b.byDNS[name]; ok {
+		return id, true
+	}
+	if _, ok := b.g.Nodes["dns:"+name]; ok {
+		return "dns:" + name, true
+	}
+	return "", false
+}
+
+func (b *builder) addHostedZones(inv collector.AWSInventory) {
+	// Create every record first so that records pointing at other records can be linked
+	for _, zone := range inv.HostedZones {
+		zoneID := "zone:" + aws.StringValue(zone.Id)
+		b.g.AddNode(zoneID, TypeHostedZone, aws.StringValue(zone.Name), "")
+		for _, r := range zone.Records {
+			id := dnsID(aws.StringValue(r.Name))
+			b.g.AddNode(id, TypeDNSRecord, normalizeDNS(aws.StringValue(r.Name)), "")
+			b.g.AddEdge(zoneID, id, EdgeContains)
+		}
+	}
+	for _, zone := range inv.HostedZones {
+		for _, r := range zone.Records {
+			id := dnsID(aws.StringValue(r.Name))
+			if r.AliasTarget != nil {
+				if target, ok := b.resolve(aws.StringValue(r.AliasTarget.DNSName)); ok {
+					b.g.AddEdge(id, target, EdgeAlias)
+				}
+			}
+			for _, rr := range r.ResourceRecords {
+				if target, ok := b.resolve(aws.StringValue(rr.Value)); ok {
+					b.g.AddEdge(id, target, EdgeResolvesTo)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Node types of the resources in the graph
+const (
+	TypeHostedZone       = "hosted_zone"
+	TypeDNSRecord        = "dns_record"
+	TypeEC2Instance      = "ec2_instance"
+	TypeRDSInstance      = "rds_instance"
+	TypeLoadBalancer     = "load_balancer"
+	TypeTargetGroup      = "target_group"
+	TypeVPC              = "vpc"
+	TypeSubnet           = "subnet"
+	TypeSecurityGroup    = "security_group"
+	TypeAutoScalingGroup = "auto_scaling_group"
+	TypeRDSCluster       = "rds_cluster"
+	TypeGlobalDatabase   = "global_database"
+	TypeCloudFront       = "cloudfront_distribution"
+	TypeAPI              = "api"
+	TypeAPIDomain        = "api_domain"
+)
+
+// Edge types between resources
+const (
+	EdgeContains          = "contains"
+	EdgeAlias             = "alias"
+	EdgeResolvesTo        = "resolves_to"
+	EdgeRoutesTo          = "routes_to"
+	EdgeInVPC             = "in_vpc"
+	EdgeInSubnet          = "in_subnet"
+	EdgeUsesSecurityGroup = "uses_security_group"
+	EdgeReplicaOf         = "replica_of"
+)
+
+// Node is a resource in the graph
+type Node struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Label  string `json:"label"`
+	Region string `json:"region,omitempty"`
+}
+
+// Edge is a typed, directed relationship between two resources
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// Graph is a directed graph of resources and their relationships
+type Graph struct {
+	Nodes map[string]*Node
+	Edges []Edge
+
+	edgeSet map[Edge]bool
+}
+
+// New returns an empty graph
+func New() *Graph {
+	return &Graph{Nodes: make(map[string]*Node), edgeSet: make(map[Edge]bool)}
+}
+
+// AddNode adds a node, keeping the first label and region seen for a given ID
+func (g *Graph) AddNode(id, nodeType, label, region string) *Node {
+	if n, ok := g.Nodes[id]; ok {
+		return n
+	}
+	if label == "" {
+		label = id
+	}
+	n := &Node{ID: id, Type: nodeType, Label: label, Region: region}
+	g.Nodes[id] = n
+	return n
+}
+
+// AddEdge adds a directed edge between two existing nodes, ignoring duplicates
+func (g *Graph) AddEdge(from, to, edgeType string) {
+	e := Edge{from, to, edgeType}
+	if from == to || g.edgeSet[e] {
+		return
+	}
+	if _, ok := g.Nodes[from]; !ok {
+		return
+	}
+	if _, ok := g.Nodes[to]; !ok {
+		return
+	}
+	g.edgeSet[e] = true
+	g.Edges = append(g.Edges, e)
+}
+
+// FindNode returns the ID of the node matching the given node ID or hostname
+func (g *Graph) FindNode(name string) (string, bool) {
+	if _, ok := g.Nodes[name]; ok {
+		return name, true
+	}
+	id := dnsID(name)
+	if _, ok := g.Nodes[id]; ok {
+		return id, true
+	}
+	return "", false
+}
+
+// Reachable returns the subgraph of every node reachable from start following edges.
+// With reverse set, edges are followed backwards, giving every node that reaches start
+func (g *Graph) Reachable(start string, reverse bool) *Graph {
+	adjacency := make(map[string][]Edge)
+	for _, e := range g.Edges {
+		if reverse {
+			adjacency[e.To] = append(adjacency[e.To], e)
+		} else {
+			adjacency[e.From] = append(adjacency[e.From], e)
+		}
+	}
+
+	sub := New()
+	if n, ok := g.Nodes[start]; ok {
+		sub.AddNode(n.ID, n.Type, n.Label, n.Region)
+	}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range adjacency[current] {
+			next := e.To
+			if reverse {
+				next = e.From
+			}
+			if _, seen := sub.Nodes[next]; !seen {
+				n := g.Nodes[next]
+				sub.AddNode(n.ID, n.Type, n.Label, n.Region)
+				queue = append(queue, next)
+			}
+			sub.AddEdge(e.From, e.To, e.Type)
+		}
+	}
+	return sub
+}
+
+// sortedNodes returns the nodes ordered by ID
+func (g *Graph) sortedNodes() []*Node {
+	var nodes []*Node
+	for _, n := range g.Nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// sortedEdges returns the edges ordered by source, destination and type
+func (g *Graph) sortedEdges() []Edge {
+	edges := append([]Edge{}, g.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].Type < edges[j].Type
+	})
+	return edges
+}
+
+// normalizeDNS lowercases a DNS name and makes it fully qualified
+func normalizeDNS(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Replace(name, "\\052", "*", -1)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+func dnsID(name string) string {
+	return "dns:" + normalizeDNS(name)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package graph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/adobe/cloudinventory/awslib"
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+func testInventory() collector.AWSInventory {
+	return collector.AWSInventory{
+		EC2: map[string][]*awslib.Instance{"us-east-1": {
+			{Instance: &ec2.Instance{
+				InstanceId:       aws.String("i-web"),
+				VpcId:            aws.String("vpc-1"),
+				SubnetId:         aws.String("subnet-1"),
+				PrivateIpAddress: aws.String("10.0.0.1"),
+				SecurityGroups:   []*ec2.GroupIdentifier{{GroupId: aws.String("sg-1"), GroupName: aws.String("web")}},
+				Tags:             []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("web")}},
+			}, AutoScalingGroupName: "web", LifecycleState: "InService"},
+			{Instance: &ec2.Instance{InstanceId: aws.String("i-legacy"), PublicIpAddress: aws.String("1.2.3.4")}},
+		}},
+		LoadBalancers: &collector.LoadBalancers{
+			Classic: map[string][]*awslib.ClassicLoadBalancer{"us-east-1": {{
+				LoadBalancerDescription: &elb.LoadBalancerDescription{
+					LoadBalancerName: aws.String("old"),
+					DNSName:          aws.String("old-1.us-east-1.elb.amazonaws.com"),
+					Instances:        []*elb.Instance{{InstanceId: aws.String("i-legacy")}},
+				},
+			}}},
+			ApplicationNetwork: map[string][]*awslib.ApplicationNetworkLoadBalancer{"us-east-1": {{
+				LoadBalancer: &elbv2.LoadBalancer{
+					LoadBalancerName: aws.String("web"),
+					DNSName:          aws.String("web-1.us-east-1.elb.amazonaws.com"),
+					VpcId:            aws.String("vpc-1"),
+				},
+				TargetGroups: []*awslib.TargetGroup{{
+					TargetGroup: &elbv2.TargetGroup{TargetGroupArn: aws.String("arn:tg"), TargetGroupName: aws.String("web")},
+					TargetHealth: []*elbv2.TargetHealthDescription{
+						{Target: &elbv2.TargetDescription{Id: aws.String("i-web")}},
+					},
+				}},
+			}}},
+		},
+		RDS: map[string][]*awslib.DBInstance{"us-east-1": {
+			{DBInstance: &rds.DBInstance{DBInstanceIdentifier: aws.String("aurora-1")}},
+			{DBInstance: &rds.DBInstance{DBInstanceIdentifier: aws.String("aurora-2")}},
+			{DBInstance: &rds.DBInstance{DBInstanceIdentifier: aws.String("legacy-replica"), ReadReplicaSourceDBInstanceIdentifier: aws.String("aurora-2")}},
+		}},
+		RDSTopology: map[string]*awslib.RDSTopology{"us-east-1": {
+			Clusters: []*awslib.DBCluster{{DBCluster: &rds.DBCluster{
+				DBClusterIdentifier: aws.String("aurora"),
+				DBClusterArn:        aws.String("arn:aws:rds:us-east-1:123456789012:cluster:aurora"),
+				Endpoint:            aws.String("aurora.cluster-abc.us-east-1.rds.amazonaws.com"),
+				DBClusterMembers: []*rds.DBClusterMember{
+					{DBInstanceIdentifier: aws.String("aurora-1"), IsClusterWriter: aws.Bool(true)},
+					{DBInstanceIdentifier: aws.String("aurora-2")},
+				},
+			}}},
+			GlobalClusters: []*rds.GlobalCluster{{
+				GlobalClusterIdentifier: aws.String("global"),
+				GlobalClusterMembers:    []*rds.GlobalClusterMember{{DBClusterArn: aws.String("arn:aws:rds:us-east-1:123456789012:cluster:aurora")}},
+			}},
+		}},
+		AutoScaling: map[string]*awslib.AutoScaling{"us-east-1": {
+			Groups: []*autoscaling.Group{{
+				AutoScalingGroupName: aws.String("web"),
+				TargetGroupARNs:      aws.StringSlice([]string{"arn:tg"}),
+				Instances:            []*autoscaling.Instance{{InstanceId: aws.String("i-web")}},
+			}},
+		}},
+		APIGateway: map[string]*awslib.APIGateway{"us-east-1": {
+			RestAPIs: []*awslib.RestAPI{{RestApi: &apigateway.RestApi{Id: aws.String("rest1"), Name: aws.String("orders")}}},
+			DomainNames: []*awslib.RestDomainName{{
+				DomainName: &apigateway.DomainName{
+					DomainName:         aws.String("api.example.com"),
+					RegionalDomainName: aws.String("d-abc.execute-api.us-east-1.amazonaws.com"),
+				},
+				BasePathMappings: []*apigateway.BasePathMapping{{BasePath: aws.String("orders"), RestApiId: aws.String("rest1")}},
+			}},
+		}},
+		APIGatewayV2: map[string]*awslib.APIGatewayV2{"us-east-1": {
+			APIs: []*awslib.API{{
+				Api: &apigatewayv2.Api{ApiId: aws.String("http1"), Name: aws.String("web"), ApiEndpoint: aws.String("https://http1.execute-api.us-east-1.amazonaws.com")},
+				Integrations: []*apigatewayv2.Integration{{
+					IntegrationUri: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/web/50dc6c495c0c9188/f2f7dc8efc522ab2"),
+				}},
+			}},
+			DomainNames: []*awslib.APIDomainName{{
+				DomainName: &apigatewayv2.DomainName{
+					DomainName:               aws.String("api.example.com"),
+					DomainNameConfigurations: []*apigatewayv2.DomainNameConfiguration{{ApiGatewayDomainName: aws.String("d-abc.execute-api.us-east-1.amazonaws.com")}},
+				},
+				APIMappings: []*apigatewayv2.ApiMapping{{ApiId: aws.String("http1"), ApiMappingKey: aws.String("web")}},
+			}},
+		}},
+		HostedZones: []*awslib.HostedZone{{
+			HostedZone: &route53.HostedZone{Id: aws.String("/hostedzone/Z1"), Name: aws.String("example.com.")},
+			Records: []*route53.ResourceRecordSet{
+				{
+					Name:        aws.String("www.example.com."),
+					Type:        aws.String("A"),
+					AliasTarget: &route53.AliasTarget{DNSName: aws.String("dualstack.web-1.us-east-1.elb.amazonaws.com.")},
+				},
+				{
+					Name:            aws.String("app.example.com."),
+					Type:            aws.String("CNAME"),
+					ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("www.example.com")}},
+				},
+				{
+					Name:            aws.String("db.example.com."),
+					Type:            aws.String("CNAME"),
+					ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("aurora.cluster-abc.us-east-1.rds.amazonaws.com")}},
+				},
+				{
+					Name:        aws.String("api.example.com."),
+					Type:        aws.String("A"),
+					AliasTarget: &route53.AliasTarget{DNSName: aws.String("d-abc.execute-api.us-east-1.amazonaws.com.")},
+				},
+				{
+					Name:            aws.String("legacy.example.com."),
+					Type:            aws.String("A"),
+					ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}},
+				},
+			},
+		}},
+	}
+}
+
+func hasEdge(g *Graph, from, to, edgeType string) bool {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to && e.Type == edgeType {
+			return true
+		}
+	}
+	return false
+}
+
+// TestBuild checks the relationships implied by the inventory
+func TestBuild(t *testing.T) {
+	g := Build(testInventory())
+	for _, e := range []Edge{
+		{"zone:/hostedzone/Z1", "dns:www.example.com.", EdgeContains},
+		{"dns:www.example.com.", "elbv2:us-east-1/web", EdgeAlias},
+		{"dns:app.example.com.", "dns:www.example.com.", EdgeResolvesTo},
+		{"dns:legacy.example.com.", "ec2:i-legacy", EdgeResolvesTo},
+		{"elbv2:us-east-1/web", "tg:arn:tg", EdgeRoutesTo},
+		{"tg:arn:tg", "ec2:i-web", EdgeRoutesTo},
+		{"elb:us-east-1/old", "ec2:i-legacy", EdgeRoutesTo},
+		{"ec2:i-web", "subnet:subnet-1", EdgeInSubnet},
+		{"subnet:subnet-1", "vpc:vpc-1", EdgeInVPC},
+		{"ec2:i-web", "sg:sg-1", EdgeUsesSecurityGroup},
+		{"tg:arn:tg", "asg:us-east-1/web", EdgeRoutesTo},
+		{"asg:us-east-1/web", "ec2:i-web", EdgeContains},
+		{"rdscluster:us-east-1/aurora", "rds:us-east-1/aurora-1", EdgeContains},
+		{"rdscluster:us-east-1/aurora", "rds:us-east-1/aurora-2", EdgeContains},
+		{"rds:us-east-1/legacy-replica", "rds:us-east-1/aurora-2", EdgeReplicaOf},
+		{"global:global", "rdscluster:us-east-1/aurora", EdgeContains},
+		{"dns:db.example.com.", "rdscluster:us-east-1/aurora", EdgeResolvesTo},
+		{"dns:api.example.com.", "apidomain:us-east-1/api.example.com", EdgeAlias},
+		{"apidomain:us-east-1/api.example.com", "api:us-east-1/rest1", EdgeRoutesTo},
+		{"apidomain:us-east-1/api.example.com", "api:us-east-1/http1", EdgeRoutesTo},
+		{"api:us-east-1/http1", "elbv2:us-east-1/web", EdgeRoutesTo},
+	} {
+		if !hasEdge(g, e.From, e.To, e.Type) {
+			t.Errorf("Missing edge %v", e)
+		}
+	}
+	if g.Nodes["ec2:i-web"].Label != "web" {
+		t.Errorf("Expected the Name tag as label, found %s", g.Nodes["ec2:i-web"].Label)
+	}
+}
+
+// TestReachable checks the subgraphs reachable from and reaching a resource
+func TestReachable(t *testing.T) {
+	g := Build(testInventory())
+	start, ok := g.FindNode("App.Example.com")
+	if !ok {
+		t.Fatalf("Could not find hostname")
+	}
+	sub := g.Reachable(start, false)
+	for _, id := range []string{"dns:app.example.com.", "dns:www.example.com.", "elbv2:us-east-1/web", "tg:arn:tg", "ec2:i-web", "vpc:vpc-1", "sg:sg-1"} {
+		if _, ok := sub.Nodes[id]; !ok {
+			t.Errorf("Expected %s to be reachable", id)
+		}
+	}
+	for _, id := range []string{"ec2:i-legacy", "zone:/hostedzone/Z1"} {
+		if _, ok := sub.Nodes[id]; ok {
+			t.Errorf("Did not expect %s to be reachable", id)
+		}
+	}
+
+	sub = g.Reachable("ec2:i-legacy", true)
+	for _, id := range []string{"elb:us-east-1/old", "dns:legacy.example.com.", "zone:/hostedzone/Z1"} {
+		if _, ok := sub.Nodes[id]; !ok {
+			t.Errorf("Expected %s to reach ec2:i-legacy", id)
+		}
+	}
+}
+
+// TestWrite checks every export format
+func TestWrite(t *testing.T) {
+	g := Build(testInventory())
+	var buf bytes.Buffer
+	if err := g.Write(&buf, "dot"); err != nil {
+		t.Fatalf("Failed to write DOT: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"dns:www.example.com." -> "elbv2:us-east-1/web" [label="alias"];`) {
+		t.Errorf("Unexpected DOT output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := g.Write(&buf, "graphml"); err != nil {
+		t.Fatalf("Failed to write GraphML: %v", err)
+	}
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid GraphML: %v", err)
+	}
+	if len(doc.Graph.Nodes) != len(g.Nodes) || len(doc.Graph.Edges) != len(g.Edges) {
+		t.Errorf("GraphML has %d nodes and %d edges", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+
+	buf.Reset()
+	if err := g.Write(&buf, "json"); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	if err := g.Write(&buf, "png"); err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package httplib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+// MaxRetries is the number of times a throttled request is retried before giving up
+const MaxRetries = 10
+
+// StatusError is a response with a status other than 200 OK, along with its body for the caller to extract
+// the error reported by the API
+type StatusError struct {
+	Status     string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return "Unexpected status " + e.Status
+}
+
+// newBackoff returns the backoff used to retry throttled API calls
+func newBackoff() *backoff.Backoff {
+	return &backoff.Backoff{
+		// Wait at most 30 seconds between attempts, longer than the 10 seconds default
+		Min:    10 * time.Millisecond,
+		Max:    30 * time.Second,
+		Factor: 2,
+		Jitter: false,
+	}
+}
+
+// isThrottled reports whether an HTTP status is worth retrying with backoff
+func isThrottled(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// retryDelay returns the delay asked by a throttled response, in seconds in its Retry-After header or as the
+// Unix time its RateLimit-Reset header resets the rate limit at
+func retryDelay(resp *http.Response) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+			return wait, true
+		}
+	}
+	return 0, false
+}
+
+// Get returns the body of a 200 OK response to a JSON GET request, authorize setting the credentials of every
+// attempt. Throttled requests are retried up to MaxRetries times after the delay asked by the API, or with backoff.
+// Other statuses are returned as a *StatusError
+func Get(client *http.Client, rawURL string, authorize func(req *http.Request) error) ([]byte, error) {
+	b := newBackoff()
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		if err := authorize(req); err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if isThrottled(resp.StatusCode) && attempt < MaxRetries {
+			delay := b.Duration()
+			if asked, ok := retryDelay(resp); ok {
+				delay = asked
+			}
+			time.Sleep(delay)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, &StatusError{resp.Status, resp.StatusCode, body}
+		}
+		return body, nil
+	}
+}
+
+// TokenCache holds a bearer token until shortly before it expires
+type TokenCache struct {
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token returns the cached token, or the one returned by fetch along with its expiry. Tokens are renewed a minute
+// early to avoid using a token expiring in flight, and kept forever when their expiry is zero
+func (c *TokenCache) Token(fetch func() (string, time.Time, error)) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && (c.expires.IsZero() || time.Now().Before(c.expires.Add(-time.Minute))) {
+		return c.token, nil
+	}
+	token, expires, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf("Empty token")
+	}
+	c.token, c.expires = token, expires
+	return token, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package httplib
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGet checks that throttled requests are retried after the delay asked, and at most MaxRetries times
+func TestGet(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Missing credentials on attempt %d", requests)
+		}
+		switch r.URL.Path {
+		case "/throttled-once":
+			if requests == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`not found`))
+		default:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	authorize := func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer token")
+		return nil
+	}
+
+	body, err := Get(server.Client(), server.URL+"/throttled-once", authorize)
+	if err != nil || string(body) != "{}" || requests != 2 {
+		t.Errorf("Expected a single retry, got %q %v after %d requests", body, err, requests)
+	}
+
+	requests = 0
+	_, err = Get(server.Client(), server.URL+"/unavailable", authorize)
+	if serr, ok := err.(*StatusError); !ok || serr.StatusCode != http.StatusServiceUnavailable || requests != MaxRetries+1 {
+		t.Errorf("Expected to give up after %d retries, got %v after %d requests", MaxRetries, err, requests)
+	}
+
+	_, err = Get(server.Client(), server.URL+"/missing", authorize)
+	if serr, ok := err.(*StatusError); !ok || string(serr.Body) != "not found" {
+		t.Errorf("Expected the body of the missing page, got %v", err)
+	}
+}
+
+// TestTokenCache checks that tokens are fetched again a minute before they expire
+func TestTokenCache(t *testing.T) {
+	var c TokenCache
+	fetches := 0
+	expires := time.Now().Add(time.Hour)
+	fetch := func() (string, time.Time, error) {
+		fetches++
+		return "token", expires, nil
+	}
+	for i := 0; i < 2; i++ {
+		if token, err := c.Token(fetch); err != nil || token != "token" {
+			t.Fatalf("Unexpected token %q: %v", token, err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected the token to be cached, fetched %d times", fetches)
+	}
+
+	expires = time.Now().Add(30 * time.Second)
+	c = TokenCache{}
+	c.Token(fetch)
+	c.Token(fetch)
+	if fetches != 3 {
+		t.Errorf("Expected a token expiring within a minute to be renewed, fetched %d times", fetches)
+	}
+
+	if _, err := c.Token(func() (string, time.Time, error) { return "", time.Time{}, errors.New("denied") }); err == nil {
+		t.Errorf("Expected the fetch error")
+	}
+}
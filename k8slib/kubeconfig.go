@@ -0,0 +1,302 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package k8slib
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adobe/cloudinventory/httplib"
+	"gopkg.in/yaml.v2"
+)
+
+// Kubeconfig holds the clusters, users and contexts of one or more kubeconfig files
+type Kubeconfig struct {
+	Clusters []struct {
+		Name    string
+		Cluster struct {
+			Server                   string
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		}
+	}
+	Users []struct {
+		Name string
+		User struct {
+			Token                 string
+			TokenFile             string `yaml:"tokenFile"`
+			Username              string
+			Password              string
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Exec                  *execConfig
+		}
+	}
+	Contexts []struct {
+		Name    string
+		Context struct {
+			Cluster string
+			User    string
+		}
+	}
+	CurrentContext string `yaml:"current-context"`
+}
+
+// execConfig is a credential plugin, such as aws eks get-token
+type execConfig struct {
+	Command string
+	Args    []string
+	Env     []struct {
+		Name  string
+		Value string
+	}
+}
+
+// DefaultKubeconfig returns the kubeconfig files named by KUBECONFIG, or ~/.kube/config
+func DefaultKubeconfig() []string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return filepath.SplitList(env)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{filepath.Join(home, ".kube", "config")}
+}
+
+// LoadKubeconfig reads and merges the given kubeconfig files. As with kubectl, the first file
+// defining a cluster, user, context or the current context wins.
+// Relative file references are resolved against the directory of the file they appear in
+func LoadKubeconfig(paths ...string) (*Kubeconfig, error) {
+	merged := &Kubeconfig{}
+	clusters, users, contexts := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var kc Kubeconfig
+		if err := yaml.Unmarshal(data, &kc); err != nil {
+			return nil, fmt.Errorf("Invalid kubeconfig %s: %v", path, err)
+		}
+		dir := filepath.Dir(path)
+		for _, c := range kc.Clusters {
+			if !clusters[c.Name] {
+				clusters[c.Name] = true
+				c.Cluster.CertificateAuthority = resolve(dir, c.Cluster.CertificateAuthority)
+				merged.Clusters = append(merged.Clusters, c)
+			}
+		}
+		for _, u := range kc.Users {
+			if !users[u.Name] {
+				users[u.Name] = true
+				u.User.TokenFile = resolve(dir, u.User.TokenFile)
+				u.User.ClientCertificate = resolve(dir, u.User.ClientCertificate)
+				u.User.ClientKey = resolve(dir, u.User.ClientKey)
+				merged.Users = append(merged.Users, u)
+			}
+		}
+		for _, c := range kc.Contexts {
+			if !contexts[c.Name] {
+				contexts[c.Name] = true
+				merged.Contexts = append(merged.Contexts, c)
+			}
+		}
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = kc.CurrentContext
+		}
+	}
+	return merged, nil
+}
+
+// resolve returns path relative to dir unless empty or absolute
+func resolve(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// dataOrFile returns the decoded base64 data, or the content of the file
+func dataOrFile(data, path string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if path != "" {
+		return ioutil.ReadFile(path)
+	}
+	return nil, nil
+}
+
+// Client returns an API client for the given context, or the current context if empty
+func (kc *Kubeconfig) Client(context string) (*Client, error) {
+	if context == "" {
+		context = kc.CurrentContext
+	}
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == context {
+			clusterName, userName, found = c.Context.Cluster, c.Context.User, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("Context %q not found in kubeconfig", context)
+	}
+
+	client := &Client{}
+	tlsConfig := &tls.Config{}
+	found = false
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+		client.Server = strings.TrimSuffix(c.Cluster.Server, "/")
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		ca, err := dataOrFile(c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid certificate authority for cluster %s: %v", clusterName, err)
+		}
+		if ca != nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("Invalid certificate authority for cluster %s", clusterName)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("Cluster %q of context %q not found in kubeconfig", clusterName, context)
+	}
+
+	found = false
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		found = true
+		user := u.User
+		cert, err := dataOrFile(user.ClientCertificateData, user.ClientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid client certificate for user %s: %v", userName, err)
+		}
+		key, err := dataOrFile(user.ClientKeyData, user.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid client key for user %s: %v", userName, err)
+		}
+		if cert != nil {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid client certificate for user %s: %v", userName, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+		switch {
+		case user.Token != "":
+			client.Credentials = staticCredentials{token: user.Token}
+		case user.TokenFile != "":
+			token, err := ioutil.ReadFile(user.TokenFile)
+			if err != nil {
+				return nil, err
+			}
+			client.Credentials = staticCredentials{token: strings.TrimSpace(string(token))}
+		case user.Username != "":
+			client.Credentials = staticCredentials{username: user.Username, password: user.Password}
+		case user.Exec != nil:
+			client.Credentials = &execCredentials{config: *user.Exec}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("User %q of context %q not found in kubeconfig", userName, context)
+	}
+	client.HTTPClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}, Timeout: time.Minute}
+	return client, nil
+}
+
+// Credentials authenticates the requests sent to the API server
+type Credentials interface {
+	Authorize(req *http.Request) error
+}
+
+// staticCredentials is a bearer token or basic authentication
+type staticCredentials struct {
+	token    string
+	username string
+	password string
+}
+
+// Authorize sets the Authorization header
+func (s staticCredentials) Authorize(req *http.Request) error {
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	} else {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	return nil
+}
+
+// execCredentials runs a credential plugin and caches its token until it expires
+type execCredentials struct {
+	config execConfig
+	tokens httplib.TokenCache
+}
+
+// Authorize sets the bearer token returned by the plugin
+func (e *execCredentials) Authorize(req *http.Request) error {
+	token, err := e.tokens.Token(e.run)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// run runs the plugin, returning its token and when it expires
+func (e *execCredentials) run() (string, time.Time, error) {
+	cmd := exec.Command(e.config.Command, e.config.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range e.config.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Credential plugin %s failed: %v %s", e.config.Command, err, strings.TrimSpace(stderr.String()))
+	}
+	var cred struct {
+		Status struct {
+			Token               string
+			ExpirationTimestamp time.Time
+		}
+	}
+	if err := json.Unmarshal(out, &cred); err != nil || cred.Status.Token == "" {
+		return "", time.Time{}, fmt.Errorf("Credential plugin %s returned no token", e.config.Command)
+	}
+	return cred.Status.Token, cred.Status.ExpirationTimestamp, nil
+}
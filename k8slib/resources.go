@@ -0,0 +1,275 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package k8slib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/cloudinventory/httplib"
+)
+
+// listLimit is the number of objects requested per page
+const listLimit = 500
+
+// Client is a Kubernetes API server client
+type Client struct {
+	Server      string
+	Credentials Credentials
+	HTTPClient  *http.Client
+}
+
+// get decodes the JSON response of a GET request, throttled requests being retried by httplib.Get
+func (c *Client) get(rawURL string, out interface{}) error {
+	body, err := httplib.Get(c.HTTPClient, rawURL, func(req *http.Request) error {
+		if c.Credentials == nil {
+			return nil
+		}
+		return c.Credentials.Authorize(req)
+	})
+	if serr, ok := err.(*httplib.StatusError); ok {
+		var status struct {
+			Reason  string
+			Message string
+		}
+		if json.Unmarshal(serr.Body, &status) == nil && status.Message != "" {
+			return fmt.Errorf("%s: %s", status.Reason, status.Message)
+		}
+		return serr
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// list appends every object of a collection to items, following the continue token
+func (c *Client) list(path string, items interface{}) error {
+	var all []json.RawMessage
+	cont := ""
+	for {
+		query := url.Values{"limit": {strconv.Itoa(listLimit)}}
+		if cont != "" {
+			query.Set("continue", cont)
+		}
+		var page struct {
+			Items    []json.RawMessage
+			Metadata struct {
+				Continue string
+			}
+		}
+		if err := c.get(c.Server+path+"?"+query.Encode(), &page); err != nil {
+			return err
+		}
+		all = append(all, page.Items...)
+		if page.Metadata.Continue == "" {
+			break
+		}
+		cont = page.Metadata.Continue
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, items)
+}
+
+// ObjectMeta is the metadata common to all Kubernetes objects
+type ObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	UID               string            `json:"uid"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp"`
+}
+
+// AWSResource links a Kubernetes object to the AWS resource backing it
+type AWSResource struct {
+	Region string `json:"region"`
+	ID     string `json:"id"`
+}
+
+// Node is a cluster node. EC2 is set when the node is an instance of the AWS inventory
+type Node struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		ProviderID    string `json:"providerID,omitempty"`
+		Unschedulable bool   `json:"unschedulable,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Capacity  map[string]string `json:"capacity,omitempty"`
+		Addresses []struct {
+			Type    string `json:"type"`
+			Address string `json:"address"`
+		} `json:"addresses,omitempty"`
+		NodeInfo struct {
+			KubeletVersion   string `json:"kubeletVersion"`
+			OSImage          string `json:"osImage"`
+			ContainerRuntime string `json:"containerRuntimeVersion"`
+			Architecture     string `json:"architecture"`
+		} `json:"nodeInfo"`
+	} `json:"status"`
+	EC2 *AWSResource `json:"ec2,omitempty"`
+}
+
+// Namespace is a cluster namespace
+type Namespace struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Status   struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+// Workload is a Deployment or a StatefulSet
+type Workload struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int `json:"replicas,omitempty"`
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Name  string `json:"name"`
+					Image string `json:"image"`
+				} `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		Replicas      int `json:"replicas"`
+		ReadyReplicas int `json:"readyReplicas"`
+	} `json:"status"`
+}
+
+// LoadBalancerStatus lists the addresses a load balancer exposes a Service or Ingress on
+type LoadBalancerStatus struct {
+	Ingress []struct {
+		IP       string `json:"ip,omitempty"`
+		Hostname string `json:"hostname,omitempty"`
+	} `json:"ingress,omitempty"`
+}
+
+// Service is a Service of type LoadBalancer. ELB is set when its load balancer is part of the AWS inventory
+type Service struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Type     string            `json:"type"`
+		Selector map[string]string `json:"selector,omitempty"`
+		Ports    []struct {
+			Name     string `json:"name,omitempty"`
+			Protocol string `json:"protocol"`
+			Port     int    `json:"port"`
+			NodePort int    `json:"nodePort,omitempty"`
+		} `json:"ports"`
+	} `json:"spec"`
+	Status struct {
+		LoadBalancer LoadBalancerStatus `json:"loadBalancer"`
+	} `json:"status"`
+	ELB *AWSResource `json:"elb,omitempty"`
+}
+
+// Ingress is an Ingress. ELB is set when its load balancer is part of the AWS inventory
+type Ingress struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		IngressClassName string `json:"ingressClassName,omitempty"`
+		Rules            []struct {
+			Host string `json:"host,omitempty"`
+		} `json:"rules,omitempty"`
+		TLS []struct {
+			Hosts      []string `json:"hosts,omitempty"`
+			SecretName string   `json:"secretName,omitempty"`
+		} `json:"tls,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		LoadBalancer LoadBalancerStatus `json:"loadBalancer"`
+	} `json:"status"`
+	ELB *AWSResource `json:"elb,omitempty"`
+}
+
+// Hostnames returns the load balancer hostnames of the status
+func (s LoadBalancerStatus) Hostnames() []string {
+	var hostnames []string
+	for _, i := range s.Ingress {
+		if i.Hostname != "" {
+			hostnames = append(hostnames, i.Hostname)
+		}
+	}
+	return hostnames
+}
+
+// InstanceID returns the EC2 instance ID of an AWS provider ID such as aws:///us-east-1a/i-0123456789abcdef0,
+// or an empty string for other providers
+func InstanceID(providerID string) string {
+	if !strings.HasPrefix(providerID, "aws://") {
+		return ""
+	}
+	id := providerID[strings.LastIndex(providerID, "/")+1:]
+	if !strings.HasPrefix(id, "i-") {
+		return ""
+	}
+	return id
+}
+
+// GetAllNodes returns a complete list of the cluster nodes
+func GetAllNodes(c *Client) ([]*Node, error) {
+	var nodes []*Node
+	err := c.list("/api/v1/nodes", &nodes)
+	return nodes, err
+}
+
+// GetAllNamespaces returns a complete list of the cluster namespaces
+func GetAllNamespaces(c *Client) ([]*Namespace, error) {
+	var namespaces []*Namespace
+	err := c.list("/api/v1/namespaces", &namespaces)
+	return namespaces, err
+}
+
+// GetAllDeployments returns a complete list of the Deployments of all namespaces
+func GetAllDeployments(c *Client) ([]*Workload, error) {
+	var deployments []*Workload
+	err := c.list("/apis/apps/v1/deployments", &deployments)
+	return deployments, err
+}
+
+// GetAllStatefulSets returns a complete list of the StatefulSets of all namespaces
+func GetAllStatefulSets(c *Client) ([]*Workload, error) {
+	var statefulSets []*Workload
+	err := c.list("/apis/apps/v1/statefulsets", &statefulSets)
+	return statefulSets, err
+}
+
+// GetAllLoadBalancerServices returns a complete list of the Services of type LoadBalancer of all namespaces
+func GetAllLoadBalancerServices(c *Client) ([]*Service, error) {
+	var services []*Service
+	if err := c.list("/api/v1/services", &services); err != nil {
+		return nil, err
+	}
+	var lbs []*Service
+	for _, s := range services {
+		if s.Spec.Type == "LoadBalancer" {
+			lbs = append(lbs, s)
+		}
+	}
+	return lbs, nil
+}
+
+// GetAllIngresses returns a complete list of the Ingresses of all namespaces
+func GetAllIngresses(c *Client) ([]*Ingress, error) {
+	var ingresses []*Ingress
+	err := c.list("/apis/networking.k8s.io/v1/ingresses", &ingresses)
+	return ingresses, err
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package k8slib
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newFakeAPIServer returns a TLS stand-in for an API server accepting the given bearer token and serving the given
+// pages keyed by path and continue token. Retries are left to the httplib tests
+func newFakeAPIServer(token string, pages map[string]string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"kind": "Status", "reason": "Unauthorized", "message": "Unauthorized"}`)
+			return
+		}
+		key := r.URL.Path + "?" + r.URL.Query().Get("continue")
+		page, ok := pages[key]
+		if !ok {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(w, `{"kind": "Status", "reason": "Forbidden", "message": "cannot list resource at %s"}`, r.URL.Path)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+}
+
+// writeKubeconfig writes a kubeconfig with a context named after every server, authenticated by the token file
+func writeKubeconfig(t *testing.T, dir string, servers map[string]*httptest.Server) string {
+	config := "apiVersion: v1\nkind: Config\nclusters:\n"
+	for name, server := range servers {
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		config += fmt.Sprintf("- name: %s\n  cluster:\n    server: %s\n    certificate-authority-data: %s\n", name, server.URL, base64.StdEncoding.EncodeToString(ca))
+	}
+	config += "users:\n- name: inventory\n  user:\n    tokenFile: token\ncontexts:\n"
+	for name := range servers {
+		config += fmt.Sprintf("- name: %s\n  context:\n    cluster: %s\n    user: inventory\n", name, name)
+	}
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "token"), []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestList(t *testing.T) {
+	server := newFakeAPIServer("secret", map[string]string{
+		"/api/v1/nodes?": `{"kind": "NodeList", "metadata": {"continue": "n2"}, "items": [
+			{"metadata": {"name": "ip-10-0-1-10.ec2.internal"}, "spec": {"providerID": "aws:///us-east-1a/i-0123456789abcdef0"},
+				"status": {"nodeInfo": {"kubeletVersion": "v1.30.2-eks"}}}]}`,
+		"/api/v1/nodes?n2": `{"kind": "NodeList", "metadata": {}, "items": [
+			{"metadata": {"name": "gke-pool-1"}, "spec": {"providerID": "gce://shop/us-central1-a/gke-pool-1"}}]}`,
+		"/api/v1/services?": `{"kind": "ServiceList", "metadata": {}, "items": [
+			{"metadata": {"name": "web", "namespace": "shop"}, "spec": {"type": "LoadBalancer", "ports": [{"protocol": "TCP", "port": 443}]},
+				"status": {"loadBalancer": {"ingress": [{"hostname": "a1b2c3-123.us-east-1.elb.amazonaws.com"}]}}},
+			{"metadata": {"name": "db", "namespace": "shop"}, "spec": {"type": "ClusterIP"}}]}`,
+	})
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	kc, err := LoadKubeconfig(writeKubeconfig(t, dir, map[string]*httptest.Server{"prod": server}))
+	if err != nil {
+		t.Fatalf("Failed to load kubeconfig: %v", err)
+	}
+	if _, err := kc.Client("staging"); err == nil {
+		t.Errorf("Expected an error for an unknown context")
+	}
+	c, err := kc.Client("prod")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	kc.Contexts[0].Context.User = "nobody"
+	if _, err := kc.Client("prod"); err == nil {
+		t.Errorf("Expected an error for a context with an unknown user")
+	}
+
+	nodes, err := GetAllNodes(c)
+	if err != nil {
+		t.Fatalf("Failed to list nodes: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Status.NodeInfo.KubeletVersion != "v1.30.2-eks" {
+		t.Errorf("Unexpected nodes %+v", nodes)
+	}
+	if id := InstanceID(nodes[0].Spec.ProviderID); id != "i-0123456789abcdef0" {
+		t.Errorf("Expected the EC2 instance ID, got %q", id)
+	}
+	if id := InstanceID(nodes[1].Spec.ProviderID); id != "" {
+		t.Errorf("Expected no EC2 instance ID for a GCE node, got %q", id)
+	}
+
+	services, err := GetAllLoadBalancerServices(c)
+	if err != nil {
+		t.Fatalf("Failed to list services: %v", err)
+	}
+	if len(services) != 1 || services[0].Status.LoadBalancer.Hostnames()[0] != "a1b2c3-123.us-east-1.elb.amazonaws.com" {
+		t.Errorf("Unexpected services %+v", services)
+	}
+
+	_, err = GetAllIngresses(c)
+	if err == nil || err.Error() != "Forbidden: cannot list resource at /apis/networking.k8s.io/v1/ingresses" {
+		t.Errorf("Expected the API server error, got %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package openstacklib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adobe/cloudinventory/httplib"
+	"gopkg.in/yaml.v2"
+)
+
+// Cloud is a cloud of a clouds.yaml file
+type Cloud struct {
+	Auth struct {
+		AuthURL                     string `yaml:"auth_url"`
+		Username                    string `yaml:"username"`
+		Password                    string `yaml:"password"`
+		UserDomainName              string `yaml:"user_domain_name"`
+		UserDomainID                string `yaml:"user_domain_id"`
+		ProjectName                 string `yaml:"project_name"`
+		ProjectID                   string `yaml:"project_id"`
+		ProjectDomainName           string `yaml:"project_domain_name"`
+		ProjectDomainID             string `yaml:"project_domain_id"`
+		ApplicationCredentialID     string `yaml:"application_credential_id"`
+		ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+	} `yaml:"auth"`
+	// RegionName limits the inventory to a region, all the regions of the catalog being collected if empty
+	RegionName string `yaml:"region_name"`
+	// Interface defaults to public
+	Interface string `yaml:"interface"`
+}
+
+// cloudsFiles returns the clouds.yaml files searched, in order
+func cloudsFiles() []string {
+	if path := os.Getenv("OS_CLIENT_CONFIG_FILE"); path != "" {
+		return []string{path}
+	}
+	files := []string{"clouds.yaml"}
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".config", "openstack", "clouds.yaml"))
+	}
+	return append(files, "/etc/openstack/clouds.yaml")
+}
+
+// LoadCloud returns the named cloud of the first clouds.yaml file found, or the cloud named by OS_CLOUD if empty
+func LoadCloud(name string) (*Cloud, error) {
+	if name == "" {
+		name = os.Getenv("OS_CLOUD")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("No cloud given and OS_CLOUD is not set")
+	}
+	for _, path := range cloudsFiles() {
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return ParseClouds(data, name)
+	}
+	return nil, fmt.Errorf("No clouds.yaml found")
+}
+
+// ParseClouds returns the named cloud of the content of a clouds.yaml file
+func ParseClouds(data []byte, name string) (*Cloud, error) {
+	var file struct {
+		Clouds map[string]*Cloud `yaml:"clouds"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("Invalid clouds.yaml: %v", err)
+	}
+	cloud, ok := file.Clouds[name]
+	if !ok {
+		return nil, fmt.Errorf("Cloud %s not found in clouds.yaml", name)
+	}
+	return cloud, nil
+}
+
+// Client is an OpenStack API client authenticated against Keystone v3
+type Client struct {
+	Cloud      *Cloud
+	HTTPClient *http.Client
+
+	tokens httplib.TokenCache
+	// mu guards the catalog, renewed along with the token
+	mu      sync.Mutex
+	catalog []catalogEntry
+}
+
+// catalogEntry is a service of the Keystone catalog
+type catalogEntry struct {
+	Type      string
+	Endpoints []struct {
+		Interface string
+		Region    string
+		RegionID  string `json:"region_id"`
+		URL       string
+	}
+}
+
+// NewClient returns a client authenticated with the credentials of the cloud
+func NewClient(cloud *Cloud) (*Client, error) {
+	c := &Client{Cloud: cloud, HTTPClient: http.DefaultClient}
+	if _, err := c.authenticate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// authenticate returns a cached token, or requests a new one along with the service catalog
+func (c *Client) authenticate() (string, error) {
+	return c.tokens.Token(c.requestToken)
+}
+
+// requestToken requests a token from Keystone and stores the service catalog it comes with
+func (c *Client) requestToken() (string, time.Time, error) {
+	auth := c.Cloud.Auth
+	identity := map[string]interface{}{}
+	request := map[string]interface{}{"identity": identity}
+	if auth.ApplicationCredentialID != "" {
+		identity["methods"] = []string{"application_credential"}
+		identity["application_credential"] = map[string]string{"id": auth.ApplicationCredentialID, "secret": auth.ApplicationCredentialSecret}
+	} else {
+		identity["methods"] = []string{"password"}
+		identity["password"] = map[string]interface{}{"user": map[string]interface{}{
+			"name": auth.Username, "password": auth.Password, "domain": domain(auth.UserDomainName, auth.UserDomainID),
+		}}
+		project := map[string]interface{}{"domain": domain(auth.ProjectDomainName, auth.ProjectDomainID)}
+		if auth.ProjectID != "" {
+			project = map[string]interface{}{"id": auth.ProjectID}
+		} else {
+			project["name"] = auth.ProjectName
+		}
+		request["scope"] = map[string]interface{}{"project": project}
+	}
+	body, err := json.Marshal(map[string]interface{}{"auth": request})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	authURL := strings.TrimSuffix(auth.AuthURL, "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+	resp, err := c.HTTPClient.Post(authURL+"/auth/tokens", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("Failed to authenticate against Keystone: %s", errorMessage(resp.Status, data))
+	}
+	var result struct {
+		Token struct {
+			ExpiresAt time.Time `json:"expires_at"`
+			Catalog   []catalogEntry
+		}
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", time.Time{}, fmt.Errorf("Invalid Keystone token: %v", err)
+	}
+	c.mu.Lock()
+	c.catalog = result.Token.Catalog
+	c.mu.Unlock()
+	return resp.Header.Get("X-Subject-Token"), result.Token.ExpiresAt, nil
+}
+
+// domain returns a Keystone domain reference, defaulting to the Default domain
+func domain(name, id string) map[string]string {
+	if id != "" {
+		return map[string]string{"id": id}
+	}
+	if name == "" {
+		name = "Default"
+	}
+	return map[string]string{"name": name}
+}
+
+// Regions returns the regions of the catalog offering compute, or the region of the cloud if set
+func (c *Client) Regions() []string {
+	if c.Cloud.RegionName != "" {
+		return []string{c.Cloud.RegionName}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var regions []string
+	seen := make(map[string]bool)
+	for _, s := range c.catalog {
+		if s.Type != "compute" {
+			continue
+		}
+		for _, e := range s.Endpoints {
+			region := e.RegionID
+			if region == "" {
+				region = e.Region
+			}
+			if !seen[region] {
+				seen[region] = true
+				regions = append(regions, region)
+			}
+		}
+	}
+	return regions
+}
+
+// endpoint returns the URL of the first of the service types found in the catalog for the region,
+// or an empty string if the region does not offer the service
+func (c *Client) endpoint(region string, serviceTypes ...string) string {
+	iface := c.Cloud.Interface
+	if iface == "" {
+		iface = "public"
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, serviceType := range serviceTypes {
+		for _, s := range c.catalog {
+			if s.Type != serviceType {
+				continue
+			}
+			for _, e := range s.Endpoints {
+				if e.Interface == iface && (e.RegionID == region || e.Region == region) {
+					return strings.TrimSuffix(e.URL, "/")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// errorMessage extracts the message of an OpenStack error body, which every service nests differently
+func errorMessage(status string, body []byte) string {
+	var top map[string]json.RawMessage
+	if json.Unmarshal(body, &top) != nil {
+		return status
+	}
+	for _, key := range []string{"message", "faultstring", "description"} {
+		var message string
+		if json.Unmarshal(top[key], &message) == nil && message != "" {
+			return message
+		}
+	}
+	// Nova and Neutron wrap the message, e.g {"itemNotFound": {"message": ...}}
+	for _, raw := range top {
+		var nested struct{ Message string }
+		if json.Unmarshal(raw, &nested) == nil && nested.Message != "" {
+			return nested.Message
+		}
+	}
+	return status
+}
+
+// get decodes the JSON response of a GET request, throttled requests being retried by httplib.Get
+func (c *Client) get(rawURL string, out interface{}) error {
+	body, err := httplib.Get(c.HTTPClient, rawURL, func(req *http.Request) error {
+		token, err := c.authenticate()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("X-Auth-Token", token)
+		return nil
+	})
+	if serr, ok := err.(*httplib.StatusError); ok {
+		return fmt.Errorf("%s", errorMessage(serr.Status, serr.Body))
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// list appends every item of a collection to items, following the next links.
+// Most services link the next page in <key>_links, Designate in links.next
+func (c *Client) list(rawURL, key string, items interface{}) error {
+	var all []json.RawMessage
+	for rawURL != "" {
+		var page map[string]json.RawMessage
+		if err := c.get(rawURL, &page); err != nil {
+			return err
+		}
+		var chunk []json.RawMessage
+		if err := json.Unmarshal(page[key], &chunk); err != nil {
+			return fmt.Errorf("Invalid %s page: %v", key, err)
+		}
+		all = append(all, chunk...)
+
+		rawURL = ""
+		var links []struct{ Rel, Href string }
+		if json.Unmarshal(page[key+"_links"], &links) == nil {
+			for _, l := range links {
+				if l.Rel == "next" {
+					rawURL = l.Href
+				}
+			}
+		}
+		var designate struct{ Next string }
+		if json.Unmarshal(page["links"], &designate) == nil && designate.Next != "" {
+			rawURL = designate.Next
+		}
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, items)
+}
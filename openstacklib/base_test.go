@@ -0,0 +1,145 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package openstacklib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const cloudsYAML = `
+clouds:
+  edge:
+    auth:
+      auth_url: {server}/identity
+      username: inventory
+      password: secret
+      project_name: edge
+      user_domain_name: Default
+      project_domain_name: Default
+    region_name: RegionOne
+`
+
+// newStandIn returns a stand-in for Keystone and the services of its catalog, serving the given pages.
+// RegionOne offers compute, network and DNS but no load balancing. Retries and token caching are left to the
+// httplib tests
+func newStandIn(t *testing.T, pages map[string]string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/identity/v3/auth/tokens" {
+			var body struct {
+				Auth struct {
+					Identity struct {
+						Password struct {
+							User struct{ Name, Password string }
+						}
+					}
+					Scope struct {
+						Project struct{ Name string }
+					}
+				}
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Auth.Identity.Password.User.Password != "secret" || body.Auth.Scope.Project.Name != "edge" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error": {"code": 401, "message": "The request you have made requires authentication.", "title": "Unauthorized"}}`)
+				return
+			}
+			w.Header().Set("X-Subject-Token", "token")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, strings.Replace(`{"token": {"expires_at": "2099-01-01T00:00:00.000000Z", "catalog": [
+				{"type": "compute", "endpoints": [{"interface": "public", "region_id": "RegionOne", "url": "{server}/compute/v2.1"},
+					{"interface": "internal", "region_id": "RegionOne", "url": "http://internal/compute/v2.1"}]},
+				{"type": "network", "endpoints": [{"interface": "public", "region_id": "RegionOne", "url": "{server}/network/"}]},
+				{"type": "dns", "endpoints": [{"interface": "public", "region_id": "RegionOne", "url": "{server}/dns"}]}]}}`, "{server}", server.URL, -1))
+			return
+		}
+		if r.Header.Get("X-Auth-Token") != "token" {
+			t.Errorf("Missing token on %s", r.URL)
+		}
+		page, ok := pages[r.URL.String()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"itemNotFound": {"code": 404, "message": "The resource could not be found."}}`)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(page, "{server}", server.URL, -1))
+	}))
+	return server
+}
+
+func TestList(t *testing.T) {
+	server := newStandIn(t, map[string]string{
+		"/compute/v2.1/servers/detail": `{"servers": [{"id": "s1", "name": "edge-1", "status": "ACTIVE", "flavor": {"original_name": "m1.small"}, "image": ""}],
+			"servers_links": [{"rel": "next", "href": "{server}/compute/v2.1/servers/detail?marker=s1"}]}`,
+		"/compute/v2.1/servers/detail?marker=s1": `{"servers": [{"id": "s2", "name": "edge-2", "status": "SHUTOFF", "image": {"id": "cirros"}}]}`,
+		"/dns/v2/zones":                          `{"zones": [{"id": "z1", "name": "edge.example.com."}], "links": {"self": "{server}/dns/v2/zones", "next": "{server}/dns/v2/zones?marker=z1"}}`,
+		"/dns/v2/zones?marker=z1":                `{"zones": [{"id": "z2", "name": "lab.example.com."}], "links": {"self": "{server}/dns/v2/zones?marker=z1"}}`,
+	})
+	defer server.Close()
+
+	cloud, err := ParseClouds([]byte(strings.Replace(cloudsYAML, "{server}", server.URL, -1)), "edge")
+	if err != nil {
+		t.Fatalf("Failed to parse clouds.yaml: %v", err)
+	}
+	if _, err := ParseClouds([]byte(cloudsYAML), "core"); err == nil {
+		t.Errorf("Expected an error for an unknown cloud")
+	}
+	c, err := NewClient(cloud)
+	if err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	if regions := c.Regions(); len(regions) != 1 || regions[0] != "RegionOne" {
+		t.Errorf("Unexpected regions %v", regions)
+	}
+
+	servers, err := GetAllServers(c, "RegionOne")
+	if err != nil {
+		t.Fatalf("Failed to list servers: %v", err)
+	}
+	if len(servers) != 2 || servers[0].Flavor.OriginalName != "m1.small" || servers[1].Status != "SHUTOFF" {
+		t.Errorf("Unexpected servers %+v", servers)
+	}
+	zones, err := GetAllZones(c, "RegionOne")
+	if err != nil {
+		t.Fatalf("Failed to list zones: %v", err)
+	}
+	if len(zones) != 2 || zones[1].Name != "lab.example.com." {
+		t.Errorf("Unexpected zones %+v", zones)
+	}
+	if lbs, err := GetAllLoadBalancers(c, "RegionOne"); lbs != nil || err != nil {
+		t.Errorf("Expected no load balancers without a load-balancer endpoint, got %v %v", lbs, err)
+	}
+	_, err = GetAllFloatingIPs(c, "RegionOne")
+	if err == nil || err.Error() != "The resource could not be found." {
+		t.Errorf("Expected the Neutron error, got %v", err)
+	}
+}
+
+func TestAuthenticationError(t *testing.T) {
+	server := newStandIn(t, nil)
+	defer server.Close()
+
+	cloud, err := ParseClouds([]byte(strings.Replace(cloudsYAML, "{server}", server.URL, -1)), "edge")
+	if err != nil {
+		t.Fatalf("Failed to parse clouds.yaml: %v", err)
+	}
+	cloud.Auth.Password = "wrong"
+	if _, err := NewClient(cloud); err == nil || !strings.Contains(err.Error(), "requires authentication") {
+		t.Errorf("Expected the Keystone error, got %v", err)
+	}
+}
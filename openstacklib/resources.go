@@ -0,0 +1,163 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package openstacklib
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Server is a Nova server
+type Server struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Status           string            `json:"status"`
+	Created          string            `json:"created"`
+	TenantID         string            `json:"tenant_id"`
+	AvailabilityZone string            `json:"OS-EXT-AZ:availability_zone,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	Flavor           struct {
+		ID           string `json:"id,omitempty"`
+		OriginalName string `json:"original_name,omitempty"`
+	} `json:"flavor"`
+	// Image is empty for servers booted from a volume
+	Image     json.RawMessage `json:"image,omitempty"`
+	Addresses map[string][]struct {
+		Addr    string `json:"addr"`
+		Type    string `json:"OS-EXT-IPS:type,omitempty"`
+		Version int    `json:"version"`
+	} `json:"addresses,omitempty"`
+	VolumesAttached []struct {
+		ID string `json:"id"`
+	} `json:"os-extended-volumes:volumes_attached,omitempty"`
+}
+
+// Volume is a Cinder volume
+type Volume struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Status           string `json:"status"`
+	Size             int    `json:"size"`
+	VolumeType       string `json:"volume_type"`
+	Bootable         string `json:"bootable"`
+	Encrypted        bool   `json:"encrypted"`
+	AvailabilityZone string `json:"availability_zone"`
+	CreatedAt        string `json:"created_at"`
+	Attachments      []struct {
+		ServerID string `json:"server_id"`
+		Device   string `json:"device"`
+	} `json:"attachments,omitempty"`
+}
+
+// FloatingIP is a Neutron floating IP
+type FloatingIP struct {
+	ID                string `json:"id"`
+	FloatingIPAddress string `json:"floating_ip_address"`
+	FixedIPAddress    string `json:"fixed_ip_address,omitempty"`
+	PortID            string `json:"port_id,omitempty"`
+	RouterID          string `json:"router_id,omitempty"`
+	FloatingNetworkID string `json:"floating_network_id"`
+	Status            string `json:"status"`
+}
+
+// LoadBalancer is an Octavia load balancer
+type LoadBalancer struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	VipAddress         string `json:"vip_address"`
+	VipPortID          string `json:"vip_port_id"`
+	Provider           string `json:"provider"`
+	ProvisioningStatus string `json:"provisioning_status"`
+	OperatingStatus    string `json:"operating_status"`
+	Listeners          []struct {
+		ID string `json:"id"`
+	} `json:"listeners,omitempty"`
+	Pools []struct {
+		ID string `json:"id"`
+	} `json:"pools,omitempty"`
+}
+
+// Zone is a Designate DNS zone
+type Zone struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	TTL    int    `json:"ttl"`
+	Serial int64  `json:"serial"`
+}
+
+// versioned returns the endpoint with the API version appended unless already part of it
+func versioned(endpoint, version string) string {
+	if endpoint == "" || strings.HasSuffix(endpoint, "/"+version) {
+		return endpoint
+	}
+	return endpoint + "/" + version
+}
+
+// GetAllServers returns a complete list of Nova servers for a given region, nil if the region has no compute endpoint
+func GetAllServers(c *Client, region string) ([]*Server, error) {
+	endpoint := c.endpoint(region, "compute")
+	if endpoint == "" {
+		return nil, nil
+	}
+	var servers []*Server
+	err := c.list(endpoint+"/servers/detail", "servers", &servers)
+	return servers, err
+}
+
+// GetAllVolumes returns a complete list of Cinder volumes for a given region, nil if the region has no block storage endpoint
+func GetAllVolumes(c *Client, region string) ([]*Volume, error) {
+	endpoint := c.endpoint(region, "block-storage", "volumev3")
+	if endpoint == "" {
+		return nil, nil
+	}
+	var volumes []*Volume
+	err := c.list(endpoint+"/volumes/detail", "volumes", &volumes)
+	return volumes, err
+}
+
+// GetAllFloatingIPs returns a complete list of Neutron floating IPs for a given region, nil if the region has no network endpoint
+func GetAllFloatingIPs(c *Client, region string) ([]*FloatingIP, error) {
+	endpoint := versioned(c.endpoint(region, "network"), "v2.0")
+	if endpoint == "" {
+		return nil, nil
+	}
+	var ips []*FloatingIP
+	err := c.list(endpoint+"/floatingips", "floatingips", &ips)
+	return ips, err
+}
+
+// GetAllLoadBalancers returns a complete list of Octavia load balancers for a given region, nil if the region has no
+// load balancer endpoint
+func GetAllLoadBalancers(c *Client, region string) ([]*LoadBalancer, error) {
+	endpoint := versioned(c.endpoint(region, "load-balancer"), "v2")
+	if endpoint == "" {
+		return nil, nil
+	}
+	var lbs []*LoadBalancer
+	err := c.list(endpoint+"/lbaas/loadbalancers", "loadbalancers", &lbs)
+	return lbs, err
+}
+
+// GetAllZones returns a complete list of Designate zones for a given region, nil if the region has no DNS endpoint
+func GetAllZones(c *Client, region string) ([]*Zone, error) {
+	endpoint := versioned(c.endpoint(region, "dns"), "v2")
+	if endpoint == "" {
+		return nil, nil
+	}
+	var zones []*Zone
+	err := c.list(endpoint+"/zones", "zones", &zones)
+	return zones, err
+}
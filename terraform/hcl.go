@@ -0,0 +1,168 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package terraform
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// reference is an HCL expression written as is, such as a provider reference
+type reference string
+
+// Write writes the provider, import and skeleton resource blocks of the given resources as HCL.
+// A provider alias is declared for every region, global resources use the default provider
+func Write(w io.Writer, resources []Resource) error {
+	var b strings.Builder
+	b.WriteString("# Generated by cloudinventory. Review the skeleton declarations until terraform plan shows no change\n")
+
+	regions := make(map[string]bool)
+	for _, r := range resources {
+		if r.Region != "" {
+			regions[r.Region] = true
+		}
+	}
+	var sorted []string
+	for region := range regions {
+		sorted = append(sorted, region)
+	}
+	sort.Strings(sorted)
+	for _, region := range sorted {
+		b.WriteString("\nprovider \"aws\" {\n")
+		writeArguments(&b, 1, []Argument{{"alias", providerAlias(region)}, {"region", region}})
+		b.WriteString("}\n")
+	}
+
+	for _, r := range resources {
+		var provider []Argument
+		if r.Region != "" {
+			provider = []Argument{{"provider", reference("aws." + providerAlias(r.Region))}}
+		}
+		b.WriteString("\nimport {\n")
+		writeArguments(&b, 1, append(provider, Argument{"to", reference(r.Type + "." + r.Name)}, Argument{"id", r.ID}))
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\nresource %s %s {\n", quote(r.Type), quote(r.Name))
+		if provider != nil {
+			writeArguments(&b, 1, provider)
+			b.WriteString("\n")
+		}
+		writeArguments(&b, 1, r.Arguments)
+		b.WriteString("}\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// providerAlias returns the alias of the provider of a region, such as us_east_1
+func providerAlias(region string) string {
+	return strings.Replace(region, "-", "_", -1)
+}
+
+// writeArguments writes arguments at the given indentation level, aligning the equal signs of consecutive
+// single line arguments like terraform fmt. Maps and nested blocks are set apart by blank lines
+func writeArguments(b *strings.Builder, level int, arguments []Argument) {
+	indent := strings.Repeat("  ", level)
+	for i := 0; i < len(arguments); i++ {
+		a := arguments[i]
+		switch v := a.Value.(type) {
+		case []Argument:
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(b, "%s%s {\n", indent, a.Name)
+			writeArguments(b, level+1, v)
+			fmt.Fprintf(b, "%s}\n", indent)
+			continue
+		case map[string]string:
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(b, "%s%s = {\n", indent, a.Name)
+			var keys []string
+			width := 0
+			for k := range v {
+				keys = append(keys, k)
+				if len(quoteKey(k)) > width {
+					width = len(quoteKey(k))
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(b, "%s  %-*s = %s\n", indent, width, quoteKey(k), quote(v[k]))
+			}
+			fmt.Fprintf(b, "%s}\n", indent)
+			continue
+		}
+
+		// Align the run of single line arguments starting here
+		end := i
+		width := 0
+		for ; end < len(arguments) && singleLine(arguments[end].Value); end++ {
+			if len(arguments[end].Name) > width {
+				width = len(arguments[end].Name)
+			}
+		}
+		for ; i < end; i++ {
+			fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, arguments[i].Name, value(arguments[i].Value))
+		}
+		i--
+	}
+}
+
+func singleLine(v interface{}) bool {
+	switch v.(type) {
+	case []Argument, map[string]string:
+		return false
+	}
+	return true
+}
+
+// value renders a single line argument value
+func value(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return quote(v)
+	case reference:
+		return string(v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = quote(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quote returns an HCL string literal, escaping template sequences
+func quote(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r", "\t", "\\t", "${", "$${", "%{", "%%{")
+	return "\"" + r.Replace(s) + "\""
+}
+
+// quoteKey returns a map key, quoted unless it is a valid identifier
+func quoteKey(k string) string {
+	if k == "" {
+		return quote(k)
+	}
+	for i, r := range k {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || i > 0 && (r >= '0' && r <= '9' || r == '-')) {
+			return quote(k)
+		}
+	}
+	return k
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// stateVersion is the only Terraform state format supported, used since Terraform 0.12
+const stateVersion = 4
+
+// State holds the identifiers of the resources managed by one or more Terraform states, keyed by resource type
+type State struct {
+	managed map[string]map[string]bool
+}
+
+// NewState returns a state managing no resource
+func NewState() *State {
+	return &State{managed: make(map[string]map[string]bool)}
+}
+
+// Load adds the managed resources of a state file to the state
+func (s *State) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Parse(data); err != nil {
+		return fmt.Errorf("Invalid state %s: %v", path, err)
+	}
+	return nil
+}
+
+// Parse adds the managed resources of a JSON encoded state to the state.
+// Resources are identified by their id, arn and identifier attributes
+func (s *State) Parse(data []byte) error {
+	var doc struct {
+		Version   int
+		Resources []struct {
+			Mode      string
+			Type      string
+			Instances []struct {
+				Attributes map[string]interface{}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Version != stateVersion {
+		return fmt.Errorf("Unsupported state version %d", doc.Version)
+	}
+	for _, r := range doc.Resources {
+		if r.Mode != "managed" {
+			continue
+		}
+		for _, i := range r.Instances {
+			for _, attribute := range []string{"id", "arn", "identifier"} {
+				if id, ok := i.Attributes[attribute].(string); ok && id != "" {
+					s.add(r.Type, id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *State) add(resourceType, id string) {
+	if s.managed[resourceType] == nil {
+		s.managed[resourceType] = make(map[string]bool)
+	}
+	s.managed[resourceType][strings.ToLower(id)] = true
+}
+
+// Manages reports whether a resource of the given type and import ID is in the state
+func (s *State) Manages(resourceType, id string) bool {
+	return s.managed[resourceType][strings.ToLower(id)]
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package terraform
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/adobe/cloudinventory/collector"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Resource is an inventory resource to import, along with the arguments of its skeleton declaration.
+// Region is empty for global resources, which use the default provider
+type Resource struct {
+	Type      string
+	Name      string
+	ID        string
+	Region    string
+	Arguments []Argument
+}
+
+// Argument is an argument or a nested block of a resource declaration. Values are strings, bools, int64s,
+// string slices, string maps, or []Argument for nested blocks
+type Argument struct {
+	Name  string
+	Value interface{}
+}
+
+// Imports returns the EC2 instances, RDS instances, load balancers, Route53 zones and Route53 records of an
+// inventory not managed by the given state, sorted by type and name
+func Imports(inv collector.AWSInventory, state *State) []Resource {
+	var resources []Resource
+	names := make(map[string]bool)
+	add := func(r Resource, label string) {
+		if state != nil && state.Manages(r.Type, r.ID) {
+			return
+		}
+		r.Name = uniqueName(names, r.Type, label)
+		resources = append(resources, r)
+	}
+
+	for region, instances := range inv.EC2 {
+		for _, i := range instances {
+			// Terminated instances cannot be imported
+			if i.State != nil && aws.StringValue(i.State.Name) == ec2.InstanceStateNameTerminated {
+				continue
+			}
+			id := aws.StringValue(i.InstanceId)
+			label := id
+			tags := make(map[string]string)
+			for _, t := range i.Tags {
+				tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			if tags["Name"] != "" {
+				label = tags["Name"]
+			}
+			var groups []string
+			for _, sg := range i.SecurityGroups {
+				groups = append(groups, aws.StringValue(sg.GroupId))
+			}
+			add(Resource{Type: "aws_instance", ID: id, Region: region, Arguments: compact([]Argument{
+				{"ami", aws.StringValue(i.ImageId)},
+				{"instance_type", aws.StringValue(i.InstanceType)},
+				{"subnet_id", aws.StringValue(i.SubnetId)},
+				{"vpc_security_group_ids", groups},
+				{"key_name", aws.StringValue(i.KeyName)},
+				{"tags", userTags(tags)},
+			})}, label)
+		}
+	}
+
+	for region, dbs := range inv.RDS {
+		for _, db := range dbs {
+			// Aurora members are declared as cluster instances
+			if aws.StringValue(db.DBClusterIdentifier) != "" {
+				continue
+			}
+			id := aws.StringValue(db.DBInstanceIdentifier)
+			tags := make(map[string]string)
+			for _, t := range db.TagList {
+				tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			var subnetGroup string
+			if db.DBSubnetGroup != nil {
+				subnetGroup = aws.StringValue(db.DBSubnetGroup.DBSubnetGroupName)
+			}
+			add(Resource{Type: "aws_db_instance", ID: id, Region: region, Arguments: compact([]Argument{
+				{"identifier", id},
+				{"engine", aws.StringValue(db.Engine)},
+				{"engine_version", aws.StringValue(db.EngineVersion)},
+				{"instance_class", aws.StringValue(db.DBInstanceClass)},
+				{"allocated_storage", aws.Int64Value(db.AllocatedStorage)},
+				{"storage_type", aws.StringValue(db.StorageType)},
+				{"multi_az", aws.BoolValue(db.MultiAZ)},
+				{"publicly_accessible", aws.BoolValue(db.PubliclyAccessible)},
+				{"db_subnet_group_name", subnetGroup},
+				{"tags", userTags(tags)},
+			})}, id)
+		}
+	}
+
+	if inv.LoadBalancers != nil {
+		for region, lbs := range inv.LoadBalancers.Classic {
+			for _, lb := range lbs {
+				name := aws.StringValue(lb.LoadBalancerName)
+				tags := make(map[string]string)
+				for _, t := range lb.Tags {
+					tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+				}
+				arguments := []Argument{
+					{"name", name},
+					{"internal", aws.StringValue(lb.Scheme) == "internal"},
+					{"subnets", aws.StringValueSlice(lb.Subnets)},
+					{"security_groups", aws.StringValueSlice(lb.SecurityGroups)},
+				}
+				// Subnets and availability zones are exclusive
+				if len(lb.Subnets) == 0 {
+					arguments = append(arguments, Argument{"availability_zones", aws.StringValueSlice(lb.AvailabilityZones)})
+				}
+				for _, l := range lb.ListenerDescriptions {
+					if l.Listener == nil {
+						continue
+					}
+					arguments = append(arguments, Argument{"listener", compact([]Argument{
+						{"instance_port", aws.Int64Value(l.Listener.InstancePort)},
+						{"instance_protocol", strings.ToLower(aws.StringValue(l.Listener.InstanceProtocol))},
+						{"lb_port", aws.Int64Value(l.Listener.LoadBalancerPort)},
+						{"lb_protocol", strings.ToLower(aws.StringValue(l.Listener.Protocol))},
+						{"ssl_certificate_id", aws.StringValue(l.Listener.SSLCertificateId)},
+					})})
+				}
+				arguments = append(arguments, Argument{"tags", userTags(tags)})
+				add(Resource{Type: "aws_elb", ID: name, Region: region, Arguments: compact(arguments)}, name)
+			}
+		}
+		for region, lbs := range inv.LoadBalancers.ApplicationNetwork {
+			for _, lb := range lbs {
+				tags := make(map[string]string)
+				for _, t := range lb.Tags {
+					tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+				}
+				var subnets []string
+				for _, az := range lb.AvailabilityZones {
+					subnets = append(subnets, aws.StringValue(az.SubnetId))
+				}
+				add(Resource{Type: "aws_lb", ID: aws.StringValue(lb.LoadBalancerArn), Region: region, Arguments: compact([]Argument{
+					{"name", aws.StringValue(lb.LoadBalancerName)},
+					{"internal", aws.StringValue(lb.Scheme) == "internal"},
+					{"load_balancer_type", aws.StringValue(lb.Type)},
+					{"subnets", subnets},
+					{"security_groups", aws.StringValueSlice(lb.SecurityGroups)},
+					{"tags", userTags(tags)},
+				})}, aws.StringValue(lb.LoadBalancerName))
+			}
+		}
+	}
+
+	for _, zone := range inv.HostedZones {
+		zoneID := strings.TrimPrefix(aws.StringValue(zone.Id), "/hostedzone/")
+		zoneName := strings.TrimSuffix(aws.StringValue(zone.Name), ".")
+		arguments := []Argument{{"name", zoneName}}
+		if zone.Config != nil {
+			arguments = append(arguments, Argument{"comment", aws.StringValue(zone.Config.Comment)})
+		}
+		add(Resource{Type: "aws_route53_zone", ID: zoneID, Arguments: compact(arguments)}, zoneName)
+
+		for _, r := range zone.Records {
+			name := strings.TrimSuffix(unescapeDNS(aws.StringValue(r.Name)), ".")
+			recordType := aws.StringValue(r.Type)
+			// The apex NS and SOA records are created along with the zone
+			if name == zoneName && (recordType == "NS" || recordType == "SOA") {
+				continue
+			}
+			id := zoneID + "_" + name + "_" + recordType
+			if aws.StringValue(r.SetIdentifier) != "" {
+				id += "_" + aws.StringValue(r.SetIdentifier)
+			}
+			arguments := []Argument{
+				{"zone_id", zoneID},
+				{"name", name},
+				{"type", recordType},
+				{"set_identifier", aws.StringValue(r.SetIdentifier)},
+			}
+			if r.AliasTarget != nil {
+				arguments = append(arguments, Argument{"alias", []Argument{
+					{"name", aws.StringValue(r.AliasTarget.DNSName)},
+					{"zone_id", aws.StringValue(r.AliasTarget.HostedZoneId)},
+					{"evaluate_target_health", aws.BoolValue(r.AliasTarget.EvaluateTargetHealth)},
+				}})
+			} else {
+				var values []string
+				for _, rr := range r.ResourceRecords {
+					values = append(values, aws.StringValue(rr.Value))
+				}
+				arguments = append(arguments, Argument{"ttl", aws.Int64Value(r.TTL)}, Argument{"records", values})
+			}
+			add(Resource{Type: "aws_route53_record", ID: id, Arguments: compact(arguments)}, name+"_"+recordType)
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	return resources
+}
+
+// compact drops the arguments left empty
+func compact(arguments []Argument) []Argument {
+	var kept []Argument
+	for _, a := range arguments {
+		switch v := a.Value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		case map[string]string:
+			if len(v) == 0 {
+				continue
+			}
+		}
+		kept = append(kept, a)
+	}
+	return kept
+}
+
+// userTags drops the tags reserved to AWS, which cannot be set
+func userTags(tags map[string]string) map[string]string {
+	for k := range tags {
+		if strings.HasPrefix(k, "aws:") {
+			delete(tags, k)
+		}
+	}
+	return tags
+}
+
+// unescapeDNS restores the wildcard Route53 escapes in record names
+func unescapeDNS(name string) string {
+	return strings.Replace(name, "\\052", "*", -1)
+}
+
+// uniqueName turns a label into a resource name unique for its type
+func uniqueName(names map[string]bool, resourceType, label string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(label) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	name := b.String()
+	// Names must start with a letter or an underscore
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "_" + name
+	}
+	unique := name
+	for i := 2; names[resourceType+"."+unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	names[resourceType+"."+unique] = true
+	return unique
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2019 Adobe. All rights reserved.
+This file is licensed to you under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License. You may obtain a copy
+of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed under
+the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+OF ANY KIND, either express or implied. See the License for the specific language
+governing permissions and limitations under the License.
+*/
+
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/adobe/cloudinventory/collector"
+)
+
+const testInventory = `{
+	"ec2": {"us-east-1": [
+		{"InstanceId": "i-web", "ImageId": "ami-1", "InstanceType": "t3.micro", "SubnetId": "subnet-1", "State": {"Name": "running"},
+			"SecurityGroups": [{"GroupId": "sg-1"}], "Tags": [{"Key": "Name", "Value": "web 1"}, {"Key": "aws:autoscaling:groupName", "Value": "web"}]},
+		{"InstanceId": "i-managed", "State": {"Name": "running"}},
+		{"InstanceId": "i-gone", "State": {"Name": "terminated"}}
+	]},
+	"rds": {"eu-west-1": [
+		{"DBInstanceIdentifier": "orders", "Engine": "postgres", "DBInstanceClass": "db.t3.micro", "AllocatedStorage": 20},
+		{"DBInstanceIdentifier": "aurora-1", "DBClusterIdentifier": "aurora"}
+	]},
+	"hostedzones": [{"Id": "/hostedzone/Z1", "Name": "example.com.", "Config": {"PrivateZone": false}, "Records": [
+		{"Name": "example.com.", "Type": "NS", "TTL": 172800, "ResourceRecords": [{"Value": "ns-1.awsdns-01.org."}]},
+		{"Name": "\\052.example.com.", "Type": "CNAME", "TTL": 300, "ResourceRecords": [{"Value": "${host}.example.com"}]},
+		{"Name": "www.example.com.", "Type": "A", "AliasTarget": {"DNSName": "web-1.us-east-1.elb.amazonaws.com.", "HostedZoneId": "Z35SXDOTRQ7X7K", "EvaluateTargetHealth": true}}
+	]}]
+}`
+
+const testState = `{
+	"version": 4,
+	"resources": [
+		{"mode": "managed", "type": "aws_instance", "name": "managed", "instances": [{"attributes": {"id": "i-managed"}}]},
+		{"mode": "managed", "type": "aws_route53_zone", "name": "example", "instances": [{"attributes": {"id": "Z1"}}]},
+		{"mode": "data", "type": "aws_db_instance", "name": "orders", "instances": [{"attributes": {"id": "orders"}}]}
+	]
+}`
+
+const expectedHCL = `# Generated by cloudinventory. Review the skeleton declarations until terraform plan shows no change
+
+provider "aws" {
+  alias  = "eu_west_1"
+  region = "eu-west-1"
+}
+
+provider "aws" {
+  alias  = "us_east_1"
+  region = "us-east-1"
+}
+
+import {
+  provider = aws.eu_west_1
+  to       = aws_db_instance.orders
+  id       = "orders"
+}
+
+resource "aws_db_instance" "orders" {
+  provider = aws.eu_west_1
+
+  identifier          = "orders"
+  engine              = "postgres"
+  instance_class      = "db.t3.micro"
+  allocated_storage   = 20
+  multi_az            = false
+  publicly_accessible = false
+}
+
+import {
+  provider = aws.us_east_1
+  to       = aws_instance.web_1
+  id       = "i-web"
+}
+
+resource "aws_instance" "web_1" {
+  provider = aws.us_east_1
+
+  ami                    = "ami-1"
+  instance_type          = "t3.micro"
+  subnet_id              = "subnet-1"
+  vpc_security_group_ids = ["sg-1"]
+
+  tags = {
+    Name = "web 1"
+  }
+}
+
+import {
+  to = aws_route53_record.__example_com_cname
+  id = "Z1_*.example.com_CNAME"
+}
+
+resource "aws_route53_record" "__example_com_cname" {
+  zone_id = "Z1"
+  name    = "*.example.com"
+  type    = "CNAME"
+  ttl     = 300
+  records = ["$${host}.example.com"]
+}
+
+import {
+  to = aws_route53_record.www_example_com_a
+  id = "Z1_www.example.com_A"
+}
+
+resource "aws_route53_record" "www_example_com_a" {
+  zone_id = "Z1"
+  name    = "www.example.com"
+  type    = "A"
+
+  alias {
+    name                   = "web-1.us-east-1.elb.amazonaws.com."
+    zone_id                = "Z35SXDOTRQ7X7K"
+    evaluate_target_health = true
+  }
+}
+`
+
+func TestImports(t *testing.T) {
+	var inv collector.AWSInventory
+	if err := json.Unmarshal([]byte(testInventory), &inv); err != nil {
+		t.Fatalf("Unable to decode inventory: %v", err)
+	}
+	state := NewState()
+	if err := state.Parse([]byte(testState)); err != nil {
+		t.Fatalf("Unable to parse state: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, Imports(inv, state)); err != nil {
+		t.Fatalf("Failed to write imports: %v", err)
+	}
+	if buf.String() != expectedHCL {
+		t.Errorf("Unexpected imports:\n%s", buf.String())
+	}
+}
+
+func TestParseStateVersion(t *testing.T) {
+	if err := NewState().Parse([]byte(`{"version": 3}`)); err == nil {
+		t.Errorf("Expected an error for a version 3 state")
+	}
+}